@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/config"
+)
+
+var configOpts struct {
+	daemon    bool // Operate on histuid.toml instead of config.toml
+	effective bool // show: print the merged configuration
+	defaults  bool // show: print the commented defaults
+	dryRun    bool // migrate: only list changes
+}
+
+// configCmd represents the config command group.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show, validate and edit configuration files",
+	Long: `Show, validate and edit the histui configuration files.
+
+histui reads ~/.config/histui/config.toml; histuid reads
+~/.config/histui/histuid.toml. Subcommands operate on config.toml unless
+--daemon is given (validate checks both).
+
+  histui config show                 # The file as written
+  histui config show --effective     # Every option, with defaults filled in
+  histui config show --defaults      # Commented defaults (redirect to create a file)
+  histui config validate             # Check both files, with line numbers
+  histui config diff --daemon        # Options changed from the defaults
+  histui config edit                 # Edit in $EDITOR, validated before saving
+  histui config migrate              # Rewrite outdated options`,
+	// The config may be invalid; these commands load it themselves.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupLogger()
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print a configuration file",
+	Long: `Print a configuration file.
+
+Without flags the file is printed as written, or the commented defaults if it
+does not exist. --effective prints every option with defaults filled in, and
+--defaults prints the commented defaults.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check both configuration files for errors",
+	Long: `Check config.toml and histuid.toml for errors.
+
+Unknown keys, wrongly typed values and invalid settings are reported with the
+line they appear on. Missing files are skipped. Exits with a non-zero status
+if any problem is found.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show options that differ from the defaults",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigDiff,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit a configuration file in $EDITOR",
+	Long: `Open a copy of the configuration file in $VISUAL or $EDITOR (default vi).
+
+The edited copy is validated before it replaces the file. If it has problems
+they are listed and you can edit again or give up; nothing is saved until the
+copy is valid. A missing file starts from the commented defaults.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite outdated options in a configuration file",
+	Long: `Rewrite options that use an outdated format.
+
+Older formats are still accepted when loading, but may be removed in a future
+release. The original file is kept as <file>.bak. Comments are not preserved
+in the rewritten file. Use --dry-run to list the changes only.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd, configValidateCmd, configDiffCmd, configEditCmd, configMigrateCmd)
+
+	configCmd.PersistentFlags().BoolVar(&configOpts.daemon, "daemon", false,
+		"Use the histuid config (histuid.toml)")
+	configShowCmd.Flags().BoolVar(&configOpts.effective, "effective", false,
+		"Print every option with defaults filled in")
+	configShowCmd.Flags().BoolVar(&configOpts.defaults, "defaults", false,
+		"Print the commented default configuration")
+	configShowCmd.MarkFlagsMutuallyExclusive("effective", "defaults")
+	configMigrateCmd.Flags().BoolVar(&configOpts.dryRun, "dry-run", false,
+		"List the changes without writing")
+}
+
+// configFile describes one of the two configuration files.
+type configFile struct {
+	name     string
+	path     string
+	defaults func() ([]byte, error)
+	check    func([]byte) []config.Problem
+	migrate  func([]byte) ([]byte, []string, error)
+}
+
+func histuiConfigFile() configFile {
+	path := globalOpts.configPath
+	if path == "" {
+		path = config.ConfigPath()
+	}
+	return configFile{
+		name:     "config.toml",
+		path:     path,
+		defaults: config.DefaultConfigTOML,
+		check:    config.CheckConfig,
+		migrate:  config.MigrateConfig,
+	}
+}
+
+func daemonConfigFile() (configFile, error) {
+	path, err := config.DaemonConfigPath()
+	if err != nil {
+		return configFile{}, err
+	}
+	return configFile{
+		name:     "histuid.toml",
+		path:     path,
+		defaults: config.DefaultDaemonConfigTOML,
+		check:    config.CheckDaemonConfig,
+		migrate:  config.MigrateDaemonConfig,
+	}, nil
+}
+
+// selectedConfigFile returns the file chosen by --daemon.
+func selectedConfigFile() (configFile, error) {
+	if configOpts.daemon {
+		return daemonConfigFile()
+	}
+	return histuiConfigFile(), nil
+}
+
+// read returns the file contents, or nil if it does not exist.
+func (f configFile) read() ([]byte, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// write replaces the file atomically, creating its directory if needed.
+func (f configFile) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, f.path)
+}
+
+// effective returns the loaded configuration with defaults filled in.
+func (f configFile) effective() (any, error) {
+	if f.name == "histuid.toml" {
+		return config.LoadDaemonConfig()
+	}
+	return config.LoadConfig(f.path)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	f, err := selectedConfigFile()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch {
+	case configOpts.defaults:
+		data, err = f.defaults()
+	case configOpts.effective:
+		var cfg any
+		if cfg, err = f.effective(); err != nil {
+			return fmt.Errorf("%s: %w (run 'histui config validate')", f.path, err)
+		}
+		data, err = config.MarshalCommented(cfg)
+	default:
+		data, err = f.read()
+		if err == nil && data == nil {
+			fmt.Fprintf(os.Stderr, "%s does not exist; showing defaults\n", f.path)
+			data, err = f.defaults()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	daemonFile, err := daemonConfigFile()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, f := range []configFile{histuiConfigFile(), daemonFile} {
+		data, err := f.read()
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			fmt.Printf("%s: not found (defaults are used)\n", f.path)
+			continue
+		}
+
+		problems := f.check(data)
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok\n", f.path)
+			continue
+		}
+		failed++
+		for _, p := range problems {
+			fmt.Printf("%s:%s\n", f.path, p)
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d configuration file(s) have problems", failed)
+	}
+	return nil
+}
+
+func runConfigDiff(cmd *cobra.Command, args []string) error {
+	f, err := selectedConfigFile()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := f.effective()
+	if err != nil {
+		return fmt.Errorf("%s: %w (run 'histui config validate')", f.path, err)
+	}
+
+	var defaults any = config.DefaultConfig()
+	if configOpts.daemon {
+		defaults = config.DefaultDaemonConfig()
+	}
+
+	changes, err := config.Diff(defaults, cfg)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Printf("%s: all options are at their defaults\n", f.path)
+		return nil
+	}
+
+	return writeConfigDiff(os.Stdout, changes)
+}
+
+// writeConfigDiff writes changes as an aligned table.
+func writeConfigDiff(w io.Writer, changes []config.Change) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "KEY\tDEFAULT\tVALUE\n")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Key, orDash(c.Default), orDash(c.Value))
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	f, err := selectedConfigFile()
+	if err != nil {
+		return err
+	}
+
+	original, err := f.read()
+	if err != nil {
+		return err
+	}
+	initial := original
+	if initial == nil {
+		if initial, err = f.defaults(); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "histui-*-"+f.name)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(initial)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	for {
+		if err := runEditor(tmpPath); err != nil {
+			return fmt.Errorf("editor failed: %w (your edits are in %s)", err, tmpPath)
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return err
+		}
+		if original != nil && bytes.Equal(edited, original) {
+			_ = os.Remove(tmpPath)
+			fmt.Printf("%s: no changes\n", f.path)
+			return nil
+		}
+
+		problems := f.check(edited)
+		if len(problems) == 0 {
+			if err := f.write(edited); err != nil {
+				return fmt.Errorf("failed to save %s: %w (your edits are in %s)", f.path, err, tmpPath)
+			}
+			_ = os.Remove(tmpPath)
+			fmt.Printf("%s: saved\n", f.path)
+			return nil
+		}
+
+		for _, p := range problems {
+			fmt.Printf("%s:%s\n", f.name, p)
+		}
+		fmt.Print("Edit again? [Y/n] ")
+		answer, _ := stdin.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a == "n" || a == "no" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%s not saved (your edits are in %s)", f.path, tmpPath)
+		}
+	}
+}
+
+// runEditor opens path in $VISUAL, $EDITOR or vi, attached to the terminal.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Allow editors with arguments, e.g. EDITOR="code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	f, err := selectedConfigFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := f.read()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		fmt.Printf("%s: not found, nothing to migrate\n", f.path)
+		return nil
+	}
+
+	migrated, changes, err := f.migrate(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("%s: already up to date\n", f.path)
+		return nil
+	}
+
+	for _, c := range changes {
+		fmt.Printf("%s: %s\n", f.path, c)
+	}
+	if configOpts.dryRun {
+		return nil
+	}
+
+	backupPath := f.path + ".bak"
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := f.write(migrated); err != nil {
+		return err
+	}
+	fmt.Printf("%s: migrated (original saved as %s)\n", f.path, backupPath)
+	return nil
+}
@@ -12,7 +12,8 @@ import (
 )
 
 var dndOpts struct {
-	quiet bool // Suppress output, return exit code only
+	quiet    bool          // Suppress output, return exit code only
+	duration time.Duration // How long "dnd on" lasts (0 = until turned off)
 }
 
 // dndCmd represents the dnd command group.
@@ -25,7 +26,7 @@ When DnD is enabled, histuid suppresses notification popups and sounds
 while still persisting notifications to the history store.
 
 Use 'histui dnd status' to check the current state.
-Use 'histui dnd on' to enable DnD mode.
+Use 'histui dnd on' to enable DnD mode ('--for 1h' turns it off again later).
 Use 'histui dnd off' to disable DnD mode.
 Use 'histui dnd toggle' to toggle DnD mode.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -38,8 +39,11 @@ Use 'histui dnd toggle' to toggle DnD mode.`,
 var dndOnCmd = &cobra.Command{
 	Use:   "on",
 	Short: "Enable Do Not Disturb mode",
-	Long:  `Enable Do Not Disturb mode. Notification popups and sounds will be suppressed.`,
-	RunE:  dndOnRun,
+	Long: `Enable Do Not Disturb mode. Notification popups and sounds will be suppressed.
+
+With --for, DnD ends by itself after the duration:
+  histui dnd on --for 90m`,
+	RunE: dndOnRun,
 }
 
 // dndOffCmd disables DnD mode.
@@ -79,6 +83,9 @@ func init() {
 			"Suppress output, return exit code only (0=off, 1=on)")
 	}
 
+	dndOnCmd.Flags().DurationVar(&dndOpts.duration, "for", 0,
+		"Turn DnD off again after this long (e.g. 30m, 2h)")
+
 	// Add to root
 	rootCmd.AddCommand(dndCmd)
 }
@@ -92,7 +99,11 @@ func dndOnRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	state.SetDnD(true, store.DnDTriggerUser, "dnd on", "cli", "")
+	if dndOpts.duration > 0 {
+		state.SetDnDUntil(time.Now().Add(dndOpts.duration), store.DnDTriggerUser, "dnd on", "cli", "")
+	} else {
+		state.SetDnD(true, store.DnDTriggerUser, "dnd on", "cli", "")
+	}
 	if err := store.SaveSharedState(state); err != nil {
 		if !dndOpts.quiet {
 			fmt.Fprintf(os.Stderr, "Failed to save state: %v\n", err)
@@ -101,7 +112,11 @@ func dndOnRun(cmd *cobra.Command, args []string) error {
 	}
 
 	if !dndOpts.quiet {
-		fmt.Println("Do Not Disturb: enabled")
+		if state.DnDUntil > 0 {
+			fmt.Printf("Do Not Disturb: enabled until %s\n", time.Unix(state.DnDUntil, 0).Format("15:04"))
+		} else {
+			fmt.Println("Do Not Disturb: enabled")
+		}
 	}
 
 	// Exit code 1 means DnD is now on
@@ -175,10 +190,14 @@ func dndStatusRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	active := state.IsDnDActive(time.Now())
 	if !dndOpts.quiet {
-		if state.DnDEnabled {
+		switch {
+		case active && state.DnDUntil > 0:
+			fmt.Printf("Do Not Disturb: enabled until %s\n", time.Unix(state.DnDUntil, 0).Format("15:04"))
+		case active:
 			fmt.Println("Do Not Disturb: enabled")
-		} else {
+		default:
 			fmt.Println("Do Not Disturb: disabled")
 		}
 
@@ -203,7 +222,7 @@ func dndStatusRun(cmd *cobra.Command, args []string) error {
 	}
 
 	// Exit code: 0=off, 1=on
-	if state.DnDEnabled {
+	if active {
 		os.Exit(1)
 	}
 	return nil
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/adapter/input"
+	"github.com/jmylchreest/histui/internal/clipboard"
+	"github.com/jmylchreest/histui/internal/config"
+	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/layout"
+	"github.com/jmylchreest/histui/internal/picker"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+var doctorOpts struct {
+	format string
+	fix    bool
+}
+
+// checkStatus is the outcome of a single doctor check.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn"
+	checkFail checkStatus = "fail"
+)
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+	Hint    string      `json:"hint,omitempty"`
+}
+
+// doctorReport is the full doctor output.
+type doctorReport struct {
+	Version string        `json:"version"`
+	Checks  []doctorCheck `json:"checks"`
+	Pass    int           `json:"pass"`
+	Warn    int           `json:"warn"`
+	Fail    int           `json:"fail"`
+}
+
+func (r *doctorReport) add(name string, status checkStatus, hint, format string, args ...any) {
+	r.Checks = append(r.Checks, doctorCheck{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+		Hint:    hint,
+	})
+	switch status {
+	case checkPass:
+		r.Pass++
+	case checkWarn:
+		r.Warn++
+	case checkFail:
+		r.Fail++
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the histui environment for problems",
+	Long: `Check the environment histui runs in and report problems.
+
+Checks which process owns org.freedesktop.Notifications, whether histuid and
+the daemon CLIs are available, whether the config files validate, whether the
+history and state files are readable, and which clipboard tool and launcher
+will be used. Each check reports pass, warn or fail.
+
+The history file is scanned without modifying it. With --fix, corrupt lines
+are removed after backing up the original file.
+
+Exits with a non-zero status if any check fails.
+
+Examples:
+  # Human-readable report
+  histui doctor
+
+  # JSON for bug reports
+  histui doctor --format json`,
+	// The config may be invalid; doctor loads it itself instead of failing
+	// before any check runs.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupLogger()
+		return nil
+	},
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringVarP(&doctorOpts.format, "format", "f", "text",
+		"Output format (text, json)")
+	doctorCmd.Flags().BoolVar(&doctorOpts.fix, "fix", false,
+		"Remove corrupt lines from the history file (a backup is kept)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(doctorOpts.format)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %q (use text, json)", doctorOpts.format)
+	}
+
+	report := &doctorReport{Version: version}
+
+	appCfg := checkConfig(report)
+	checkDaemonConfig(report)
+	checkLayouts(report)
+	serverName := checkNotificationServer(report)
+	checkControl(report, serverName)
+	checkInputAdapter(report, serverName)
+	checkHistory(report, appCfg, doctorOpts.fix)
+	checkTombstones(report)
+	checkState(report)
+	checkClipboard(report, appCfg)
+	checkLauncher(report, appCfg)
+
+	var err error
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeDoctorReport(os.Stdout, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Fail > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d check(s) failed", report.Fail)
+	}
+	return nil
+}
+
+// writeDoctorReport writes the report as an aligned table.
+func writeDoctorReport(w io.Writer, report *doctorReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range report.Checks {
+		fmt.Fprintf(tw, "[%s]\t%s\t%s\n", strings.ToUpper(string(c.Status)), c.Name, c.Message)
+		if c.Hint != "" {
+			fmt.Fprintf(tw, "\t\t→ %s\n", c.Hint)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", report.Pass, report.Warn, report.Fail)
+	return err
+}
+
+// checkConfig validates the histui config. Returns the loaded config, or the
+// defaults if it could not be loaded.
+func checkConfig(report *doctorReport) *config.Config {
+	path := globalOpts.configPath
+	if path == "" {
+		path = config.ConfigPath()
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		report.add("config", checkPass, "", "%s not found, using defaults", path)
+		return config.DefaultConfig()
+	}
+
+	loaded, err := config.LoadConfig(path)
+	if err != nil {
+		report.add("config", checkFail, "fix the file or move it aside to use the defaults", "%s: %v", path, err)
+		return config.DefaultConfig()
+	}
+	report.add("config", checkPass, "", "%s is valid", path)
+	return loaded
+}
+
+// checkDaemonConfig validates the histuid config.
+func checkDaemonConfig(report *doctorReport) {
+	path, err := config.DaemonConfigPath()
+	if err != nil {
+		report.add("daemon config", checkFail, "", "%v", err)
+		return
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		report.add("daemon config", checkPass, "", "%s not found, using defaults", path)
+		return
+	}
+
+	// LoadDaemonConfig runs DaemonConfig.Validate after parsing
+	if _, err := config.LoadDaemonConfig(); err != nil {
+		report.add("daemon config", checkFail, "histuid refuses to start or reload with this file", "%s: %v", path, err)
+		return
+	}
+	report.add("daemon config", checkPass, "", "%s is valid", path)
+}
+
+// checkLayouts validates the user's popup layout templates.
+func checkLayouts(report *doctorReport) {
+	dir, err := layout.LayoutsDir()
+	if err != nil {
+		report.add("layouts", checkWarn, "", "%v", err)
+		return
+	}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		report.add("layouts", checkPass, "", "no custom layouts")
+		return
+	}
+
+	if err := layout.NewLoader(dir).Validate(); err != nil {
+		report.add("layouts", checkFail, "popups using these layouts fall back to the default", "%v", err)
+		return
+	}
+	report.add("layouts", checkPass, "", "%s is valid", dir)
+}
+
+// checkNotificationServer reports which process owns the notification bus
+// name. Returns the server's reported name, or "" if none.
+func checkNotificationServer(report *doctorReport) string {
+	conn, err := godbus.SessionBus()
+	if err != nil {
+		report.add("notification server", checkFail, "is DBUS_SESSION_BUS_ADDRESS set?", "cannot connect to the session bus: %v", err)
+		return ""
+	}
+
+	owner, err := dbus.LookupNameOwner(conn, dbus.DBusBusName)
+	if err != nil {
+		report.add("notification server", checkFail, "", "%v", err)
+		return ""
+	}
+	if !owner.Owned() {
+		report.add("notification server", checkFail, "start histuid or another notification daemon",
+			"nothing owns %s", dbus.DBusBusName)
+		return ""
+	}
+
+	process := owner.UniqueName
+	if owner.PID > 0 {
+		process = fmt.Sprintf("pid %d", owner.PID)
+		if owner.ProcessName != "" {
+			process = fmt.Sprintf("%s, pid %d", owner.ProcessName, owner.PID)
+		}
+	}
+
+	info, err := dbus.QueryServerInformation(conn)
+	if err != nil {
+		report.add("notification server", checkWarn, "", "%s is owned by %s but did not answer: %v",
+			dbus.DBusBusName, process, err)
+		return owner.ProcessName
+	}
+
+	report.add("notification server", checkPass, "", "%s %s (%s, spec %s)",
+		info.Name, info.Version, process, info.SpecVersion)
+	return info.Name
+}
+
+// checkControl reports whether histuid's control interface is available.
+func checkControl(report *doctorReport, serverName string) {
+	conn, err := godbus.SessionBus()
+	if err != nil {
+		report.add("histuid control", checkWarn, "", "cannot connect to the session bus")
+		return
+	}
+
+	owner, err := dbus.LookupNameOwner(conn, dbus.ControlBusName)
+	switch {
+	case err != nil:
+		report.add("histuid control", checkWarn, "", "%v", err)
+	case owner.Owned():
+		report.add("histuid control", checkPass, "", "%s is available", dbus.ControlBusName)
+	case serverName == "histuid":
+		report.add("histuid control", checkWarn, "restart histuid to enable invoking actions from histui",
+			"histuid is running without %s", dbus.ControlBusName)
+	default:
+		report.add("histuid control", checkPass, "", "histuid is not running")
+	}
+}
+
+// checkInputAdapter reports which daemon CLI histui can import history from.
+func checkInputAdapter(report *doctorReport, serverName string) {
+	source := input.DetectDaemon()
+	switch {
+	case source == "dunst":
+		path, _ := exec.LookPath("dunstctl")
+		report.add("history source", checkPass, "", "dunst (%s)", path)
+	case serverName == "histuid":
+		report.add("history source", checkPass, "", "histuid writes history directly")
+	default:
+		report.add("history source", checkWarn, "install dunst or run histuid",
+			"no supported daemon CLI on PATH (dunstctl); only stored history is available")
+	}
+}
+
+// checkHistory scans the history file for corrupt lines without modifying it,
+// unless fix is set. Encrypted files are scanned record by record.
+func checkHistory(report *doctorReport, appCfg *config.Config, fix bool) {
+	path := globalOpts.historyFile
+	if path == "" {
+		path = config.HistoryPath()
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		report.add("history", checkWarn, "", "%s does not exist yet", path)
+		return
+	}
+	if err == nil && info.Mode().Perm()&0077 != 0 {
+		report.add("history permissions", checkWarn, "run any histui command to restrict it to 0600",
+			"%s is readable by other users (%04o)", path, info.Mode().Perm())
+	}
+
+	key, ok := checkEncryption(report, appCfg, path)
+	if !ok {
+		return
+	}
+
+	scan, err := store.RecoverFromCorruption(path, key, true)
+	if err != nil {
+		report.add("history", checkFail, "", "%s: %v", path, err)
+		return
+	}
+	if scan.Corrupt == 0 {
+		report.add("history", checkPass, "", "%s: %d notifications", path, scan.Valid)
+		return
+	}
+
+	if fix {
+		fixed, err := store.RecoverFromCorruption(path, key, false)
+		if err != nil {
+			report.add("history", checkFail, "", "%s: recovery failed: %v", path, err)
+			return
+		}
+		report.add("history", checkPass, "", "%s: removed %d corrupt line(s), kept %d notifications (backup: %s)",
+			path, fixed.Corrupt, fixed.Valid, fixed.BackupPath)
+		return
+	}
+
+	report.add("history", checkFail, "run 'histui doctor --fix' to remove them (a backup is kept)",
+		"%s: %d corrupt line(s) at %s; %d notifications readable",
+		path, scan.Corrupt, formatLineNumbers(scan.CorruptLines, scan.Corrupt), scan.Valid)
+}
+
+// checkEncryption reports whether the history is encrypted and whether the
+// configured key can decrypt it. Returns the key (nil if none is configured)
+// and whether the history can be read.
+func checkEncryption(report *doctorReport, appCfg *config.Config, path string) (*store.HistoryKey, bool) {
+	key, source, err := appCfg.Encryption.HistoryKey()
+	if err != nil {
+		report.add("encryption", checkFail, "fix [encryption] in config.toml or $"+config.HistoryKeyEnv, "%v", err)
+		return nil, false
+	}
+
+	scheme, err := store.FileEncryption(path, key)
+	switch {
+	case errors.Is(err, store.ErrWrongHistoryKey):
+		report.add("encryption", checkFail, "configure the key the history was encrypted with",
+			"history is encrypted (%s) but the key from %s does not match", scheme, source)
+		return nil, false
+	case err != nil:
+		report.add("encryption", checkFail, "", "%s: %v", path, err)
+		return nil, false
+	case scheme == "" && key == nil:
+		report.add("encryption", checkPass, "", "history is not encrypted")
+	case scheme == "":
+		report.add("encryption", checkWarn, "run 'histui store encrypt'",
+			"a key is configured (%s) but the history is not encrypted", source)
+	case key == nil:
+		report.add("encryption", checkFail, "configure the key in [encryption] or $"+config.HistoryKeyEnv,
+			"history is encrypted (%s) but no key is configured", scheme)
+		return nil, false
+	default:
+		report.add("encryption", checkPass, "", "history is encrypted (%s), key from %s", scheme, source)
+	}
+	return key, true
+}
+
+// formatLineNumbers formats reported line numbers, noting any not listed.
+func formatLineNumbers(lines []int, total int) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = fmt.Sprintf("%d", line)
+	}
+	s := "line " + strings.Join(parts, ", ")
+	if total > len(lines) {
+		s += fmt.Sprintf(" and %d more", total-len(lines))
+	}
+	return s
+}
+
+// checkTombstones checks that the tombstones file is readable.
+func checkTombstones(report *doctorReport) {
+	path := config.TombstonePath()
+	tombstones, err := store.NewTombstoneFile(path).Load()
+	if err != nil {
+		report.add("tombstones", checkWarn, "deleted notifications may be re-imported", "%s: %v", path, err)
+		return
+	}
+	report.add("tombstones", checkPass, "", "%d deleted notifications tracked", len(tombstones))
+}
+
+// checkState checks that state.json is readable. LoadSharedState silently
+// falls back to defaults on corruption, so the file is parsed directly.
+func checkState(report *doctorReport) {
+	path, err := store.StateFilePath()
+	if err != nil {
+		report.add("state", checkFail, "", "%v", err)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		report.add("state", checkPass, "", "%s not created yet", path)
+		return
+	}
+	if err != nil {
+		report.add("state", checkFail, "", "%s: %v", path, err)
+		return
+	}
+
+	var state store.SharedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		report.add("state", checkFail, "delete the file to reset Do Not Disturb state", "%s is corrupt: %v", path, err)
+		return
+	}
+
+	dnd := "off"
+	if state.IsDnDActive(time.Now()) {
+		dnd = "on"
+	}
+	report.add("state", checkPass, "", "%s: Do Not Disturb %s", path, dnd)
+}
+
+// checkClipboard reports the clipboard command copy actions will use.
+func checkClipboard(report *doctorReport, appCfg *config.Config) {
+	command := clipboard.DetectCommand(appCfg)
+	if command == "" {
+		report.add("clipboard", checkWarn, "install wl-clipboard (Wayland) or xclip/xsel (X11)", "no clipboard tool found")
+		return
+	}
+
+	executable := strings.Fields(command)[0]
+	if _, err := exec.LookPath(executable); err != nil {
+		report.add("clipboard", checkFail, "fix [clipboard] command in the config", "configured command %q not found", executable)
+		return
+	}
+	report.add("clipboard", checkPass, "", "%s", command)
+}
+
+// checkLauncher reports the launcher 'histui pick' will use.
+func checkLauncher(report *doctorReport, appCfg *config.Config) {
+	launcher, err := picker.New(appCfg.Picker.Launcher, appCfg.Picker.Command)
+	if err != nil {
+		report.add("launcher", checkWarn, "only needed for 'histui pick'", "%v", err)
+		return
+	}
+	if _, err := exec.LookPath(launcher.Command); err != nil {
+		report.add("launcher", checkWarn, "only needed for 'histui pick'", "%s: %q not found", launcher.Name, launcher.Command)
+		return
+	}
+	report.add("launcher", checkPass, "", "%s", launcher.Name)
+}
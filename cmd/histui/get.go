@@ -13,6 +13,7 @@ import (
 	"github.com/jmylchreest/histui/internal/adapter/input"
 	"github.com/jmylchreest/histui/internal/adapter/output"
 	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -22,11 +23,14 @@ var getOpts struct {
 
 	// Filter options
 	since   string
+	until   string
 	app     string
 	urgency string
 	limit   int
 	search  string
 	filter  string // Expression-based filter
+	view    string // Named view from config
+	snoozed bool   // Only pending snoozes from the history store
 
 	// Sort options
 	sortBy    string
@@ -36,6 +40,7 @@ var getOpts struct {
 	format   string
 	field    string
 	template string
+	markup   string
 
 	// Lookup options
 	index int
@@ -52,6 +57,8 @@ fuzzel, walker, rofi, etc.).
 
 With an index (1-based) or ID argument, outputs that specific notification.
 
+Notifications snoozed until later are hidden; --snoozed lists them.
+
 Examples:
   # List all notifications in dmenu format
   histui get
@@ -59,16 +66,38 @@ Examples:
   # Filter by app and time
   histui get --app firefox --since 1h
 
+  # Absolute dates and ranges (local time unless an offset is given)
+  histui get --since monday --until yesterday
+  histui get --filter "timestamp=2026-10-01..2026-10-07"
+
   # Use expression filter
   histui get --filter "app=discord,urgency=critical"
   histui get --filter "body~meeting,dismissed=false"
 
+  # Fuzzy search, best matches first (typos and word order are forgiven)
+  histui get --search "meetign moved" --sort relevance
+
+  # Use a saved view from [views.work] in config.toml
+  histui get --view work
+
+  # List snoozed notifications and reminders histuid will show again
+  histui get --snoozed --format json
+
   # Get specific notification by index
   histui get 3
 
-  # Get notification and output body field
+  # Get notification and output body field (markup stripped)
   histui get 3 --field body
 
+  # Render <b>, <i> and <a href> in the terminal instead
+  histui get 3 --field body --markup ansi
+
+  # Copy the one-time code from the newest notification
+  histui get 1 --field codes | head -n1 | wl-copy
+
+  # Open every link in a notification
+  histui get 3 --field urls | xargs -r -n1 xdg-open
+
   # Output as JSON
   histui get --format json
 
@@ -86,7 +115,9 @@ func init() {
 
 	// Filter flags
 	getCmd.Flags().StringVar(&getOpts.since, "since", "",
-		"Show notifications from the last duration (e.g., 1h, 7d, 1w)")
+		"Show notifications from this time on (e.g., 1h, 7d, today, monday, 2026-10-01)")
+	getCmd.Flags().StringVar(&getOpts.until, "until", "",
+		"Show notifications up to this time (e.g., 2h, yesterday, 2026-10-07 18:00)")
 	getCmd.Flags().StringVar(&getOpts.app, "app", "",
 		"Filter by application name (exact match)")
 	getCmd.Flags().StringVar(&getOpts.urgency, "urgency", "",
@@ -94,13 +125,17 @@ func init() {
 	getCmd.Flags().IntVarP(&getOpts.limit, "limit", "n", 0,
 		"Maximum number of notifications to show (0=unlimited)")
 	getCmd.Flags().StringVarP(&getOpts.search, "search", "s", "",
-		"Search in summary and body")
+		"Fuzzy search in summary, body and app name (--sort relevance ranks the matches)")
 	getCmd.Flags().StringVar(&getOpts.filter, "filter", "",
 		"Expression filter (e.g., 'app=discord,urgency=critical')")
+	getCmd.Flags().StringVar(&getOpts.view, "view", "",
+		"Apply a named view from the config file (flags override its settings)")
+	getCmd.Flags().BoolVar(&getOpts.snoozed, "snoozed", false,
+		"Only show snoozed notifications and reminders waiting to be shown again")
 
 	// Sort flags
 	getCmd.Flags().StringVar(&getOpts.sortBy, "sort", "timestamp",
-		"Sort by field (timestamp, app, urgency)")
+		"Sort by field (timestamp, app, urgency, relevance)")
 	getCmd.Flags().StringVar(&getOpts.sortOrder, "order", "desc",
 		"Sort order (asc, desc)")
 
@@ -108,9 +143,11 @@ func init() {
 	getCmd.Flags().StringVarP(&getOpts.format, "format", "f", "dmenu",
 		"Output format (dmenu, json, plain, ids)")
 	getCmd.Flags().StringVar(&getOpts.field, "field", "",
-		"Output single field from notification (id, app, summary, body, all)")
+		"Output single field from notification (id, app, summary, body, tags, note, urls, emails, codes, all)")
 	getCmd.Flags().StringVar(&getOpts.template, "template", "",
 		"Custom Go template for output formatting")
+	getCmd.Flags().StringVar(&getOpts.markup, "markup", "",
+		"Body markup: raw, strip or ansi (default strip; raw for json)")
 
 	// Lookup flags
 	getCmd.Flags().IntVar(&getOpts.index, "index", 0,
@@ -135,12 +172,46 @@ func runGet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Fetch notifications
-	notifications, err := fetchNotifications(ctx)
-	if err != nil {
+	if getOpts.markup != "" {
+		if _, err := markup.ParseMode(getOpts.markup); err != nil {
+			return err
+		}
+	}
+
+	// Apply saved view defaults; explicit flags take precedence
+	if getOpts.view != "" {
+		if err := applyGetView(cmd); err != nil {
+			return err
+		}
+	}
+
+	// Validate sorting, including values taken from the view
+	if _, err := core.ParseSortField(getOpts.sortBy); err != nil {
+		return err
+	}
+	if _, err := core.ParseSortOrder(getOpts.sortOrder); err != nil {
 		return err
 	}
 
+	// Validate --since and --until rather than listing everything
+	var timeRange core.FilterOptions
+	if err := timeRange.SetTimeRange(getOpts.since, getOpts.until, time.Now()); err != nil {
+		return err
+	}
+
+	// Fetch notifications; snoozes only exist in the history store
+	var notifications []model.Notification
+	if getOpts.snoozed {
+		notifications = pendingSnoozes()
+	} else {
+		var err error
+		notifications, err = fetchNotifications(ctx)
+		if err != nil {
+			return err
+		}
+		notifications = hideSnoozed(notifications)
+	}
+
 	// If looking up specific notification
 	if getOpts.index > 0 || getOpts.id != "" {
 		return handleLookup(notifications)
@@ -154,6 +225,38 @@ func runGet(cmd *cobra.Command, args []string) error {
 	return outputNotifications(notifications)
 }
 
+// applyGetView copies the named view's settings into getOpts for any flag
+// the user did not set explicitly. The view filter is ANDed with --filter.
+func applyGetView(cmd *cobra.Command) error {
+	view, err := cfg.View(getOpts.view)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if view.Filter != "" {
+		if getOpts.filter != "" {
+			getOpts.filter = view.Filter + "," + getOpts.filter
+		} else {
+			getOpts.filter = view.Filter
+		}
+	}
+	if view.Sort != "" && !flags.Changed("sort") {
+		getOpts.sortBy = view.Sort
+	}
+	if view.Order != "" && !flags.Changed("order") {
+		getOpts.sortOrder = view.Order
+	}
+	if view.Limit > 0 && !flags.Changed("limit") {
+		getOpts.limit = view.Limit
+	}
+	if view.Template != "" && !flags.Changed("template") {
+		getOpts.template = view.Template
+	}
+
+	return nil
+}
+
 // fetchNotifications retrieves notifications from the configured source.
 func fetchNotifications(ctx context.Context) ([]model.Notification, error) {
 	// Determine source
@@ -187,6 +290,29 @@ func fetchNotifications(ctx context.Context) ([]model.Notification, error) {
 	return notifications, nil
 }
 
+// hideSnoozed drops notifications snoozed in the history store until later,
+// as the TUI does; --snoozed lists them instead.
+func hideSnoozed(notifications []model.Notification) []model.Notification {
+	snoozed := make(map[string]bool)
+	for _, n := range historyStore.All() {
+		if n.IsSnoozed() {
+			snoozed[n.ContentHash] = true
+		}
+	}
+	if len(snoozed) == 0 {
+		return notifications
+	}
+
+	kept := notifications[:0]
+	for _, n := range notifications {
+		n.EnsureContentHash()
+		if !snoozed[n.ContentHash] {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
 // applyFilters applies filter options to notifications.
 func applyFilters(notifications []model.Notification) []model.Notification {
 	// Apply expression-based filter if provided
@@ -205,15 +331,8 @@ func applyFilters(notifications []model.Notification) []model.Notification {
 		Limit:     getOpts.limit,
 	}
 
-	// Parse since duration
-	if getOpts.since != "" {
-		d, err := core.ParseDuration(getOpts.since)
-		if err != nil {
-			logger.Warn("invalid since duration", "value", getOpts.since, "error", err)
-		} else {
-			opts.Since = d
-		}
-	}
+	// Parse --since and --until, validated by runGet
+	_ = opts.SetTimeRange(getOpts.since, getOpts.until, time.Now())
 
 	// Parse urgency
 	if getOpts.urgency != "" {
@@ -238,12 +357,14 @@ func applyFilters(notifications []model.Notification) []model.Notification {
 
 // applySort sorts notifications based on options.
 func applySort(notifications []model.Notification) {
+	// Both were validated by runGet
 	field, _ := core.ParseSortField(getOpts.sortBy)
 	order, _ := core.ParseSortOrder(getOpts.sortOrder)
 
 	core.Sort(notifications, core.SortOptions{
 		Field: field,
 		Order: order,
+		Query: getOpts.search,
 	})
 }
 
@@ -270,6 +391,10 @@ func handleLookup(notifications []model.Notification) error {
 
 	// Output specific field if requested
 	if getOpts.field != "" {
+		// Extracted fields read link targets from the markup, so keep it
+		if !output.IsExtractedField(getOpts.field) {
+			n = output.RenderMarkup(n, markupMode())
+		}
 		fmt.Println(output.FormatField(n, getOpts.field))
 		return nil
 	}
@@ -319,6 +444,20 @@ func outputNotifications(notifications []model.Notification) error {
 	return formatter.Format(os.Stdout, notifications)
 }
 
+// markupMode returns the --markup mode, defaulting to plain text for the
+// text formats and markup as received for JSON. The flag is validated by
+// runGet.
+func markupMode() markup.Mode {
+	if getOpts.markup != "" {
+		mode, _ := markup.ParseMode(getOpts.markup)
+		return mode
+	}
+	if strings.EqualFold(getOpts.format, "json") {
+		return markup.ModeRaw
+	}
+	return markup.ModeStrip
+}
+
 // createFormatter creates the output formatter based on options.
 func createFormatter() output.Formatter {
 	var format output.FormatType
@@ -335,6 +474,7 @@ func createFormatter() output.Formatter {
 
 	opts := output.DefaultFormatterOptions()
 	opts.Template = getOpts.template
+	opts.Markup = markupMode()
 
 	// Apply config defaults if available
 	if cfg != nil {
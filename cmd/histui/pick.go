@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/adapter/input"
+	"github.com/jmylchreest/histui/internal/adapter/output"
+	"github.com/jmylchreest/histui/internal/clipboard"
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/icons"
+	"github.com/jmylchreest/histui/internal/markup"
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/picker"
+)
+
+var pickOpts struct {
+	launcher string
+	source   string
+	since    string
+	until    string
+	filter   string
+	view     string
+	limit    int
+	action   string
+}
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Choose a notification with rofi/fuzzel/wofi/dmenu and act on it",
+	Long: `Show notification history in a launcher menu and act on the selection.
+
+The launcher is taken from --launcher, then [picker] launcher in the config
+file, then auto-detected (rofi, fuzzel, wofi, dmenu). rofi and fuzzel show
+application icons.
+
+After choosing a notification a second menu offers:
+  copy           Copy the body to the clipboard as plain text
+  copy-summary   Copy the summary to the clipboard
+  copy-code      Copy a one-time code found in the notification
+  open-url       Open a URL found in the notification
+  invoke         Invoke a notification action (requires histuid)
+  dismiss        Dismiss the notification
+  restore        Restore a dismissed notification
+  delete         Delete the notification permanently
+
+Use --action to skip the second menu.
+
+Examples:
+  # Browse recent history
+  histui pick --since 24h
+
+  # Copy the body of the chosen notification
+  histui pick --action copy
+
+  # Use a saved view and force fuzzel
+  histui pick --view work --launcher fuzzel`,
+	RunE: runPick,
+}
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+
+	pickCmd.Flags().StringVar(&pickOpts.launcher, "launcher", "",
+		"Launcher to use (rofi, fuzzel, wofi, dmenu, custom; auto-detects if empty)")
+	pickCmd.Flags().StringVar(&pickOpts.source, "source", "",
+		"Notification source to import first (dunst, stdin; auto-detects if empty)")
+	pickCmd.Flags().StringVar(&pickOpts.since, "since", "",
+		"Show notifications from this time on (e.g., 1h, 7d, today, 2026-10-01)")
+	pickCmd.Flags().StringVar(&pickOpts.until, "until", "",
+		"Show notifications up to this time (e.g., 2h, yesterday)")
+	pickCmd.Flags().StringVar(&pickOpts.filter, "filter", "",
+		"Expression filter (e.g., 'app=discord,dismissed=false')")
+	pickCmd.Flags().StringVar(&pickOpts.view, "view", "",
+		"Apply a named view from the config file")
+	pickCmd.Flags().IntVarP(&pickOpts.limit, "limit", "n", 0,
+		"Maximum number of notifications to show (0=unlimited)")
+	pickCmd.Flags().StringVar(&pickOpts.action, "action", "",
+		"Action to run without showing the second menu (copy, copy-summary, copy-code, open-url, invoke, dismiss, restore, delete)")
+}
+
+// pickAction is an entry in the second (action) menu.
+type pickAction struct {
+	id    string
+	label string
+	icon  string
+	run   func() error
+}
+
+// pickIconSize is the icon size requested from the theme for launcher rows.
+const pickIconSize = 32
+
+func runPick(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	launcher, err := newPickLauncher()
+	if err != nil {
+		return err
+	}
+
+	importForPick(ctx)
+
+	notifications, template, err := pickNotifications()
+	if err != nil {
+		return err
+	}
+	if len(notifications) == 0 {
+		return fmt.Errorf("no notifications to pick from")
+	}
+
+	opts := output.DefaultFormatterOptions()
+	opts.Template = template
+	formatter := output.NewDmenuFormatter(opts)
+
+	entries := make([]picker.Entry, len(notifications))
+	for i := range notifications {
+		entries[i] = picker.Entry{
+			Label: formatter.FormatLine(i+1, &notifications[i]),
+			Icon:  notificationIcon(&notifications[i]),
+		}
+	}
+
+	idx, err := launcher.Choose(ctx, "histui", entries)
+	if errors.Is(err, picker.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	n := notifications[idx]
+
+	actions := buildPickActions(&n)
+
+	if pickOpts.action != "" {
+		for _, a := range actions {
+			if a.id == pickOpts.action {
+				return a.run()
+			}
+		}
+		return fmt.Errorf("action %q is not available for this notification", pickOpts.action)
+	}
+
+	actionEntries := make([]picker.Entry, len(actions))
+	for i, a := range actions {
+		actionEntries[i] = picker.Entry{Label: a.label, Icon: a.icon}
+	}
+
+	idx, err = launcher.Choose(ctx, n.Summary, actionEntries)
+	if errors.Is(err, picker.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return actions[idx].run()
+}
+
+// newPickLauncher resolves the launcher from flags and config.
+// The configured command only applies to the configured launcher.
+func newPickLauncher() (*picker.Launcher, error) {
+	name, command := pickOpts.launcher, ""
+	if cfg != nil {
+		if name == "" {
+			name = cfg.Picker.Launcher
+		}
+		if name == cfg.Picker.Launcher {
+			command = cfg.Picker.Command
+		}
+	}
+	return picker.New(name, command)
+}
+
+// importForPick refreshes the store from the notification daemon, if any.
+// Failures are logged; the picker still works from stored history.
+func importForPick(ctx context.Context) {
+	source := pickOpts.source
+	if source == "" {
+		source = input.DetectDaemon()
+	}
+	if source == "" {
+		return
+	}
+
+	adapter, err := input.NewAdapter(source)
+	if err != nil {
+		logger.Warn("failed to create input adapter", "source", source, "error", err)
+		return
+	}
+
+	notifications, err := adapter.Import(ctx)
+	if err != nil {
+		logger.Warn("failed to import notifications", "source", source, "error", err)
+		return
+	}
+	if len(notifications) > 0 {
+		_ = historyStore.AddBatch(notifications)
+	}
+}
+
+// pickNotifications returns the filtered and sorted notifications to show,
+// along with the dmenu template to render them with.
+func pickNotifications() ([]model.Notification, string, error) {
+	notifications := historyStore.All()
+
+	sortOpts := core.SortOptions{Field: core.SortByTimestamp, Order: core.SortDesc}
+	limit := pickOpts.limit
+	template := ""
+	if cfg != nil {
+		template = cfg.Templates.Dmenu
+	}
+
+	filter := pickOpts.filter
+	if pickOpts.view != "" {
+		view, err := cfg.View(pickOpts.view)
+		if err != nil {
+			return nil, "", err
+		}
+		if view.Filter != "" {
+			if filter != "" {
+				filter = view.Filter + "," + filter
+			} else {
+				filter = view.Filter
+			}
+		}
+		sortOpts = view.SortOptions()
+		if limit == 0 {
+			limit = view.Limit
+		}
+		if view.Template != "" {
+			template = view.Template
+		}
+	}
+
+	if filter != "" {
+		expr, err := core.ParseFilter(filter)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid filter: %w", err)
+		}
+		notifications = core.FilterWithExpr(notifications, expr)
+	}
+
+	opts := core.FilterOptions{}
+	if err := opts.SetTimeRange(pickOpts.since, pickOpts.until, time.Now()); err != nil {
+		return nil, "", err
+	}
+	notifications = core.Filter(notifications, opts)
+
+	core.Sort(notifications, sortOpts)
+	if limit > 0 && len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+
+	return notifications, template, nil
+}
+
+// notificationIcon returns the icon to show for n in the launcher.
+// Resolved files are preferred; otherwise the raw name is passed through
+// for the launcher's own theme lookup.
+func notificationIcon(n *model.Notification) string {
+	if path := icons.Default().NotificationIcon(n, pickIconSize); path != "" {
+		return path
+	}
+	if n.IconPath != "" {
+		return n.IconPath
+	}
+	if n.Extensions != nil && n.Extensions.DesktopEntry != "" {
+		return n.Extensions.DesktopEntry
+	}
+	return ""
+}
+
+// buildPickActions returns the actions available for n, in menu order.
+func buildPickActions(n *model.Notification) []pickAction {
+	id := n.HistuiID
+	actions := []pickAction{
+		{
+			id: "copy", label: "Copy body", icon: "edit-copy",
+			run: func() error { return copyAndMarkActed(id, markup.Strip(n.Body)) },
+		},
+		{
+			id: "copy-summary", label: "Copy summary", icon: "edit-copy",
+			run: func() error { return copyAndMarkActed(id, n.Summary) },
+		},
+	}
+
+	for _, c := range n.Codes() {
+		code := c
+		actions = append(actions, pickAction{
+			id: "copy-code", label: "Copy code " + code, icon: "edit-copy",
+			run: func() error { return copyAndMarkActed(id, code) },
+		})
+	}
+
+	for _, u := range n.URLs() {
+		url := u
+		actions = append(actions, pickAction{
+			id: "open-url", label: "Open " + url, icon: "web-browser",
+			run: func() error { return exec.Command("xdg-open", url).Run() },
+		})
+	}
+
+	if n.Extensions != nil {
+		for _, a := range n.Extensions.Actions {
+			actionKey := a.Key
+			label := a.Label
+			if label == "" {
+				label = actionKey
+			}
+			actions = append(actions, pickAction{
+				id: "invoke", label: "Invoke: " + label, icon: "system-run",
+				run: func() error {
+					client, err := dbus.NewControlClient()
+					if err != nil {
+						return err
+					}
+					return client.InvokeAction(id, actionKey)
+				},
+			})
+		}
+	}
+
+	if n.IsDismissed() {
+		actions = append(actions, pickAction{
+			id: "restore", label: "Restore", icon: "edit-undo",
+			run: func() error {
+				restored := *n
+				restored.Undismiss()
+				return historyStore.Update(restored)
+			},
+		})
+	} else {
+		actions = append(actions, pickAction{
+			id: "dismiss", label: "Dismiss", icon: "window-close",
+			run: func() error { return historyStore.Dismiss(id) },
+		})
+	}
+
+	actions = append(actions, pickAction{
+		id: "delete", label: "Delete", icon: "edit-delete",
+		run: func() error { return historyStore.DeleteWithTombstone(id) },
+	})
+
+	return actions
+}
+
+// copyAndMarkActed copies text to the clipboard and records the action.
+func copyAndMarkActed(id, text string) error {
+	if err := clipboard.Copy(text, cfg); err != nil {
+		return err
+	}
+	if n := historyStore.GetByID(id); n != nil {
+		acted := *n
+		acted.MarkActed()
+		_ = historyStore.Update(acted)
+	}
+	return nil
+}
@@ -2,18 +2,25 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jmylchreest/histui/internal/config"
 	"github.com/jmylchreest/histui/internal/core"
 	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
 )
 
 var pruneOpts struct {
 	olderThan string
 	keep      int
 	dryRun    bool
+
+	includeStarred bool
+	policy         bool
 }
 
 var pruneCmd = &cobra.Command{
@@ -25,11 +32,36 @@ Examples:
   # Remove notifications older than 7 days
   histui prune --older-than 7d
 
+  # Remove everything from before October (local time)
+  histui prune --older-than 2026-10-01
+
   # Keep only the 100 most recent notifications
   histui prune --keep 100
 
   # Preview what would be removed (dry run)
-  histui prune --older-than 48h --dry-run`,
+  histui prune --older-than 48h --dry-run
+
+  # Preview what histuid's [[retention]] policies will remove
+  histui prune --policy
+
+Starred notifications are never pruned unless --include-starred is given.
+Pending snoozes and reminders are never pruned.
+
+Retention policies are configured in histuid.toml and applied by histuid on
+startup and hourly; --policy only previews their effect:
+
+  [[retention]]
+  name = "critical"
+  filter = "urgency=critical"
+  max_age = "0"            # keep forever
+
+  [[retention]]
+  name = "low"
+  filter = "urgency=low"
+  max_age = "2d"
+
+Each notification is governed by the first matching policy; notifications
+matching no policy are kept, as are pending snoozes.`,
 	RunE: runPrune,
 }
 
@@ -37,16 +69,25 @@ func init() {
 	rootCmd.AddCommand(pruneCmd)
 
 	pruneCmd.Flags().StringVar(&pruneOpts.olderThan, "older-than", "",
-		"Remove notifications older than this duration (e.g., 48h, 7d, 1w)")
+		"Remove notifications from before this time (e.g., 48h, 7d, yesterday, 2026-10-01)")
 	pruneCmd.Flags().IntVar(&pruneOpts.keep, "keep", 0,
 		"Keep only the N most recent notifications (0=unlimited)")
 	pruneCmd.Flags().BoolVar(&pruneOpts.dryRun, "dry-run", false,
 		"Show what would be removed without actually removing")
+	pruneCmd.Flags().BoolVar(&pruneOpts.includeStarred, "include-starred", false,
+		"Also remove starred notifications")
+	pruneCmd.Flags().BoolVar(&pruneOpts.policy, "policy", false,
+		"Preview the effect of the [[retention]] policies in histuid.toml")
+	pruneCmd.MarkFlagsMutuallyExclusive("policy", "older-than")
+	pruneCmd.MarkFlagsMutuallyExclusive("policy", "keep")
 }
 
 func runPrune(cmd *cobra.Command, args []string) error {
+	if pruneOpts.policy {
+		return previewRetention()
+	}
 	if pruneOpts.olderThan == "" && pruneOpts.keep == 0 {
-		return fmt.Errorf("specify --older-than or --keep")
+		return fmt.Errorf("specify --older-than, --keep or --policy")
 	}
 
 	// Get all notifications
@@ -62,46 +103,59 @@ func runPrune(cmd *cobra.Command, args []string) error {
 		Order: core.SortDesc,
 	})
 
-	// Determine which to remove
-	var toRemove []model.Notification
+	// Determine which to remove, in newest-first order
+	remove := make(map[string]bool)
 
 	if pruneOpts.olderThan != "" {
-		duration, err := core.ParseDuration(pruneOpts.olderThan)
+		span, err := core.ParseTimeSpan(pruneOpts.olderThan, time.Now())
 		if err != nil {
-			return fmt.Errorf("invalid duration: %w", err)
+			return fmt.Errorf("invalid --older-than: %w", err)
 		}
 
-		cutoff := time.Now().Add(-duration)
+		// A date keeps that whole day
+		cutoff := span.Start
 		for _, n := range notifications {
-			if time.Unix(n.Timestamp, 0).Before(cutoff) {
-				toRemove = append(toRemove, n)
+			if n.TimestampTime().Before(cutoff) {
+				remove[n.HistuiID] = true
 			}
 		}
 	}
 
-	if pruneOpts.keep > 0 && len(notifications) > pruneOpts.keep {
+	if pruneOpts.keep > 0 {
 		// Remove the oldest ones beyond the keep limit
-		keepSet := make(map[string]bool)
-		for i := 0; i < pruneOpts.keep && i < len(notifications); i++ {
-			keepSet[notifications[i].HistuiID] = true
+		for _, n := range notifications[min(pruneOpts.keep, len(notifications)):] {
+			remove[n.HistuiID] = true
 		}
+	}
 
-		for _, n := range notifications {
-			if !keepSet[n.HistuiID] {
-				// Avoid duplicates if also removed by older-than
-				found := false
-				for _, r := range toRemove {
-					if r.HistuiID == n.HistuiID {
-						found = true
-						break
-					}
-				}
-				if !found {
-					toRemove = append(toRemove, n)
-				}
-			}
+	var toRemove []model.Notification
+	for _, n := range notifications {
+		if remove[n.HistuiID] {
+			toRemove = append(toRemove, n)
+		}
+	}
+
+	// Pending snoozes are exempt, as they are from retention policies, and
+	// starred notifications unless explicitly included
+	kept := toRemove[:0]
+	starred, snoozed := 0, 0
+	for _, n := range toRemove {
+		switch {
+		case n.HasPendingSnooze():
+			snoozed++
+		case n.IsStarred() && !pruneOpts.includeStarred:
+			starred++
+		default:
+			kept = append(kept, n)
 		}
 	}
+	toRemove = kept
+	if starred > 0 {
+		fmt.Printf("Keeping %d starred notification(s)\n", starred)
+	}
+	if snoozed > 0 {
+		fmt.Printf("Keeping %d snoozed notification(s) and reminder(s)\n", snoozed)
+	}
 
 	if len(toRemove) == 0 {
 		fmt.Println("No notifications to remove")
@@ -110,24 +164,87 @@ func runPrune(cmd *cobra.Command, args []string) error {
 
 	if pruneOpts.dryRun {
 		fmt.Printf("Would remove %d notification(s):\n", len(toRemove))
-		for i, n := range toRemove {
-			if i >= 10 {
-				fmt.Printf("  ... and %d more\n", len(toRemove)-10)
-				break
-			}
-			fmt.Printf("  - [%s] %s (%s)\n", n.AppName, n.Summary, n.RelativeTime())
-		}
+		printPruneSample(toRemove)
 		return nil
 	}
 
-	// Actually remove
+	// Actually remove, persisting once; the store compacts the history file
+	// afterwards so it shrinks
 	removed := 0
-	for _, n := range toRemove {
-		if err := historyStore.Delete(n.HistuiID); err == nil {
-			removed++
+	err := historyStore.Batch(func(tx *store.Tx) error {
+		for _, n := range toRemove {
+			if tx.Delete(n.HistuiID) {
+				removed++
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove notifications: %w", err)
 	}
 
 	fmt.Printf("Removed %d notification(s)\n", removed)
 	return nil
 }
+
+// previewRetention shows what histuid's retention policies would remove now.
+func previewRetention() error {
+	daemonCfg, err := config.LoadDaemonConfig()
+	if err != nil {
+		return err
+	}
+	if len(daemonCfg.Retention) == 0 {
+		return fmt.Errorf("no [[retention]] policies configured in histuid.toml")
+	}
+	rules, err := daemonCfg.RetentionRules()
+	if err != nil {
+		return err
+	}
+
+	notifications := historyStore.All()
+	core.Sort(notifications, core.SortOptions{
+		Field: core.SortByTimestamp,
+		Order: core.SortDesc,
+	})
+	result := core.ApplyRetention(notifications, rules, time.Now(), pruneOpts.includeStarred)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "POLICY\tFILTER\tMAX AGE\tREMOVE")
+	for i, p := range daemonCfg.Retention {
+		maxAge := p.MaxAge
+		if rules[i].MaxAge == 0 {
+			maxAge = "forever"
+		}
+		filter := p.Filter
+		if filter == "" {
+			filter = "*"
+		}
+		name := p.Name
+		if name == "" {
+			name = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", name, filter, maxAge, result.ByRule[i])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nWould remove %d of %d notification(s)", len(result.Expired), len(notifications))
+	if result.Exempt > 0 {
+		fmt.Printf(", keeping %d starred or snoozed", result.Exempt)
+	}
+	fmt.Println()
+	printPruneSample(result.Expired)
+	return nil
+}
+
+// printPruneSample lists the first few notifications that would be removed.
+func printPruneSample(notifications []model.Notification) {
+	for i, n := range notifications {
+		if i >= 10 {
+			fmt.Printf("  ... and %d more\n", len(notifications)-10)
+			break
+		}
+		fmt.Printf("  - [%s] %s (%s)\n", n.AppName, n.Summary, n.RelativeTime())
+	}
+}
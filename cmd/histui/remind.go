@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+var remindOpts struct {
+	at      string
+	body    string
+	urgency string
+}
+
+var remindCmd = &cobra.Command{
+	Use:   "remind <text>",
+	Short: "Schedule an ad-hoc reminder notification",
+	Long: `Schedule a reminder that histuid shows as a notification at the given time.
+
+The reminder is stored in the history as a snoozed notification, so it
+survives daemon restarts and is listed by 'histui get --snoozed'. --at
+accepts the same times as 'histui snooze': a duration (30m, 2h), a clock
+time (15:00) or an absolute time (2006-01-02 15:04).
+
+Examples:
+  histui remind "Stand-up" --at 09:55
+  histui remind "Take the bread out" --at 40m
+  histui remind "Submit expenses" --at "2026-03-31 10:00" --urgency critical`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemind,
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+
+	remindCmd.Flags().StringVar(&remindOpts.at, "at", "",
+		"When to show the reminder (e.g., 30m, 15:00, '2006-01-02 15:04')")
+	remindCmd.Flags().StringVar(&remindOpts.body, "body", "",
+		"Optional reminder body text")
+	remindCmd.Flags().StringVar(&remindOpts.urgency, "urgency", "normal",
+		"Reminder urgency (low, normal, critical)")
+	_ = remindCmd.MarkFlagRequired("at")
+}
+
+func runRemind(cmd *cobra.Command, args []string) error {
+	until, err := core.ParseWhen(remindOpts.at, time.Now())
+	if err != nil {
+		return err
+	}
+
+	urgency, err := core.ParseUrgency(remindOpts.urgency)
+	if err != nil {
+		return err
+	}
+
+	n, err := model.NewNotification("histui")
+	if err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+	n.AppName = "Reminder"
+	n.Summary = args[0]
+	n.Body = remindOpts.body
+	n.IconPath = "appointment-soon"
+	n.Timestamp = time.Now().Unix()
+	n.SetUrgency(urgency)
+	n.Snooze(until)
+
+	// Reminders are never re-imported, so key them on their ID: identical
+	// reminders set in the same second are still separate reminders
+	hash := sha256.Sum256([]byte(n.DedupeKey() + ":" + n.HistuiID))
+	n.ContentHash = hex.EncodeToString(hash[:])
+
+	if err := n.Validate(); err != nil {
+		return fmt.Errorf("invalid reminder: %w", err)
+	}
+	if err := historyStore.Add(*n); err != nil {
+		return fmt.Errorf("failed to save reminder: %w", err)
+	}
+	// Add skips duplicates without an error, e.g. of redacted content
+	if historyStore.GetByID(n.HistuiID) == nil {
+		return fmt.Errorf("reminder not saved: an identical reminder for %s was already set", formatWhen(until))
+	}
+
+	fmt.Printf("Reminder set for %s\n", formatWhen(until))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -32,6 +33,9 @@ var (
 	// historyStore is the global store instance
 	historyStore  *store.Store
 	tombstoneFile *store.TombstoneFile
+
+	// historyKey encrypts the history file; nil if no key is configured
+	historyKey *store.HistoryKey
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -67,13 +71,29 @@ Running histui without a subcommand launches the interactive TUI.`,
 			historyPath = config.HistoryPath()
 		}
 
-		persistence, err := store.NewJSONLPersistence(historyPath)
+		// A missing key only matters if the history is encrypted, which
+		// Hydrate reports, so commands that fix the config still run
+		historyKey, _, err = cfg.Encryption.HistoryKey()
+		if err != nil {
+			logger.Warn("failed to load history key", "error", err)
+		}
+
+		persistence, err := store.NewEncryptedJSONLPersistence(historyPath, historyKey)
 		if err != nil {
 			return fmt.Errorf("failed to initialize persistence: %w", err)
 		}
 
 		historyStore = store.NewStore(persistence)
 
+		// Redact secrets from notifications imported by this invocation
+		redactor, err := cfg.Redaction.Redactor()
+		if err != nil {
+			return fmt.Errorf("invalid redaction config: %w", err)
+		}
+		if redactor != nil {
+			historyStore.SetRedactor(redactor)
+		}
+
 		// Load tombstones
 		tombstoneFile = store.NewTombstoneFile(config.TombstonePath())
 		tombstones, err := tombstoneFile.Load()
@@ -87,6 +107,13 @@ Running histui without a subcommand launches the interactive TUI.`,
 			logger.Warn("failed to hydrate store from disk", "error", err)
 		}
 
+		// Redact values whose redaction TTL passed while histuid was not running
+		if redactor != nil {
+			if _, err := redactor.ExpireDue(historyStore, time.Now()); err != nil {
+				logger.Warn("failed to expire redactions", "error", err)
+			}
+		}
+
 		return nil
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -9,6 +9,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
 )
 
 var setOpts struct {
@@ -21,12 +24,26 @@ var setOpts struct {
 	undismiss bool
 	seen      bool
 	delete    bool
+
+	// Annotations (combinable with each other and with state actions)
+	tags   []string
+	untags []string
+	star   bool
+	unstar bool
+	note   string
+
+	noteChanged bool // --note was given, even if empty
 }
 
 var setCmd = &cobra.Command{
 	Use:   "set [id...]",
-	Short: "Modify notification state",
-	Long: `Modify notification state (dismiss, undismiss, seen, delete).
+	Short: "Modify notification state and annotations",
+	Long: `Modify notification state (dismiss, undismiss, seen, delete) and
+annotations (tags, star, note).
+
+At most one state action can be given. Annotation flags can be combined with
+each other and with a state action other than --delete. Tags are lowercase
+and cannot contain commas or whitespace; --note "" clears the note.
 
 IDs can be provided as positional arguments or via stdin (--stdin).
 When using --stdin, each line is scanned for a ULID pattern.
@@ -48,7 +65,14 @@ Examples:
   histui get --filter "app=slack" --format ids | histui set --stdin --seen
 
   # Delete old notifications
-  histui get --filter "timestamp<7d" --format ids | histui set --stdin --delete`,
+  histui get --filter "timestamp<7d" --format ids | histui set --stdin --delete
+
+  # Tag and star a notification for follow-up
+  histui set 01HZ3X2J5YFMK2V3P4Q6R7S8T9 --tag work --tag followup --star
+
+  # Attach a note, then list starred work items
+  histui set 01HZ3X2J5YFMK2V3P4Q6R7S8T9 --note "reply after stand-up"
+  histui get --filter "starred=true,tag=work"`,
 	RunE: runSet,
 }
 
@@ -70,6 +94,19 @@ func init() {
 		"Mark notification(s) as seen")
 	setCmd.Flags().BoolVar(&setOpts.delete, "delete", false,
 		"Permanently delete notification(s) from history")
+
+	// Annotation flags
+	setCmd.Flags().StringSliceVar(&setOpts.tags, "tag", nil,
+		"Add a tag (repeatable)")
+	setCmd.Flags().StringSliceVar(&setOpts.untags, "untag", nil,
+		"Remove a tag (repeatable)")
+	setCmd.Flags().BoolVar(&setOpts.star, "star", false,
+		"Star notification(s); starred items are exempt from prune")
+	setCmd.Flags().BoolVar(&setOpts.unstar, "unstar", false,
+		"Remove the star from notification(s)")
+	setCmd.Flags().StringVar(&setOpts.note, "note", "",
+		"Set a free-text note (empty clears it)")
+	setCmd.MarkFlagsMutuallyExclusive("star", "unstar")
 }
 
 func runSet(cmd *cobra.Command, args []string) error {
@@ -88,12 +125,27 @@ func runSet(cmd *cobra.Command, args []string) error {
 		actionCount++
 	}
 
-	if actionCount == 0 {
-		return fmt.Errorf("must specify an action: --dismiss, --undismiss, --seen, or --delete")
+	annotating := len(setOpts.tags) > 0 || len(setOpts.untags) > 0 ||
+		setOpts.star || setOpts.unstar || cmd.Flags().Changed("note")
+	setOpts.noteChanged = cmd.Flags().Changed("note")
+
+	if actionCount == 0 && !annotating {
+		return fmt.Errorf("must specify an action: --dismiss, --undismiss, --seen, --delete, --tag, --untag, --star, --unstar, or --note")
 	}
 	if actionCount > 1 {
 		return fmt.Errorf("only one action can be specified at a time")
 	}
+	if setOpts.delete && annotating {
+		return fmt.Errorf("--delete cannot be combined with annotation flags")
+	}
+
+	var err error
+	if setOpts.tags, err = normalizeTags(setOpts.tags); err != nil {
+		return err
+	}
+	if setOpts.untags, err = normalizeTags(setOpts.untags); err != nil {
+		return err
+	}
 
 	// Collect IDs
 	ids := args
@@ -114,16 +166,21 @@ func runSet(cmd *cobra.Command, args []string) error {
 	// Remove duplicates
 	ids = uniqueStrings(ids)
 
-	// Perform the action
+	// Perform the action in a single store transaction
 	var successCount, failCount int
-	for _, id := range ids {
-		err := performAction(id)
-		if err != nil {
-			logger.Warn("failed to update notification", "id", id, "error", err)
-			failCount++
-		} else {
-			successCount++
+	err = historyStore.Batch(func(tx *store.Tx) error {
+		for _, id := range ids {
+			if performAction(tx, id) {
+				successCount++
+			} else {
+				logger.Warn("notification not found", "id", id)
+				failCount++
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notifications: %w", err)
 	}
 
 	// Report results
@@ -136,6 +193,12 @@ func runSet(cmd *cobra.Command, args []string) error {
 		action = "marked as seen"
 	} else if setOpts.delete {
 		action = "deleted"
+	} else if setOpts.star {
+		action = "starred"
+	} else if setOpts.unstar {
+		action = "unstarred"
+	} else if len(setOpts.tags) > 0 || len(setOpts.untags) > 0 {
+		action = "tagged"
 	}
 
 	if failCount > 0 {
@@ -248,25 +311,50 @@ func readJSONFromStdin(scanner *bufio.Scanner) ([]string, error) {
 	return ids, nil
 }
 
-// performAction performs the selected action on a notification.
-func performAction(id string) error {
-	n := historyStore.GetByID(id)
-	if n == nil {
-		return fmt.Errorf("notification not found: %s", id)
+// performAction stages the selected annotations and action on a notification.
+// Returns false if the notification was not found.
+func performAction(tx *store.Tx, id string) bool {
+	if tx.Get(id) == nil {
+		return false
+	}
+
+	for _, tag := range setOpts.tags {
+		tx.Tag(id, tag)
+	}
+	for _, tag := range setOpts.untags {
+		tx.Untag(id, tag)
+	}
+	if setOpts.star || setOpts.unstar {
+		tx.SetStarred(id, setOpts.star)
+	}
+	if setOpts.noteChanged {
+		tx.SetNote(id, setOpts.note)
 	}
 
 	switch {
 	case setOpts.dismiss:
-		n.MarkDismissed()
+		return tx.Dismiss(id)
 	case setOpts.undismiss:
-		n.Undismiss()
+		return tx.Undismiss(id)
 	case setOpts.seen:
-		n.MarkSeen()
+		return tx.MarkSeen(id)
 	case setOpts.delete:
-		return historyStore.Delete(id)
+		return tx.Delete(id)
 	}
+	return true
+}
 
-	return historyStore.Update(*n)
+// normalizeTags lowercases and validates tags given on the command line.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := model.NormalizeTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %q: %w", tag, err)
+		}
+		normalized = append(normalized, t)
+	}
+	return uniqueStrings(normalized), nil
 }
 
 // uniqueStrings removes duplicates from a string slice.
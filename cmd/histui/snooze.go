@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+var snoozeOpts struct {
+	cancel bool
+}
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze <id> <duration|time>",
+	Short: "Hide a notification and show it again later",
+	Long: `Hide a notification until a later time, when histuid displays it again.
+
+The time can be a duration (30m, 2h, 1d), a clock time (15:00, the next
+occurrence) or an absolute time (2006-01-02 15:04). Snoozes are stored in
+the history file, so they survive daemon restarts; snoozes that expire
+while histuid is not running are shown when it next starts.
+
+Examples:
+  # Show a notification again in 30 minutes
+  histui snooze 01HZ3X2J5YFMK2V3P4Q6R7S8T9 30m
+
+  # Snooze until tomorrow morning
+  histui snooze 01HZ3X2J5YFMK2V3P4Q6R7S8T9 09:00
+
+  # Cancel a snooze and restore the notification now
+  histui snooze 01HZ3X2J5YFMK2V3P4Q6R7S8T9 --cancel
+
+  # List pending snoozes
+  histui get --snoozed`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if snoozeOpts.cancel {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: runSnooze,
+}
+
+func init() {
+	rootCmd.AddCommand(snoozeCmd)
+
+	snoozeCmd.Flags().BoolVar(&snoozeOpts.cancel, "cancel", false,
+		"Cancel a pending snooze instead of creating one")
+}
+
+func runSnooze(cmd *cobra.Command, args []string) error {
+	n := historyStore.Lookup(parseDmenuSelection(args[0]))
+	if n == nil {
+		return fmt.Errorf("notification %s not found", args[0])
+	}
+
+	if snoozeOpts.cancel {
+		if !n.HasPendingSnooze() {
+			return fmt.Errorf("notification %s is not snoozed", n.HistuiID)
+		}
+		err := historyStore.Batch(func(tx *store.Tx) error {
+			tx.Unsnooze(n.HistuiID)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel snooze: %w", err)
+		}
+		fmt.Printf("Cancelled snooze for %q\n", n.Summary)
+		return nil
+	}
+
+	until, err := core.ParseWhen(args[1], time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = historyStore.Batch(func(tx *store.Tx) error {
+		tx.Snooze(n.HistuiID, until)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to snooze notification: %w", err)
+	}
+
+	fmt.Printf("Snoozed %q until %s\n", n.Summary, formatWhen(until))
+	return nil
+}
+
+// formatWhen formats a future time, including the date unless it is today.
+func formatWhen(t time.Time) string {
+	now := time.Now()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("Mon Jan 2 15:04")
+}
+
+// pendingSnoozes returns the stored notifications histuid has yet to
+// show again.
+func pendingSnoozes() []model.Notification {
+	var snoozed []model.Notification
+	for _, n := range historyStore.All() {
+		if n.HasPendingSnooze() {
+			snoozed = append(snoozed, n)
+		}
+	}
+	return snoozed
+}
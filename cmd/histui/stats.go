@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+var statsOpts struct {
+	since  string
+	until  string
+	filter string
+	format string
+	top    int
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show notification analytics",
+	Long: `Aggregate the notification history to show which apps interrupt you
+most and when.
+
+Reports counts by app, urgency, category, hour of day and weekday, the
+dismissed-vs-ignored ratio and the median time to dismiss. A notification is
+"ignored" if it was never seen or dismissed.
+
+Examples:
+  # Stats for the last week
+  histui stats --since 7d
+
+  # Histogram view
+  histui stats --since 30d --format histogram
+
+  # Last week, Monday to Sunday
+  histui stats --since 2026-10-05 --until 2026-10-11
+
+  # JSON for scripting
+  histui stats --format json
+
+  # Only critical notifications
+  histui stats --filter "urgency=critical"`,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsOpts.since, "since", "",
+		"Only include notifications from this time on (e.g., 7d, monday, 2026-10-01)")
+	statsCmd.Flags().StringVar(&statsOpts.until, "until", "",
+		"Only include notifications up to this time (e.g., yesterday, 2026-10-07)")
+	statsCmd.Flags().StringVar(&statsOpts.filter, "filter", "",
+		"Expression filter (e.g., 'app=discord,urgency=critical')")
+	statsCmd.Flags().StringVarP(&statsOpts.format, "format", "f", "table",
+		"Output format (table, json, histogram)")
+	statsCmd.Flags().IntVar(&statsOpts.top, "top", 10,
+		"Maximum number of apps and categories to show (0=unlimited)")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	notifications := historyStore.All()
+
+	if statsOpts.filter != "" {
+		expr, err := core.ParseFilter(statsOpts.filter)
+		if err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+		notifications = core.FilterWithExpr(notifications, expr)
+	}
+
+	var opts core.FilterOptions
+	if err := opts.SetTimeRange(statsOpts.since, statsOpts.until, time.Now()); err != nil {
+		return err
+	}
+	notifications = core.Filter(notifications, opts)
+
+	stats := core.ComputeStats(notifications)
+
+	switch strings.ToLower(statsOpts.format) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case "histogram", "hist", "spark":
+		return writeStatsHistogram(os.Stdout, stats, statsOpts.top)
+	case "table":
+		return writeStatsTable(os.Stdout, stats, statsOpts.top)
+	default:
+		return fmt.Errorf("unknown format %q (use table, json, histogram)", statsOpts.format)
+	}
+}
+
+// writeStatsTable writes the stats as aligned text tables.
+func writeStatsTable(w io.Writer, st core.Stats, top int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Total\t%d\n", st.Total)
+	fmt.Fprintf(tw, "Dismissed\t%d\n", st.Dismissed)
+	fmt.Fprintf(tw, "Seen\t%d\n", st.Seen)
+	fmt.Fprintf(tw, "Ignored\t%d\n", st.Ignored)
+	fmt.Fprintf(tw, "Dismiss ratio\t%.0f%%\n", st.DismissRatio*100)
+	fmt.Fprintf(tw, "Median time to dismiss\t%s\n", formatStatsDuration(st.MedianTimeToDismiss))
+
+	writeBucketTable(tw, "APP", st.ByApp, top)
+	writeBucketTable(tw, "URGENCY", st.ByUrgency, 0)
+	writeBucketTable(tw, "CATEGORY", st.ByCategory, top)
+
+	fmt.Fprintf(tw, "\nHOUR\tCOUNT\n")
+	for hour, count := range st.ByHour {
+		fmt.Fprintf(tw, "%02d\t%d\n", hour, count)
+	}
+
+	fmt.Fprintf(tw, "\nWEEKDAY\tCOUNT\n")
+	for day, count := range st.ByWeekday {
+		fmt.Fprintf(tw, "%s\t%d\n", core.WeekdayNames[day], count)
+	}
+
+	return tw.Flush()
+}
+
+// writeBucketTable writes one grouped table section.
+func writeBucketTable(w io.Writer, title string, buckets []core.StatsBucket, top int) {
+	fmt.Fprintf(w, "\n%s\tCOUNT\tDISMISSED\tIGNORED\n", title)
+	for i, b := range buckets {
+		if top > 0 && i >= top {
+			fmt.Fprintf(w, "... %d more\t\t\t\n", len(buckets)-top)
+			break
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", b.Name, b.Count, b.Dismissed, b.Ignored)
+	}
+}
+
+// writeStatsHistogram writes the stats as bar charts and sparklines.
+func writeStatsHistogram(w io.Writer, st core.Stats, top int) error {
+	const barWidth = 40
+
+	fmt.Fprintf(w, "%d notifications, %.0f%% dismissed vs ignored, median dismiss %s\n",
+		st.Total, st.DismissRatio*100, formatStatsDuration(st.MedianTimeToDismiss))
+
+	writeBucketBars(w, "By app", st.ByApp, top, barWidth)
+	writeBucketBars(w, "By urgency", st.ByUrgency, 0, barWidth)
+	writeBucketBars(w, "By category", st.ByCategory, top, barWidth)
+
+	fmt.Fprintf(w, "\nBy hour\n")
+	fmt.Fprintf(w, "  %s\n", core.Sparkline(st.ByHour[:]))
+	fmt.Fprintf(w, "  0     6     12    18   23\n")
+
+	fmt.Fprintf(w, "\nBy weekday\n")
+	maxDay := 0
+	for _, c := range st.ByWeekday {
+		maxDay = max(maxDay, c)
+	}
+	for day, count := range st.ByWeekday {
+		fmt.Fprintf(w, "  %s %s %d\n", core.WeekdayNames[day], core.Bar(count, maxDay, barWidth), count)
+	}
+
+	return nil
+}
+
+// writeBucketBars writes one grouped histogram section.
+func writeBucketBars(w io.Writer, title string, buckets []core.StatsBucket, top, width int) {
+	fmt.Fprintf(w, "\n%s\n", title)
+	if len(buckets) == 0 {
+		fmt.Fprintf(w, "  (none)\n")
+		return
+	}
+
+	if top > 0 && len(buckets) > top {
+		buckets = buckets[:top]
+	}
+
+	nameWidth, maxCount := 0, 0
+	for _, b := range buckets {
+		nameWidth = max(nameWidth, len(b.Name))
+		maxCount = max(maxCount, b.Count)
+	}
+
+	for _, b := range buckets {
+		fmt.Fprintf(w, "  %-*s %s %d\n", nameWidth, b.Name, core.Bar(b.Count, maxCount, width), b.Count)
+	}
+}
+
+// formatStatsDuration formats a duration for stats output ("-" when zero).
+func formatStatsDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
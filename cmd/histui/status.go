@@ -11,14 +11,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jmylchreest/histui/internal/adapter/input"
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
 	"github.com/jmylchreest/histui/internal/store"
 )
 
 var statusOpts struct {
 	source  string
 	since   string
+	until   string
 	urgency string
-	all     bool // Include history (acknowledged) notifications
+	all     bool   // Include history (acknowledged) notifications
+	view    string // Named view from config (counts matching history)
 }
 
 // WaybarStatus represents the Waybar custom module JSON format.
@@ -47,6 +51,14 @@ This is designed to be used with Waybar's custom module:
     "on-click": "histui tui"
   }
 
+With --view, the count is instead the number of notifications in the history
+that match the named view from the config file, e.g.:
+
+  [views.critical]
+  filter = "urgency=critical,dismissed=false"
+
+  histui status --view critical
+
 The output includes:
   - text: Number of active notifications
   - alt: Urgency class (low, normal, critical, empty)
@@ -63,9 +75,13 @@ func init() {
 	statusCmd.Flags().BoolVar(&statusOpts.all, "all", false,
 		"Include history (acknowledged) notifications in count")
 	statusCmd.Flags().StringVar(&statusOpts.since, "since", "",
-		"Only count notifications from the last duration (for --all)")
+		"Only count notifications from this time on (e.g., 24h, today; for --view)")
+	statusCmd.Flags().StringVar(&statusOpts.until, "until", "",
+		"Only count notifications up to this time (for --view)")
 	statusCmd.Flags().StringVar(&statusOpts.urgency, "urgency", "",
 		"Only count notifications of this urgency level")
+	statusCmd.Flags().StringVar(&statusOpts.view, "view", "",
+		"Count history notifications matching a named view from the config file")
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -76,7 +92,15 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	dndEnabled := false
 	sharedState, err := store.LoadSharedState()
 	if err == nil {
-		dndEnabled = sharedState.DnDEnabled
+		dndEnabled = sharedState.IsDnDActive(time.Now())
+	}
+
+	if statusOpts.view != "" {
+		status, err := generateStatusFromView(statusOpts.view, dndEnabled)
+		if err != nil {
+			return err
+		}
+		return outputStatus(status)
 	}
 
 	// Currently only dunst is supported for status
@@ -152,6 +176,77 @@ func generateStatusFromCounts(counts *input.DunstCounts, includeHistory bool, dn
 	}
 }
 
+// statusViewTooltipMax limits how many notifications are listed in a view tooltip.
+const statusViewTooltipMax = 5
+
+// generateStatusFromView creates a WaybarStatus from history matching a named view.
+func generateStatusFromView(name string, dndEnabled bool) (WaybarStatus, error) {
+	view, err := cfg.View(name)
+	if err != nil {
+		return WaybarStatus{}, err
+	}
+
+	expr, err := view.FilterExpr()
+	if err != nil {
+		return WaybarStatus{}, fmt.Errorf("view %q: %w", name, err)
+	}
+	notifications := core.FilterWithExpr(historyStore.All(), expr)
+
+	opts := core.FilterOptions{}
+	if err := opts.SetTimeRange(statusOpts.since, statusOpts.until, time.Now()); err != nil {
+		return WaybarStatus{}, err
+	}
+	if statusOpts.urgency != "" {
+		u, err := core.ParseUrgency(statusOpts.urgency)
+		if err != nil {
+			return WaybarStatus{}, err
+		}
+		opts.Urgency = &u
+	}
+	notifications = core.Filter(notifications, opts)
+	core.Sort(notifications, view.SortOptions())
+	if view.Limit > 0 && len(notifications) > view.Limit {
+		notifications = notifications[:view.Limit]
+	}
+
+	count := len(notifications)
+	if dndEnabled {
+		tooltip := "Do Not Disturb: enabled"
+		if count > 0 {
+			tooltip += fmt.Sprintf("\n%d notification(s) in %s", count, name)
+		}
+		return WaybarStatus{Text: "DnD", Alt: "dnd", Tooltip: tooltip, Class: "dnd"}, nil
+	}
+
+	if count == 0 {
+		return WaybarStatus{Text: "", Alt: "empty", Tooltip: "No notifications in " + name, Class: "empty"}, nil
+	}
+
+	// Class reflects the highest urgency in the view
+	highest := model.UrgencyLow
+	for _, n := range notifications {
+		highest = max(highest, n.Urgency)
+	}
+	urgencyClass := model.UrgencyNames[highest]
+
+	lines := []string{fmt.Sprintf("%d in %s", count, name)}
+	for i, n := range notifications {
+		if i >= statusViewTooltipMax {
+			lines = append(lines, fmt.Sprintf("... and %d more", count-statusViewTooltipMax))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", n.AppName, n.Summary))
+	}
+
+	return WaybarStatus{
+		Text:       fmt.Sprintf("%d", count),
+		Alt:        urgencyClass,
+		Tooltip:    joinLines(lines),
+		Class:      urgencyClass,
+		Percentage: min(count, 100),
+	}, nil
+}
+
 // buildCountsTooltip creates a tooltip showing notification breakdown.
 func buildCountsTooltip(counts *input.DunstCounts, includeHistory bool) string {
 	var lines []string
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmylchreest/histui/internal/config"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Encrypt or decrypt the history file",
+	Long: `Migrate the history file between plaintext and encrypted storage.
+
+Encrypted history is read and written transparently by histui and histuid
+once the key is configured. The key is read from $HISTUI_HISTORY_KEY, the
+[encryption] key_command (e.g. a keyring lookup) or key_file, in that order.
+A key starting with HISTUI-X25519-KEY- is an X25519 identity; anything else
+is a passphrase.
+
+Examples:
+  # Encrypt with a new X25519 key in ~/.config/histui/history.key
+  histui store encrypt
+
+  # Encrypt with a passphrase from the keyring
+  #   [encryption]
+  #   key_command = "secret-tool lookup service histui"
+  histui store encrypt
+
+  # Back to plaintext
+  histui store decrypt`,
+	// The history may not be readable yet; these commands open it themselves.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupLogger()
+		var err error
+		if cfg, err = config.LoadConfig(globalOpts.configPath); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return nil
+	},
+}
+
+var storeEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the history file",
+	Long: `Encrypt the history file with the configured key.
+
+If no key is configured, a new X25519 identity is written to
+~/.config/histui/history.key. Keep a copy of it: the history cannot be read
+without it. Restart histuid afterwards so it loads the key.`,
+	Args: cobra.NoArgs,
+	RunE: runStoreEncrypt,
+}
+
+var storeDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt the history file",
+	Long:  `Rewrite the encrypted history file as plaintext JSONL.`,
+	Args:  cobra.NoArgs,
+	RunE:  runStoreDecrypt,
+}
+
+func init() {
+	rootCmd.AddCommand(storeCmd)
+	storeCmd.AddCommand(storeEncryptCmd, storeDecryptCmd)
+}
+
+func runStoreEncrypt(cmd *cobra.Command, args []string) error {
+	path := globalOpts.historyFile
+	if path == "" {
+		path = config.HistoryPath()
+	}
+
+	scheme, err := store.FileEncryption(path, nil)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if scheme != "" {
+		return fmt.Errorf("%s is already encrypted (%s)", path, scheme)
+	}
+
+	key, source, err := cfg.Encryption.HistoryKey()
+	if err != nil {
+		return fmt.Errorf("failed to load history key: %w", err)
+	}
+	if key == nil {
+		if key, source, err = generateKeyFile(); err != nil {
+			return err
+		}
+		fmt.Printf("Generated a new key in %s\n", source)
+		fmt.Println("Keep a copy of it: the history cannot be read without it.")
+	}
+
+	count, err := store.MigrateHistory(path, nil, key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt history: %w", err)
+	}
+	fmt.Printf("Encrypted %d notifications in %s (%s, key from %s)\n", count, path, key.Scheme(), source)
+
+	if backups := plaintextCopies(path); len(backups) > 0 {
+		fmt.Println("Unencrypted copies of the history remain; remove them if they are no longer needed:")
+		for _, backup := range backups {
+			fmt.Println("  " + backup)
+		}
+	}
+	fmt.Println("Restart histuid so it loads the key.")
+	return nil
+}
+
+func runStoreDecrypt(cmd *cobra.Command, args []string) error {
+	path := globalOpts.historyFile
+	if path == "" {
+		path = config.HistoryPath()
+	}
+
+	scheme, err := store.FileEncryption(path, nil)
+	if err != nil {
+		return err
+	}
+	if scheme == "" {
+		return fmt.Errorf("%s is not encrypted", path)
+	}
+
+	key, _, err := cfg.Encryption.HistoryKey()
+	if err != nil {
+		return fmt.Errorf("failed to load history key: %w", err)
+	}
+	if key == nil {
+		return fmt.Errorf("%w; set $%s or [encryption] in config.toml", store.ErrHistoryEncrypted, config.HistoryKeyEnv)
+	}
+
+	count, err := store.MigrateHistory(path, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt history: %w", err)
+	}
+	fmt.Printf("Decrypted %d notifications in %s\n", count, path)
+	return nil
+}
+
+// generateKeyFile writes a new X25519 identity to the default key file.
+func generateKeyFile() (*store.HistoryKey, string, error) {
+	key, err := store.GenerateHistoryKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := config.DefaultKeyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, "", err
+	}
+	// O_EXCL: never overwrite a key that may still be needed
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := file.WriteString(key.String() + "\n"); err != nil {
+		_ = file.Close()
+		return nil, "", err
+	}
+	if err := file.Close(); err != nil {
+		return nil, "", err
+	}
+	return key, path, nil
+}
+
+// plaintextCopies returns backups of the history file that are not
+// encrypted, such as those kept by doctor --fix.
+func plaintextCopies(path string) []string {
+	candidates, _ := filepath.Glob(path + ".corrupted.*")
+	candidates = append(candidates, path+".bak")
+
+	var plain []string
+	for _, candidate := range candidates {
+		if scheme, err := store.FileEncryption(candidate, nil); err == nil && scheme == "" {
+			plain = append(plain, candidate)
+		}
+	}
+	return plain
+}
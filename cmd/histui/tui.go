@@ -29,9 +29,16 @@ Key bindings:
   enter       View notification details
   c           Copy notification body to clipboard
   s           Copy summary to clipboard
-  /           Search notifications
+  /           Search notifications (fuzzy, best matches first)
   d           Delete notification
+  z           Snooze notification (or cancel its snooze)
+  *           Star/unstar notification
+  t           Add tags (prefix with - to remove)
+  n           Edit note
   r           Refresh from source
+  H           Do Not Disturb and popup controls
+  T           Group the list by day
+  M           Activity heatmap by day and hour
   ?           Show help
   q           Quit`,
 	RunE: runTUI,
@@ -4,9 +4,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -20,7 +22,9 @@ import (
 	"github.com/jmylchreest/histui/internal/daemon"
 	"github.com/jmylchreest/histui/internal/dbus"
 	"github.com/jmylchreest/histui/internal/display"
+	"github.com/jmylchreest/histui/internal/layout"
 	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/redact"
 	"github.com/jmylchreest/histui/internal/store"
 	"github.com/jmylchreest/histui/internal/theme"
 )
@@ -77,7 +81,10 @@ func runMonitorMode(logger *slog.Logger) {
 		os.Exit(1)
 	}
 
-	persistence, err := store.NewJSONLPersistence(historyPath)
+	// config.toml holds the redaction and encryption settings shared with histui
+	appCfg := loadAppConfig(logger)
+
+	persistence, err := store.NewEncryptedJSONLPersistence(historyPath, loadHistoryKey(appCfg, logger))
 	if err != nil {
 		logger.Error("failed to create persistence", "error", err)
 		os.Exit(1)
@@ -89,6 +96,14 @@ func runMonitorMode(logger *slog.Logger) {
 	}
 	logger.Info("history store initialized", "path", historyPath, "count", historyStore.Count())
 
+	// Redact secrets before notifications are stored
+	var redactions *daemon.RedactionExpirer
+	if redactor := loadRedactor(appCfg, logger); redactor != nil {
+		historyStore.SetRedactor(redactor)
+		redactions = daemon.NewRedactionExpirer(historyStore, redactor, logger)
+		redactions.Start(context.Background(), daemon.DefaultRedactionInterval)
+	}
+
 	// Create and configure the monitor
 	monitor := dbus.NewMonitor(logger)
 	monitor.SetNotifyHandler(func(notification *dbus.DBusNotification, id uint32) {
@@ -152,6 +167,9 @@ func runMonitorMode(logger *slog.Logger) {
 	if err := monitor.Stop(); err != nil {
 		logger.Warn("error stopping monitor", "error", err)
 	}
+	if redactions != nil {
+		redactions.Stop()
+	}
 	if err := historyStore.Close(); err != nil {
 		logger.Warn("error closing store", "error", err)
 	}
@@ -176,6 +194,7 @@ func runDaemonMode(logger *slog.Logger) {
 	// Shared state between GTK main loop and signal handlers
 	var (
 		dbusServer       *dbus.NotificationServer
+		controlServer    *dbus.ControlServer
 		displayManager   *display.Manager
 		themeLoader      *theme.Loader
 		audioManager     *audio.Manager
@@ -184,6 +203,10 @@ func runDaemonMode(logger *slog.Logger) {
 		storeWatcher     *daemon.StoreWatcher
 		stateWatcher     *daemon.StateWatcher
 		configWatcher    *daemon.ConfigWatcher
+		layoutWatcher    *layout.Watcher
+		snoozes          *daemon.SnoozeScheduler
+		pruner           *daemon.Pruner
+		redactions       *daemon.RedactionExpirer
 		internalNotifier *daemon.InternalNotifier
 		sharedState      *store.SharedState
 		running          atomic.Bool
@@ -211,15 +234,30 @@ func runDaemonMode(logger *slog.Logger) {
 				if configWatcher != nil {
 					configWatcher.Stop()
 				}
+				if layoutWatcher != nil {
+					layoutWatcher.Stop()
+				}
 				if stateWatcher != nil {
 					stateWatcher.Stop()
 				}
 				if storeWatcher != nil {
 					storeWatcher.Stop()
 				}
+				if snoozes != nil {
+					snoozes.Stop()
+				}
+				if pruner != nil {
+					pruner.Stop()
+				}
+				if redactions != nil {
+					redactions.Stop()
+				}
 				if displayManager != nil {
 					displayManager.Stop()
 				}
+				if controlServer != nil {
+					_ = controlServer.Stop()
+				}
 				if dbusServer != nil {
 					_ = dbusServer.Stop()
 				}
@@ -247,7 +285,10 @@ func runDaemonMode(logger *slog.Logger) {
 			return
 		}
 
-		persistence, err := store.NewJSONLPersistence(historyPath)
+		// config.toml holds the redaction and encryption settings shared with histui
+		appCfg := loadAppConfig(logger)
+
+		persistence, err := store.NewEncryptedJSONLPersistence(historyPath, loadHistoryKey(appCfg, logger))
 		if err != nil {
 			logger.Error("failed to create persistence", "error", err)
 			app.Quit()
@@ -260,6 +301,13 @@ func runDaemonMode(logger *slog.Logger) {
 		}
 		logger.Info("history store initialized", "path", historyPath, "count", historyStore.Count())
 
+		// Redact secrets before notifications are stored
+		redactor := loadRedactor(appCfg, logger)
+		if redactor != nil {
+			historyStore.SetRedactor(redactor)
+			redactions = daemon.NewRedactionExpirer(historyStore, redactor, logger)
+		}
+
 		// Load shared state (DnD, etc.)
 		sharedState, err = store.LoadSharedState()
 		if err != nil {
@@ -271,6 +319,13 @@ func runDaemonMode(logger *slog.Logger) {
 		// Initialize display state manager (maps D-Bus IDs to histui IDs)
 		displayState = daemon.NewDisplayStateManager()
 
+		// Initialize snooze scheduler (re-displays snoozed notifications)
+		snoozes = daemon.NewSnoozeScheduler(logger)
+
+		// Initialize retention pruner (applies [[retention]] policies)
+		pruner = daemon.NewPruner(historyStore, logger)
+		pruner.SetPolicies(cfg.Retention)
+
 		// Initialize theme loader
 		themeLoader = theme.NewLoader(logger)
 		if err := themeLoader.LoadTheme(cfg.Theme.Name); err != nil {
@@ -302,60 +357,26 @@ func runDaemonMode(logger *slog.Logger) {
 			SpecVersion: "1.2",
 		})
 
-		// Connect D-Bus notifications to display manager AND store
-		dbusServer.SetNotifyHandler(func(notification *dbus.DBusNotification, id uint32) {
-			// Create a model.Notification for persistence
-			n, err := model.NewNotification("histuid")
-			if err != nil {
-				logger.Error("failed to create notification model", "error", err)
-				return
-			}
-
-			// Populate from D-Bus notification
-			n.ID = int(id)
-			n.AppName = notification.AppName
-			n.Summary = notification.Summary
-			n.Body = notification.Body
-			n.Timestamp = time.Now().Unix()
-			n.ExpireTimeout = int(notification.ExpireTimeout)
-			n.IconPath = notification.AppIcon
-			n.SetUrgency(notification.Urgency())
-			n.Category = notification.Category()
-
-			// Store D-Bus specific extensions
-			n.Extensions = &model.Extensions{
-				Actions:      convertActions(notification.ParsedActions()),
-				SoundFile:    notification.SoundFile(),
-				SoundName:    notification.SoundName(),
-				DesktopEntry: notification.DesktopEntry(),
-				Resident:     notification.Resident(),
-				Transient:    notification.Transient(),
-			}
-
-			// Don't persist transient notifications
-			if !notification.Transient() {
-				if err := historyStore.Add(*n); err != nil {
-					logger.Error("failed to persist notification", "id", id, "error", err)
-				}
-			}
-
+		// presentNotification tracks a notification for display and shows it,
+		// honouring DnD and playing its sound
+		presentNotification := func(notification *dbus.DBusNotification, id uint32, histuiID string) {
 			// Track the mapping between D-Bus ID and histui ID
 			timeout := cfg.GetTimeoutForUrgency(notification.Urgency())
 			var expiresAt time.Time
 			if timeout > 0 {
 				expiresAt = time.Now().Add(time.Duration(timeout) * time.Millisecond)
 			}
-			displayState.Register(n.HistuiID, id, expiresAt)
+			displayState.Register(histuiID, id, expiresAt)
 
 			// Check if DnD is enabled (suppress popups and sounds)
 			urgency := notification.Urgency()
-			isDnDEnabled := sharedState != nil && sharedState.DnDEnabled
+			isDnDEnabled := sharedState != nil && sharedState.IsDnDActive(time.Now())
 			isCriticalBypass := cfg.DnD.CriticalBypass && urgency == 2 // Critical urgency
 
 			// Suppress popup and sound if DnD is enabled (unless critical bypass)
 			if isDnDEnabled && !isCriticalBypass {
 				logger.Debug("notification suppressed by DnD", "id", id, "urgency", urgency)
-				// Note: Notification is still persisted to store (done above)
+				// Note: Notification is still persisted to store by the caller
 				return
 			}
 
@@ -376,10 +397,50 @@ func runDaemonMode(logger *slog.Logger) {
 
 			// Schedule display on GTK main loop
 			glib.IdleAdd(func() {
-				if err := displayManager.Show(notification, id, n.HistuiID); err != nil {
+				if err := displayManager.Show(notification, id, histuiID); err != nil {
 					logger.Error("failed to show notification", "id", id, "error", err)
 				}
 			})
+		}
+
+		// Connect D-Bus notifications to display manager AND store
+		dbusServer.SetNotifyHandler(func(notification *dbus.DBusNotification, id uint32) {
+			// Create a model.Notification for persistence
+			n, err := model.NewNotification("histuid")
+			if err != nil {
+				logger.Error("failed to create notification model", "error", err)
+				return
+			}
+
+			// Populate from D-Bus notification
+			n.ID = int(id)
+			n.AppName = notification.AppName
+			n.Summary = notification.Summary
+			n.Body = notification.Body
+			n.Timestamp = time.Now().Unix()
+			n.ExpireTimeout = int(notification.ExpireTimeout)
+			n.IconPath = notification.AppIcon
+			n.SetUrgency(notification.Urgency())
+			n.Category = notification.Category()
+
+			// Store D-Bus specific extensions
+			n.Extensions = &model.Extensions{
+				Actions:      convertActions(notification.ParsedActions()),
+				SoundFile:    notification.SoundFile(),
+				SoundName:    notification.SoundName(),
+				DesktopEntry: notification.DesktopEntry(),
+				Resident:     notification.Resident(),
+				Transient:    notification.Transient(),
+			}
+
+			// Don't persist transient notifications
+			if !notification.Transient() {
+				if err := historyStore.Add(*n); err != nil {
+					logger.Error("failed to persist notification", "id", id, "error", err)
+				}
+			}
+
+			presentNotification(notification, id, n.HistuiID)
 		})
 
 		dbusServer.SetCloseHandler(func(id uint32) {
@@ -415,6 +476,36 @@ func runDaemonMode(logger *slog.Logger) {
 			}
 		})
 
+		// Snoozing from the popup menu persists the snooze before the popup
+		// closes as dismissed; the scheduler shows it again when it expires
+		displayManager.SetSnoozeCallback(func(dbusID uint32, d time.Duration) {
+			histuiID := displayState.GetHistuiIDByDBusID(dbusID)
+			if histuiID == "" {
+				return
+			}
+			until := time.Now().Add(d)
+			found := false
+			err := historyStore.Batch(func(tx *store.Tx) error {
+				found = tx.Snooze(histuiID, until)
+				return nil
+			})
+			if err != nil {
+				logger.Warn("failed to snooze notification", "histui_id", histuiID, "error", err)
+				return
+			}
+			if !found {
+				logger.Debug("cannot snooze notification missing from history", "histui_id", histuiID)
+				return
+			}
+			snoozes.Schedule(histuiID, until)
+		})
+
+		snoozes.SetDueCallback(func(histuiID string) {
+			glib.IdleAdd(func() {
+				showSnoozedNotification(historyStore, dbusServer, histuiID, presentNotification, logger)
+			})
+		})
+
 		// Start D-Bus server
 		if err := dbusServer.Start(); err != nil {
 			logger.Error("failed to start D-Bus server", "error", err)
@@ -423,12 +514,52 @@ func runDaemonMode(logger *slog.Logger) {
 			return
 		}
 
+		// Start control interface for histui CLI requests (e.g., histui pick)
+		controlServer = dbus.NewControlServer(logger)
+		controlServer.SetInvokeActionHandler(func(histuiID, actionKey string) error {
+			return invokeStoredAction(historyStore, displayState, dbusServer, displayManager, histuiID, actionKey)
+		})
+		controlServer.SetStatusHandler(func() dbus.Status {
+			active, queued := displayManager.Counts()
+			return dbus.Status{
+				Active: uint32(active),
+				Queued: uint32(queued),
+				Theme:  themeLoader.CurrentTheme(),
+				DnD:    sharedState != nil && sharedState.IsDnDActive(time.Now()),
+			}
+		})
+		controlServer.SetDnDHandler(func(enabled bool, d time.Duration, source string) error {
+			state, err := store.SaveDnD(enabled, d, source)
+			if err != nil {
+				return err
+			}
+			logger.Info("DnD state changed", "enabled", enabled, "duration", d, "source", source)
+			sharedState = state
+			return nil
+		})
+		controlServer.SetCloseAllHandler(func() {
+			glib.IdleAdd(displayManager.CloseAll)
+		})
+		controlServer.SetShowNotificationHandler(func(histuiID string) error {
+			return showStoredNotification(historyStore, dbusServer, displayState, displayManager, cfg, histuiID, logger)
+		})
+		if err := controlServer.Start(dbusServer.Connection()); err != nil {
+			logger.Warn("failed to start control server", "error", err)
+		}
+
 		// Initialize store watcher for external changes (e.g., histui CLI dismiss)
 		storeWatcher = daemon.NewStoreWatcher(historyPath, logger)
 		storeWatcher.SetChangeCallback(func() {
-			// Store file changed - check for dismissed notifications
+			// Store file changed - apply what other processes appended to the
+			// log, then close popups for dismissed notifications and
+			// reschedule snoozes
 			glib.IdleAdd(func() {
+				if err := historyStore.Reload(); err != nil {
+					logger.Warn("failed to reload history", "error", err)
+					return
+				}
 				checkForExternalDismissals(historyStore, displayManager, displayState, logger)
+				snoozes.Sync(historyStore.All())
 			})
 		})
 		if err := storeWatcher.Start(ctx); err != nil {
@@ -490,6 +621,12 @@ func runDaemonMode(logger *slog.Logger) {
 						}
 					}
 
+					// Apply changed retention policies straight away
+					pruner.SetPolicies(newConfig.Retention)
+					if !slices.Equal(newConfig.Retention, cfg.Retention) {
+						go pruner.Run()
+					}
+
 					// Update the config reference
 					cfg = newConfig
 
@@ -506,6 +643,42 @@ func runDaemonMode(logger *slog.Logger) {
 			}
 		}
 
+		// Report invalid user layouts now rather than on the first popup
+		layouts := displayManager.Layouts()
+		if err := layouts.Validate(); err != nil {
+			logger.Warn("invalid layout templates", "error", err)
+			internalNotifier.NotifyLayoutError(err)
+		}
+
+		// Watch layout templates; future popups use the reloaded versions
+		layoutWatcher = layout.NewWatcher(layouts.Dir(), logger)
+		layoutWatcher.SetChangeCallback(func(names []string) {
+			glib.IdleAdd(func() {
+				layouts.Invalidate(names...)
+				if err := layouts.Validate(); err != nil {
+					logger.Warn("invalid layout templates", "error", err)
+					internalNotifier.NotifyLayoutError(err)
+					return
+				}
+				internalNotifier.NotifyLayoutReloaded(names)
+			})
+		})
+		if err := layoutWatcher.Start(ctx); err != nil {
+			logger.Warn("failed to start layout watcher", "error", err)
+		}
+
+		// Schedule pending snoozes; those that expired while histuid was
+		// not running are shown straight away
+		snoozes.Sync(historyStore.All())
+
+		// Apply retention policies now and then hourly
+		pruner.Start(ctx, daemon.DefaultRetentionInterval)
+
+		// Redact kept values once their redaction TTL passes
+		if redactions != nil {
+			redactions.Start(ctx, daemon.DefaultRedactionInterval)
+		}
+
 		logger.Info("histuid ready", "dbus_interface", dbus.DBusInterface)
 
 		// Create a hidden window to keep the application running
@@ -529,15 +702,30 @@ func runDaemonMode(logger *slog.Logger) {
 		if configWatcher != nil {
 			configWatcher.Stop()
 		}
+		if layoutWatcher != nil {
+			layoutWatcher.Stop()
+		}
 		if stateWatcher != nil {
 			stateWatcher.Stop()
 		}
 		if storeWatcher != nil {
 			storeWatcher.Stop()
 		}
+		if snoozes != nil {
+			snoozes.Stop()
+		}
+		if pruner != nil {
+			pruner.Stop()
+		}
+		if redactions != nil {
+			redactions.Stop()
+		}
 		if displayManager != nil {
 			displayManager.Stop()
 		}
+		if controlServer != nil {
+			_ = controlServer.Stop()
+		}
 		if dbusServer != nil {
 			_ = dbusServer.Stop()
 		}
@@ -562,9 +750,49 @@ func runDaemonMode(logger *slog.Logger) {
 	logger.Info("histuid stopped")
 }
 
-// checkForExternalDismissals checks if any active popups were dismissed externally.
-// This is called when the store file changes (e.g., histui CLI dismissed a notification).
-// It reads the current state directly from the persistence file.
+// loadAppConfig loads config.toml for the settings histuid shares with
+// histui. Falls back to the defaults if it cannot be loaded.
+func loadAppConfig(logger *slog.Logger) *config.Config {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		logger.Warn("failed to load config.toml, using defaults", "error", err)
+		return config.DefaultConfig()
+	}
+	return cfg
+}
+
+// loadHistoryKey loads the key for an encrypted history. Returns nil if no
+// key is configured; an encrypted history then fails to load with a clear
+// error rather than being overwritten.
+func loadHistoryKey(cfg *config.Config, logger *slog.Logger) *store.HistoryKey {
+	key, source, err := cfg.Encryption.HistoryKey()
+	if err != nil {
+		logger.Warn("failed to load history key", "error", err)
+		return nil
+	}
+	if key != nil {
+		logger.Info("history key loaded", "scheme", key.Scheme(), "source", source)
+	}
+	return key
+}
+
+// loadRedactor builds the redactor from the [redaction] section of
+// config.toml. Returns nil if redaction is disabled or misconfigured.
+func loadRedactor(cfg *config.Config, logger *slog.Logger) *redact.Redactor {
+	redactor, err := cfg.Redaction.Redactor()
+	if err != nil {
+		logger.Warn("invalid redaction config, redaction disabled", "error", err)
+		return nil
+	}
+	if redactor != nil {
+		logger.Info("redaction enabled", "rules", len(redactor.Rules()))
+	}
+	return redactor
+}
+
+// checkForExternalDismissals checks if any active popups were dismissed
+// or snoozed externally (e.g., by the histui CLI). The store must have been
+// reloaded from disk first.
 func checkForExternalDismissals(
 	historyStore *store.Store,
 	displayManager *display.Manager,
@@ -575,68 +803,151 @@ func checkForExternalDismissals(
 		return
 	}
 
-	// Get all active histui IDs from the display manager
-	activeIDs := displayManager.GetActiveHistuiIDs()
-	if len(activeIDs) == 0 {
-		return
-	}
+	for _, histuiID := range displayManager.GetActiveHistuiIDs() {
+		// Skip if we've already processed this dismissal
+		if dismissedIDsCache[histuiID] {
+			continue
+		}
 
-	// Build a set of active IDs for quick lookup
-	activeIDSet := make(map[string]bool)
-	for _, id := range activeIDs {
-		activeIDSet[id] = true
+		n := historyStore.GetByID(histuiID)
+		switch {
+		case n == nil:
+			// Notification was deleted from store - close the popup
+			logger.Debug("notification deleted externally, closing popup", "histui_id", histuiID)
+		case n.IsDismissed():
+			logger.Debug("notification dismissed externally, closing popup", "histui_id", histuiID)
+		case n.IsSnoozed():
+			logger.Debug("notification snoozed externally, closing popup", "histui_id", histuiID)
+		default:
+			continue
+		}
+
+		dismissedIDsCache[histuiID] = true
+		displayManager.CloseByHistuiID(histuiID, dbus.CloseReasonDismissed)
 	}
+}
 
-	// Re-read the store from disk to get the latest state
-	// This creates a temporary persistence to read the file
-	historyPath, err := store.HistoryPath()
-	if err != nil {
-		logger.Warn("failed to get history path for external check", "error", err)
+// showSnoozedNotification displays a notification again once its snooze
+// has expired, under a fresh D-Bus ID since the original was closed.
+func showSnoozedNotification(
+	historyStore *store.Store,
+	dbusServer *dbus.NotificationServer,
+	histuiID string,
+	present func(notification *dbus.DBusNotification, id uint32, histuiID string),
+	logger *slog.Logger,
+) {
+	n := historyStore.GetByID(histuiID)
+	if n == nil || !n.HasPendingSnooze() || n.IsSnoozed() {
+		// Deleted, unsnoozed or snoozed again since it was scheduled
 		return
 	}
 
-	persistence, err := store.NewJSONLPersistence(historyPath)
+	err := historyStore.Batch(func(tx *store.Tx) error {
+		tx.Unsnooze(histuiID)
+		return nil
+	})
 	if err != nil {
-		logger.Warn("failed to open persistence for external check", "error", err)
-		return
+		logger.Warn("failed to clear snooze", "histui_id", histuiID, "error", err)
 	}
-	defer func() { _ = persistence.Close() }()
 
-	notifications, err := persistence.Load()
-	if err != nil {
-		logger.Warn("failed to load notifications for external check", "error", err)
-		return
+	delete(dismissedIDsCache, histuiID)
+	id := dbusServer.ReserveID()
+	logger.Debug("showing snoozed notification", "histui_id", histuiID, "id", id)
+	present(dbus.NotificationFromModel(n), id, histuiID)
+}
+
+// showStoredNotification shows a notification from the history as a popup
+// again, under a fresh D-Bus ID. It is an explicit request, so it is shown
+// even in DnD mode, without a sound, and restored if it was dismissed so
+// the popup is not closed as an external dismissal.
+func showStoredNotification(
+	historyStore *store.Store,
+	dbusServer *dbus.NotificationServer,
+	displayState *daemon.DisplayStateManager,
+	displayManager *display.Manager,
+	cfg *config.DaemonConfig,
+	histuiID string,
+	logger *slog.Logger,
+) error {
+	n := historyStore.GetByID(histuiID)
+	if n == nil {
+		return fmt.Errorf("notification %s not found", histuiID)
+	}
+	if _, shown := displayState.GetDBusIDByHistuiID(histuiID); shown {
+		return fmt.Errorf("notification %s is already shown", histuiID)
+	}
+	if n.IsDismissed() {
+		err := historyStore.Batch(func(tx *store.Tx) error {
+			tx.Undismiss(histuiID)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	// Build index of current notifications by histui ID
-	currentState := make(map[string]*model.Notification)
-	for i := range notifications {
-		currentState[notifications[i].HistuiID] = &notifications[i]
+	notification := dbus.NotificationFromModel(n)
+	id := dbusServer.ReserveID()
+	var expiresAt time.Time
+	if timeout := cfg.GetTimeoutForUrgency(notification.Urgency()); timeout > 0 {
+		expiresAt = time.Now().Add(time.Duration(timeout) * time.Millisecond)
 	}
+	displayState.Register(histuiID, id, expiresAt)
 
-	// Check each active notification against the current file state
-	for _, histuiID := range activeIDs {
-		// Skip if we've already processed this dismissal
-		if dismissedIDsCache[histuiID] {
-			continue
+	glib.IdleAdd(func() {
+		delete(dismissedIDsCache, histuiID)
+		if err := displayManager.Show(notification, id, histuiID); err != nil {
+			logger.Error("failed to show notification", "id", id, "error", err)
+			displayState.RemoveByDBusID(id)
 		}
+	})
+	return nil
+}
 
-		n, exists := currentState[histuiID]
-		if !exists {
-			// Notification was deleted from store - close the popup
-			logger.Debug("notification deleted externally, closing popup", "histui_id", histuiID)
-			dismissedIDsCache[histuiID] = true
-			displayManager.CloseByHistuiID(histuiID, dbus.CloseReasonDismissed)
-			continue
-		}
+// invokeStoredAction emits ActionInvoked for a notification that is still
+// displayed, closing the popup afterwards unless it is resident.
+// Applications only listen for actions on notifications they know are open,
+// so actions on notifications that have already closed are rejected.
+func invokeStoredAction(
+	historyStore *store.Store,
+	displayState *daemon.DisplayStateManager,
+	dbusServer *dbus.NotificationServer,
+	displayManager *display.Manager,
+	histuiID, actionKey string,
+) error {
+	n := historyStore.GetByID(histuiID)
+	if n == nil {
+		return fmt.Errorf("notification %s not found", histuiID)
+	}
 
-		// Check if it was dismissed
-		if n.IsDismissed() {
-			logger.Debug("notification dismissed externally, closing popup", "histui_id", histuiID)
-			dismissedIDsCache[histuiID] = true
-			displayManager.CloseByHistuiID(histuiID, dbus.CloseReasonDismissed)
+	hasAction := false
+	if n.Extensions != nil {
+		for _, a := range n.Extensions.Actions {
+			if a.Key == actionKey {
+				hasAction = true
+				break
+			}
 		}
 	}
+	if !hasAction {
+		return fmt.Errorf("notification %s has no action %q", histuiID, actionKey)
+	}
+
+	dbusID, ok := displayState.GetDBusIDByHistuiID(histuiID)
+	if !ok {
+		return fmt.Errorf("notification %s is no longer active", histuiID)
+	}
+
+	if err := dbusServer.EmitActionInvoked(dbusID, actionKey); err != nil {
+		return err
+	}
+
+	if !n.Extensions.Resident {
+		glib.IdleAdd(func() {
+			displayManager.Close(dbusID, dbus.CloseReasonDismissed)
+		})
+	}
+	return nil
 }
 
 // convertActions converts D-Bus actions to model.Action slice.
@@ -7,6 +7,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/jmylchreest/histui/internal/icons"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -34,7 +35,7 @@ func NewDmenuFormatter(opts FormatterOptions) *DmenuFormatter {
 // Format writes notifications in dmenu format (one per line).
 func (f *DmenuFormatter) Format(w io.Writer, notifications []model.Notification) error {
 	for i, n := range notifications {
-		line := f.formatLine(i+1, &n)
+		line := f.FormatLine(i+1, &n)
 		if _, err := fmt.Fprintln(w, line); err != nil {
 			return err
 		}
@@ -42,8 +43,11 @@ func (f *DmenuFormatter) Format(w io.Writer, notifications []model.Notification)
 	return nil
 }
 
-// formatLine formats a single notification line.
-func (f *DmenuFormatter) formatLine(index int, n *model.Notification) string {
+// FormatLine formats a single notification as one line.
+// index is the 1-based position shown when ShowIndex is set.
+func (f *DmenuFormatter) FormatLine(index int, n *model.Notification) string {
+	n = RenderMarkup(n, f.opts.Markup)
+
 	// Use custom template if available
 	if f.template != nil {
 		var buf strings.Builder
@@ -96,6 +100,9 @@ type templateData struct {
 	RelativeTime string
 }
 
+// templateIconSize is the icon size used by the iconPath template function.
+const templateIconSize = 48
+
 // templateFuncs returns template helper functions.
 func templateFuncs() template.FuncMap {
 	return template.FuncMap{
@@ -111,6 +118,12 @@ func templateFuncs() template.FuncMap {
 		"reltime": func(ts int64) string {
 			return relativeTime(ts)
 		},
+		"iconPath": func(n *model.Notification) string {
+			return icons.Default().NotificationIcon(n, templateIconSize)
+		},
+		"appDisplayName": func(n *model.Notification) string {
+			return icons.Default().NotificationAppName(n)
+		},
 		"urgencyIcon": func(urgency int) string {
 			switch urgency {
 			case model.UrgencyLow:
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -19,6 +20,14 @@ func NewJSONFormatter(opts FormatterOptions) *JSONFormatter {
 
 // Format writes notifications as a JSON array.
 func (f *JSONFormatter) Format(w io.Writer, notifications []model.Notification) error {
+	if f.opts.Markup != "" && f.opts.Markup != markup.ModeRaw {
+		rendered := make([]model.Notification, len(notifications))
+		for i := range notifications {
+			rendered[i] = *RenderMarkup(&notifications[i], f.opts.Markup)
+		}
+		notifications = rendered
+	}
+
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(notifications)
@@ -28,5 +37,5 @@ func (f *JSONFormatter) Format(w io.Writer, notifications []model.Notification)
 func (f *JSONFormatter) FormatSingle(w io.Writer, n *model.Notification) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(n)
+	return encoder.Encode(RenderMarkup(n, f.opts.Markup))
 }
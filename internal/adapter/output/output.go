@@ -4,6 +4,7 @@ package output
 import (
 	"io"
 
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -41,14 +42,15 @@ func NewFormatter(format FormatType, opts FormatterOptions) Formatter {
 
 // FormatterOptions configures formatter behavior.
 type FormatterOptions struct {
-	Template       string // Custom template for dmenu/plain format
-	ShowIndex      bool   // Show 1-based index prefix
-	ShowTime       bool   // Show relative time
-	ShowApp        bool   // Show app name
-	BodyMaxLen     int    // Maximum body length (0 = unlimited)
-	Separator      string // Field separator for dmenu format
-	OutputField    string // Field to output (for single-notification mode)
-	IncludeNewline bool   // Include newlines in body (default: replace with space)
+	Template       string      // Custom template for dmenu/plain format
+	ShowIndex      bool        // Show 1-based index prefix
+	ShowTime       bool        // Show relative time
+	ShowApp        bool        // Show app name
+	BodyMaxLen     int         // Maximum body length (0 = unlimited)
+	Separator      string      // Field separator for dmenu format
+	OutputField    string      // Field to output (for single-notification mode)
+	IncludeNewline bool        // Include newlines in body (default: replace with space)
+	Markup         markup.Mode // How body markup is rendered (empty = raw)
 }
 
 // DefaultFormatterOptions returns sensible defaults for dmenu output.
@@ -60,5 +62,18 @@ func DefaultFormatterOptions() FormatterOptions {
 		BodyMaxLen:     80,
 		Separator:      " | ",
 		IncludeNewline: false,
+		Markup:         markup.ModeStrip,
 	}
 }
+
+// RenderMarkup returns n with its body rendered for the markup mode, or n
+// itself if there is nothing to change.
+func RenderMarkup(n *model.Notification, mode markup.Mode) *model.Notification {
+	body := mode.Render(n.Body)
+	if body == n.Body {
+		return n
+	}
+	rendered := *n
+	rendered.Body = body
+	return &rendered
+}
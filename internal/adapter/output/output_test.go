@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -110,6 +111,38 @@ func TestDmenuFormatter_TruncateBody(t *testing.T) {
 	assert.NotContains(t, output, "truncated when the max length is set")
 }
 
+func TestFormatters_Markup(t *testing.T) {
+	n := model.Notification{
+		HistuiID: "m1",
+		AppName:  "Chat",
+		Summary:  "Alice",
+		Body:     `<b>Hi</b> &amp; <a href="https://x.test">link</a>`,
+	}
+
+	var buf bytes.Buffer
+	formatter := NewDmenuFormatter(DefaultFormatterOptions())
+	require.NoError(t, formatter.Format(&buf, []model.Notification{n}))
+	assert.Contains(t, buf.String(), "Alice: Hi & link")
+
+	opts := DefaultFormatterOptions()
+	opts.Markup = markup.ModeRaw
+	assert.Contains(t, NewDmenuFormatter(opts).FormatLine(1, &n), "<b>Hi</b>")
+
+	// Templates see the rendered body too
+	opts = DefaultFormatterOptions()
+	opts.Template = "{{.Notification.Body}}"
+	assert.Equal(t, "Hi & link", NewDmenuFormatter(opts).FormatLine(1, &n))
+
+	buf.Reset()
+	opts = DefaultFormatterOptions()
+	opts.Markup = markup.ModeANSI
+	require.NoError(t, NewJSONFormatter(opts).Format(&buf, []model.Notification{n}))
+	var result []model.Notification
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "\x1b[1mHi\x1b[22m & \x1b]8;;https://x.test\x1b\\link\x1b]8;;\x1b\\", result[0].Body)
+	assert.Equal(t, `<b>Hi</b> &amp; <a href="https://x.test">link</a>`, n.Body, "the input is not modified")
+}
+
 func TestJSONFormatter_Format(t *testing.T) {
 	notifications := testNotifications()
 	var buf bytes.Buffer
@@ -171,6 +204,8 @@ func TestFormatField(t *testing.T) {
 		Category:    "transfer.complete",
 		IconPath:    "/usr/share/icons/firefox.png",
 		UrgencyName: "normal",
+		HistuiTags:  []string{"work", "followup"},
+		HistuiNote:  "check checksum",
 	}
 
 	tests := []struct {
@@ -186,6 +221,8 @@ func TestFormatField(t *testing.T) {
 		{"category", "transfer.complete"},
 		{"icon", "/usr/share/icons/firefox.png"},
 		{"urgency", "normal"},
+		{"tags", "work,followup"},
+		{"note", "check checksum"},
 		{"all", "Download Complete\nfile.zip finished"},
 		{"unknown", "Download Complete"}, // defaults to summary
 	}
@@ -198,6 +235,19 @@ func TestFormatField(t *testing.T) {
 	}
 }
 
+func TestFormatField_Extracted(t *testing.T) {
+	n := &model.Notification{
+		Summary: "Sign in to Acme",
+		Body:    `Your login code is 482913. <a href="https://acme.example/help">Help</a> or mail support@acme.example`,
+	}
+
+	assert.Equal(t, "https://acme.example/help", FormatField(n, "urls"))
+	assert.Equal(t, "support@acme.example", FormatField(n, "emails"))
+	assert.Equal(t, "482913", FormatField(n, "codes"))
+	assert.True(t, IsExtractedField("URLs"))
+	assert.False(t, IsExtractedField("body"))
+}
+
 func TestIDsFormatter_Format(t *testing.T) {
 	notifications := testNotifications()
 	var buf bytes.Buffer
@@ -42,6 +42,8 @@ func (f *PlainFormatter) Format(w io.Writer, notifications []model.Notification)
 
 // formatNotification formats a single notification.
 func (f *PlainFormatter) formatNotification(w io.Writer, index int, n *model.Notification) error {
+	n = RenderMarkup(n, f.opts.Markup)
+
 	// Use custom template if available
 	if f.template != nil {
 		data := templateData{
@@ -86,7 +88,8 @@ func (f *PlainFormatter) formatNotification(w io.Writer, index int, n *model.Not
 	return err
 }
 
-// FormatField outputs a specific field from a notification.
+// FormatField outputs a specific field from a notification. The extracted
+// fields (urls, emails and codes) list one value per line.
 func FormatField(n *model.Notification, field string) string {
 	switch strings.ToLower(field) {
 	case "id", "histui_id":
@@ -103,9 +106,30 @@ func FormatField(n *model.Notification, field string) string {
 		return n.IconPath
 	case "urgency":
 		return n.UrgencyName
+	case "tags", "tag":
+		return strings.Join(n.HistuiTags, ",")
+	case "note":
+		return n.HistuiNote
+	case "urls", "url":
+		return strings.Join(n.URLs(), "\n")
+	case "emails", "email":
+		return strings.Join(n.Emails(), "\n")
+	case "codes", "code":
+		return strings.Join(n.Codes(), "\n")
 	case "all", "full":
 		return fmt.Sprintf("%s\n%s", n.Summary, n.Body)
 	default:
 		return n.Summary
 	}
 }
+
+// IsExtractedField reports whether field is extracted from the notification
+// text rather than stored. These fields read link targets from the body
+// markup, so they need the body before markup is rendered.
+func IsExtractedField(field string) bool {
+	switch strings.ToLower(field) {
+	case "urls", "url", "emails", "email", "codes", "code":
+		return true
+	}
+	return false
+}
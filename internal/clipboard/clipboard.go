@@ -0,0 +1,62 @@
+// Package clipboard copies text to the system clipboard.
+package clipboard
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/config"
+)
+
+// Copy copies text to the system clipboard.
+func Copy(text string, cfg *config.Config) error {
+	// Get clipboard command
+	cmd := DetectCommand(cfg)
+	if cmd == "" {
+		return fmt.Errorf("no clipboard command available")
+	}
+
+	// Parse command
+	parts := strings.Fields(cmd)
+	if len(parts) == 0 {
+		return fmt.Errorf("invalid clipboard command")
+	}
+
+	// Execute with text as stdin
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	c.Stdin = strings.NewReader(text)
+
+	return c.Run()
+}
+
+// DetectCommand returns the clipboard command to use.
+// Returns an empty string if no clipboard tool is available.
+func DetectCommand(cfg *config.Config) string {
+	// Use configured command if specified
+	if cfg != nil && cfg.Clipboard.Command != "" {
+		return cfg.Clipboard.Command
+	}
+
+	// Auto-detect based on environment
+	// Check for Wayland
+	if _, err := exec.LookPath("wl-copy"); err == nil {
+		return "wl-copy"
+	}
+
+	// Check for X11
+	if _, err := exec.LookPath("xclip"); err == nil {
+		return "xclip -selection clipboard"
+	}
+
+	if _, err := exec.LookPath("xsel"); err == nil {
+		return "xsel --clipboard --input"
+	}
+
+	return ""
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// FieldError is a validation error for a specific option. Key is the
+// dotted TOML key, e.g. "display.width", "views.work" or "layout.rules[1]".
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldErrorf returns a FieldError for key with a formatted message.
+func fieldErrorf(key, format string, args ...any) error {
+	return &FieldError{Key: key, Err: fmt.Errorf(format, args...)}
+}
+
+// Problem is an error found in a config file.
+type Problem struct {
+	Line    int    `json:"line,omitempty"`   // 1-based, 0 if unknown
+	Column  int    `json:"column,omitempty"` // 1-based, 0 if unknown
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("%d: %s", p.Line, p.Message)
+	default:
+		return p.Message
+	}
+}
+
+// CheckConfig parses config.toml contents strictly and validates them.
+// Unlike LoadConfig, unknown keys are reported. Problems carry the line
+// they were found on where it can be determined.
+func CheckConfig(data []byte) []Problem {
+	cfg := DefaultConfig()
+	return checkTOML(data, cfg, cfg.Validate)
+}
+
+// CheckDaemonConfig parses histuid.toml contents strictly and validates them.
+func CheckDaemonConfig(data []byte) []Problem {
+	cfg := DefaultDaemonConfig()
+	return checkTOML(data, cfg, cfg.Validate)
+}
+
+// checkTOML decodes data into v, reporting unknown keys, then runs validate.
+func checkTOML(data []byte, v any, validate func() error) []Problem {
+	var problems []Problem
+
+	dec := toml.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+
+	var strictErr *toml.StrictMissingError
+	var decodeErr *toml.DecodeError
+	switch {
+	case err == nil:
+	case errors.As(err, &strictErr):
+		// Unknown keys; the rest of the document was still decoded
+		for _, e := range strictErr.Errors {
+			line, col := e.Position()
+			key := strings.Join(e.Key(), ".")
+			problems = append(problems, Problem{Line: line, Column: col, Key: key, Message: fmt.Sprintf("unknown key %q", key)})
+		}
+	case errors.As(err, &decodeErr):
+		line, col := decodeErr.Position()
+		return append(problems, Problem{
+			Line:    line,
+			Column:  col,
+			Key:     strings.Join(decodeErr.Key(), "."),
+			Message: strings.TrimPrefix(decodeErr.Error(), "toml: "),
+		})
+	default:
+		return append(problems, Problem{Message: err.Error()})
+	}
+
+	if err := validate(); err != nil {
+		p := Problem{Message: err.Error()}
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			p.Key = fieldErr.Key
+			p.Line = locateKey(data, fieldErr.Key)
+		}
+		problems = append(problems, p)
+	}
+
+	return problems
+}
+
+// locateKey returns the 1-based line defining a dotted key in TOML data,
+// falling back to the closest enclosing table. Array tables are addressed
+// by index, e.g. "layout.rules[0].template". Returns 0 if not found.
+func locateKey(data []byte, key string) int {
+	lines := make(map[string]int)
+	record := func(k string, n int) {
+		if _, ok := lines[k]; !ok {
+			lines[k] = n
+		}
+	}
+
+	arrayCounts := make(map[string]int)
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[["):
+			name := normalizeKey(strings.TrimSuffix(strings.SplitN(line[2:], "]]", 2)[0], "]]"))
+			table = fmt.Sprintf("%s[%d]", name, arrayCounts[name])
+			arrayCounts[name]++
+			record(table, n)
+		case strings.HasPrefix(line, "["):
+			table = normalizeKey(strings.SplitN(line[1:], "]", 2)[0])
+			record(table, n)
+		default:
+			k, _, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			full := normalizeKey(k)
+			if table != "" {
+				full = table + "." + full
+			}
+			record(full, n)
+		}
+	}
+
+	for k := key; k != ""; {
+		if n, ok := lines[k]; ok {
+			return n
+		}
+		i := strings.LastIndexAny(k, ".[")
+		if i < 0 {
+			break
+		}
+		k = k[:i]
+	}
+	return 0
+}
+
+// normalizeKey removes whitespace and quotes from a dotted TOML key.
+func normalizeKey(k string) string {
+	parts := strings.Split(strings.TrimSpace(k), ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
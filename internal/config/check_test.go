@@ -0,0 +1,182 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTOML_RoundTrip(t *testing.T) {
+	data, err := DefaultConfigTOML()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# timestamp, app, urgency or relevance\nfield = 'timestamp'")
+	assert.Empty(t, CheckConfig(data))
+
+	cfg := &Config{}
+	require.NoError(t, toml.Unmarshal(data, cfg))
+	changes, err := Diff(DefaultConfig(), cfg)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	data, err = DefaultDaemonConfigTOML()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Popup width in pixels (100-1000)\nwidth = 350")
+	assert.Empty(t, CheckDaemonConfig(data))
+
+	daemonCfg := &DaemonConfig{}
+	require.NoError(t, toml.Unmarshal(data, daemonCfg))
+	changes, err = Diff(DefaultDaemonConfig(), daemonCfg)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Problem
+	}{
+		{
+			name:  "valid",
+			input: "[filter]\nsince = \"24h\"\n",
+		},
+		{
+			name:  "unknown keys",
+			input: "[filter]\nsince = \"24h\"\nsinse = \"1h\"\n\n[colours]\nbg = 1\n",
+			want: []Problem{
+				{Line: 3, Column: 1, Key: "filter.sinse", Message: `unknown key "filter.sinse"`},
+				{Line: 5, Column: 2, Key: "colours", Message: `unknown key "colours"`},
+			},
+		},
+		{
+			name:  "invalid view",
+			input: "[filter]\nlimit = 5\n\n[views.work]\nfilter = \"colour=red\"\n",
+			want: []Problem{
+				{Line: 4, Key: "views.work", Message: `view "work": filter: unknown filter field: colour`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckConfig([]byte(tt.input)))
+		})
+	}
+}
+
+func TestCheckConfig_TypeError(t *testing.T) {
+	problems := CheckConfig([]byte("[filter]\n\nlimit = \"ten\"\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "cannot decode TOML string")
+}
+
+func TestCheckDaemonConfig(t *testing.T) {
+	problems := CheckDaemonConfig([]byte("[display]\nposition = \"top-right\"\nwidth = 50\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, Problem{Line: 3, Key: "display.width", Message: "width must be between 100 and 1000, got 50"}, problems[0])
+	assert.Equal(t, "3: width must be between 100 and 1000, got 50", problems[0].String())
+
+	problems = CheckDaemonConfig([]byte("[layout]\n[[layout.rules]]\ntemplate = \"compact\"\n\n[[layout.rules]]\napp = \"x\"\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 5, problems[0].Line)
+	assert.Equal(t, "layout.rules[1]", problems[0].Key)
+
+	problems = CheckDaemonConfig([]byte("[[retention]]\nfilter = \"urgency=low\"\nmax_age = \"2d\"\n\n[[retention]]\nname = \"chat\"\nfilter = \"ap=slack\"\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 5, problems[0].Line)
+	assert.Equal(t, "retention[1]", problems[0].Key)
+	assert.Contains(t, problems[0].Message, `retention policy "chat": filter: unknown filter field: ap`)
+}
+
+func TestRetentionRules(t *testing.T) {
+	cfg := DefaultDaemonConfig()
+	cfg.Retention = []RetentionPolicy{
+		{Name: "low", Filter: "urgency=low", MaxAge: "2d"},
+		{Filter: "urgency=critical", MaxAge: "0"},
+		{MaxAge: "1w"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	rules, err := cfg.RetentionRules()
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "low", rules[0].Name)
+	assert.Equal(t, 48*time.Hour, rules[0].MaxAge)
+	assert.Equal(t, "urgency=critical", rules[1].Name)
+	assert.Zero(t, rules[1].MaxAge)
+	assert.Equal(t, "all", rules[2].Name)
+	assert.Nil(t, rules[2].Filter)
+
+	cfg.Retention = []RetentionPolicy{{MaxAge: "soon"}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestLocateKey(t *testing.T) {
+	data := []byte(`top = 1
+[display]
+width = 1
+  "gap" = 2
+[views."my view"]
+filter = ""
+[[layout.rules]]
+app = "a"
+[[layout.rules]]
+template = "b"
+`)
+	tests := map[string]int{
+		"top":                      1,
+		"display":                  2,
+		"display.width":            3,
+		"display.gap":              4,
+		"display.missing":          2,
+		"views.my view.filter":     6,
+		"layout.rules[0].app":      8,
+		"layout.rules[1]":          9,
+		"layout.rules[1].template": 10,
+		"nope":                     0,
+	}
+	for key, want := range tests {
+		assert.Equal(t, want, locateKey(data, key), key)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cfg := DefaultDaemonConfig()
+	cfg.Display.Width = 400
+	cfg.Layout.Rules = []LayoutRule{{App: "slack", Template: "compact"}}
+
+	changes, err := Diff(DefaultDaemonConfig(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Key: "display.width", Default: "350", Value: "400"},
+		{Key: "layout.rules[0].app", Value: `"slack"`},
+		{Key: "layout.rules[0].category", Value: `""`},
+		{Key: "layout.rules[0].template", Value: `"compact"`},
+		{Key: "layout.rules[0].urgency", Value: `""`},
+	}, changes)
+}
+
+func TestMigrateDaemonConfig(t *testing.T) {
+	out, changes, err := MigrateDaemonConfig([]byte("[timeouts]\nlow = 5000\nnormal = \"10s\"\ncritical = 0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`timeouts.low: 5000 (milliseconds) -> "5s"`,
+		`timeouts.critical: 0 (milliseconds) -> "0s"`,
+	}, changes)
+
+	cfg := DefaultDaemonConfig()
+	require.NoError(t, toml.Unmarshal(out, cfg))
+	assert.Equal(t, 5000, cfg.Timeouts.Low.Milliseconds())
+	assert.Empty(t, CheckDaemonConfig(out))
+
+	// Already current: unchanged
+	in := []byte("[timeouts]\nlow = \"5s\"\n")
+	out, changes, err = MigrateDaemonConfig(in)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, in, out)
+}
@@ -3,10 +3,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/pelletier/go-toml/v2"
+
+	"github.com/jmylchreest/histui/internal/redact"
 )
 
 // Default configuration values.
@@ -23,53 +26,74 @@ const (
 )
 
 // Config represents the histui configuration.
+// The comment tags document each option in generated config files
+// (see DefaultConfigTOML).
 type Config struct {
-	Filter    FilterConfig    `toml:"filter"`
-	Sort      SortConfig      `toml:"sort"`
-	Prune     PruneConfig     `toml:"prune"`
-	Templates TemplatesConfig `toml:"templates"`
-	TUI       TUIConfig       `toml:"tui"`
-	Clipboard ClipboardConfig `toml:"clipboard"`
+	Filter     FilterConfig          `toml:"filter" comment:"Default filtering for get, pick and the TUI"`
+	Sort       SortConfig            `toml:"sort" comment:"Default sort order"`
+	Prune      PruneConfig           `toml:"prune" comment:"Defaults for histui prune"`
+	Templates  TemplatesConfig       `toml:"templates" comment:"Output templates (Go text/template syntax)"`
+	TUI        TUIConfig             `toml:"tui" comment:"Interactive TUI settings"`
+	Clipboard  ClipboardConfig       `toml:"clipboard" comment:"Clipboard used by copy actions"`
+	Picker     PickerConfig          `toml:"picker" comment:"Launcher used by histui pick"`
+	Redaction  RedactionConfig       `toml:"redaction" comment:"Redaction of secrets before notifications are written to the history\nUser patterns are added as [[redaction.rules]] with name, pattern and\noptionally action, ttl and replacement"`
+	Encryption EncryptionConfig      `toml:"encryption" comment:"Key for the encrypted history (see histui store encrypt)\nThe HISTUI_HISTORY_KEY environment variable takes precedence"`
+	Views      map[string]ViewConfig `toml:"views" comment:"Named queries for get --view, status --view and TUI tabs, e.g.\n[views.critical]\nfilter = \"urgency=critical,dismissed=false\""`
 }
 
 // FilterConfig holds default filtering options.
 type FilterConfig struct {
-	Since string `toml:"since"` // Default time filter (0 = all time)
-	Limit int    `toml:"limit"` // Max notifications (0 = unlimited)
+	Since string `toml:"since" comment:"Only show notifications newer than this (e.g. 48h, 7d; 0 = all time)"`
+	Limit int    `toml:"limit" comment:"Maximum notifications to show (0 = unlimited)"`
 }
 
 // SortConfig holds default sorting options.
 type SortConfig struct {
-	Field string `toml:"field"` // timestamp, app, urgency
-	Order string `toml:"order"` // asc, desc
+	Field string `toml:"field" comment:"timestamp, app, urgency or relevance"`
+	Order string `toml:"order" comment:"asc or desc"`
 }
 
 // PruneConfig holds default prune options.
 type PruneConfig struct {
-	OlderThan string `toml:"older_than"` // Default age threshold
-	Keep      int    `toml:"keep"`       // Max to keep (0 = unlimited)
+	OlderThan string `toml:"older_than" comment:"Remove notifications older than this"`
+	Keep      int    `toml:"keep" comment:"Maximum notifications to keep (0 = unlimited)"`
 }
 
 // TemplatesConfig holds output templates.
 type TemplatesConfig struct {
-	Dmenu     string            `toml:"dmenu"`
-	Full      string            `toml:"full"`
-	Body      string            `toml:"body"`
-	JSON      string            `toml:"json"` // Empty = use built-in marshaling
-	TUIOutput string            `toml:"tui_output"`
-	Custom    map[string]string `toml:"custom"`
+	Dmenu     string            `toml:"dmenu" comment:"One line per notification for dmenu-style launchers"`
+	Full      string            `toml:"full" comment:"Full notification"`
+	Body      string            `toml:"body" comment:"Body only"`
+	JSON      string            `toml:"json" comment:"JSON output (empty = built-in marshaling)"`
+	TUIOutput string            `toml:"tui_output" comment:"Text printed when selecting a notification in the TUI"`
+	Custom    map[string]string `toml:"custom" comment:"Additional named templates for get --format"`
 }
 
 // TUIConfig holds TUI-specific settings.
 type TUIConfig struct {
-	ShowIcons bool `toml:"show_icons"`
-	IconSize  int  `toml:"icon_size"`
-	ShowHelp  bool `toml:"show_help"`
+	ShowIcons bool `toml:"show_icons" comment:"Show application icons"`
+	IconSize  int  `toml:"icon_size" comment:"Icon size in pixels"`
+	ShowHelp  bool `toml:"show_help" comment:"Show the key help bar"`
+	Mouse     bool `toml:"mouse" comment:"Click to select and scroll with the wheel (hold shift to select text)"`
+	Status    bool `toml:"status" comment:"Show Do Not Disturb and histuid's popups above the list"`
+	Timeline  bool `toml:"timeline" comment:"Start with the list grouped by day"`
+
+	Split      bool `toml:"split" comment:"Start with a detail preview beside the list (below it on narrow terminals)"`
+	SplitRatio int  `toml:"split_ratio" comment:"Percentage of the width (or height) given to the list (20-80)"`
+
+	Keys   TUIKeysConfig   `toml:"keys" comment:"Key bindings"`
+	Colors TUIColorsConfig `toml:"colors" comment:"Colors"`
 }
 
 // ClipboardConfig holds clipboard settings (TUI only).
 type ClipboardConfig struct {
-	Command string `toml:"command"` // Auto-detected if empty
+	Command string `toml:"command" comment:"Copy command reading stdin (empty = auto-detect wl-copy, xclip, xsel)"`
+}
+
+// PickerConfig holds settings for `histui pick`.
+type PickerConfig struct {
+	Launcher string `toml:"launcher" comment:"rofi, fuzzel, wofi, dmenu or custom (empty = auto-detect)"`
+	Command  string `toml:"command" comment:"Overrides the launcher executable (full command for custom)"`
 }
 
 // DefaultConfig returns a Config with default values.
@@ -96,13 +120,29 @@ func DefaultConfig() *Config {
 			Custom:    make(map[string]string),
 		},
 		TUI: TUIConfig{
-			ShowIcons: true,
-			IconSize:  DefaultIconSize,
-			ShowHelp:  true,
+			ShowIcons:  true,
+			IconSize:   DefaultIconSize,
+			ShowHelp:   true,
+			Mouse:      true,
+			Status:     true,
+			SplitRatio: DefaultSplitRatio,
+			Keys:       TUIKeysConfig{Preset: DefaultKeyPreset},
+			Colors:     TUIColorsConfig{Preset: DefaultColorPreset},
 		},
 		Clipboard: ClipboardConfig{
 			Command: "", // Auto-detect
 		},
+		Picker: PickerConfig{
+			Launcher: "", // Auto-detect
+		},
+		Redaction: RedactionConfig{
+			Enabled:     true,
+			Detectors:   redact.BuiltinNames(),
+			Action:      DefaultRedactionAction,
+			TTL:         DefaultRedactionTTL,
+			Replacement: DefaultRedactionReplacement,
+		},
+		Views: make(map[string]ViewConfig),
 	}
 }
 
@@ -170,9 +210,36 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	// Validate so typos are reported at load time
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	return cfg, nil
 }
 
+// Validate checks if the configuration is valid.
+func (c *Config) Validate() error {
+	for _, name := range c.ViewNames() {
+		if err := c.Views[name].validate(); err != nil {
+			return &FieldError{Key: "views." + name, Err: fmt.Errorf("view %q: %w", name, err)}
+		}
+	}
+	if _, err := c.Redaction.Redactor(); err != nil {
+		return err
+	}
+	if r := c.TUI.SplitRatio; r != 0 && (r < MinSplitRatio || r > MaxSplitRatio) {
+		return fieldErrorf("tui.split_ratio", "split_ratio must be between %d and %d", MinSplitRatio, MaxSplitRatio)
+	}
+	if _, err := c.TUI.Keys.Bindings(); err != nil {
+		return err
+	}
+	if _, err := c.TUI.Colors.Colors(); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Save writes the configuration to the specified path.
 // Creates parent directories if needed.
 func (c *Config) Save(path string) error {
@@ -226,5 +293,5 @@ func EnsureDataDir() error {
 	if path == "" {
 		return errors.New("unable to determine data directory")
 	}
-	return os.MkdirAll(path, 0755)
+	return os.MkdirAll(path, 0700)
 }
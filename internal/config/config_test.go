@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/store"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -122,6 +125,123 @@ func TestLoadConfig_InvalidTOML(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestLoadConfig_Views(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+
+	content := `
+[views.work]
+filter = "app=slack,dismissed=false"
+sort = "urgency"
+order = "desc"
+limit = 20
+template = "{{.Summary}}"
+
+[views.critical]
+filter = "urgency=critical"
+`
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"critical", "work"}, cfg.ViewNames())
+
+	work, err := cfg.View("work")
+	require.NoError(t, err)
+	assert.Equal(t, "app=slack,dismissed=false", work.Filter)
+	assert.Equal(t, 20, work.Limit)
+	assert.Equal(t, "{{.Summary}}", work.Template)
+
+	_, err = cfg.View("missing")
+	assert.ErrorContains(t, err, "available: critical, work")
+}
+
+func TestLoadConfig_InvalidView(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"unknown field", "[views.bad]\nfilter = \"aap=slack\"\n", "unknown filter field"},
+		{"bad regex", "[views.bad]\nfilter = \"body~=(\"\n", "invalid regex"},
+		{"bad sort", "[views.bad]\nsort = \"size\"\n", "invalid sort field"},
+		{"bad order", "[views.bad]\norder = \"up\"\n", "invalid sort order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := LoadConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `view "bad"`)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_Redaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[redaction]
+detectors = ["otp"]
+action = "replace"
+
+[[redaction.rules]]
+name = "ticket"
+pattern = "TICKET-(\\d+)"
+action = "ttl"
+ttl = "1h"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	r, err := cfg.Redaction.Redactor()
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	rules := r.Rules()
+	require.Len(t, rules, 2)
+	assert.Equal(t, "ticket", rules[0].Name)
+	assert.Equal(t, time.Hour, rules[0].TTL)
+	assert.Equal(t, "otp", rules[1].Name)
+	assert.Equal(t, "Code [otp] TICKET-[ticket]", r.String("Code 123456 TICKET-7"))
+
+	cfg.Redaction.Enabled = false
+	r, err = cfg.Redaction.Redactor()
+	require.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestLoadConfig_InvalidRedaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"bad detector", "[redaction]\ndetectors = [\"ssn\"]\n", `unknown detector "ssn"`},
+		{"bad action", "[redaction]\naction = \"shred\"\n", "invalid redaction action"},
+		{"bad ttl", "[redaction]\nttl = \"0\"\n", "ttl must be positive"},
+		{"bad pattern", "[[redaction.rules]]\nname = \"x\"\npattern = \"(\"\n", "invalid pattern"},
+		{"unnamed rule", "[[redaction.rules]]\npattern = \"x\"\n", "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := LoadConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
 func TestConfig_Save(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "subdir", "config.toml")
@@ -305,3 +425,81 @@ critical = "0"
 	assert.Equal(t, 15000, cfg.GetTimeoutForUrgency(1)) // 15s
 	assert.Equal(t, 0, cfg.GetTimeoutForUrgency(2))     // 0 (never)
 }
+
+func TestLayoutConfig_TemplateFor(t *testing.T) {
+	cfg := LayoutConfig{
+		Template: "default",
+		Rules: []LayoutRule{
+			{Urgency: "critical", Template: "detailed"},
+			{App: "Slack", Category: "im", Template: "compact"},
+			{App: "spotify", Template: "minimal"},
+		},
+	}
+
+	assert.Equal(t, "detailed", cfg.TemplateFor("slack", 2, "im.received"))
+	assert.Equal(t, "compact", cfg.TemplateFor("slack", 1, "im.received"))
+	assert.Equal(t, "compact", cfg.TemplateFor("slack", 1, "im"))
+	assert.Equal(t, "default", cfg.TemplateFor("slack", 1, "imaginary"))
+	assert.Equal(t, "minimal", cfg.TemplateFor("spotify", 0, ""))
+	assert.Equal(t, "default", cfg.TemplateFor("firefox", 1, ""))
+}
+
+func TestDaemonConfig_ValidateLayoutRules(t *testing.T) {
+	cfg := DefaultDaemonConfig()
+	cfg.Layout.Rules = []LayoutRule{{App: "slack", Template: "compact"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Layout.Rules = []LayoutRule{{App: "slack"}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Layout.Rules = []LayoutRule{{Urgency: "urgent", Template: "compact"}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestEncryptionConfig_HistoryKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(HistoryKeyEnv, "")
+
+	// Nothing configured and no default key file
+	key, _, err := EncryptionConfig{}.HistoryKey()
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	// The default key file is used once it exists
+	generated, err := store.GenerateHistoryKey()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(DefaultKeyPath()), 0700))
+	require.NoError(t, os.WriteFile(DefaultKeyPath(), []byte(generated.String()+"\n"), 0600))
+	key, source, err := EncryptionConfig{}.HistoryKey()
+	require.NoError(t, err)
+	assert.Equal(t, generated.String(), key.String())
+	assert.Equal(t, DefaultKeyPath(), source)
+
+	// A configured key file must exist
+	_, _, err = EncryptionConfig{KeyFile: filepath.Join(dir, "missing.key")}.HistoryKey()
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "encryption.key_file", fieldErr.Key)
+
+	// The key command takes precedence over the file
+	key, source, err = EncryptionConfig{KeyCommand: "echo hunter2"}.HistoryKey()
+	require.NoError(t, err)
+	assert.Equal(t, store.SchemePassphrase, key.Scheme())
+	assert.Equal(t, "key_command", source)
+
+	_, _, err = EncryptionConfig{KeyCommand: "false"}.HistoryKey()
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "encryption.key_command", fieldErr.Key)
+
+	// Quoted arguments reach the command whole
+	out, err := runKeyCommand(`printf '%s' "histui  key"`)
+	require.NoError(t, err)
+	assert.Equal(t, "histui  key", string(out))
+
+	// And the environment over both
+	t.Setenv(HistoryKeyEnv, "from-env")
+	_, source, err = EncryptionConfig{KeyCommand: "false"}.HistoryKey()
+	require.NoError(t, err)
+	assert.Equal(t, "$"+HistoryKeyEnv, source)
+}
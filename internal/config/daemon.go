@@ -53,56 +53,61 @@ func (d Duration) Duration() time.Duration {
 // DaemonConfig is the configuration for histuid.
 // Loaded from ~/.config/histui/histuid.toml
 type DaemonConfig struct {
-	Display  DisplayConfig  `toml:"display"`
-	Timeouts TimeoutConfig  `toml:"timeouts"`
-	Behavior BehaviorConfig `toml:"behavior"`
-	Audio    AudioConfig    `toml:"audio"`
-	Theme    ThemeConfig    `toml:"theme"`
-	Layout   LayoutConfig   `toml:"layout"`
-	DnD      DnDConfig      `toml:"dnd"`
-	Mouse    MouseConfig    `toml:"mouse"`
+	Display  DisplayConfig  `toml:"display" comment:"Popup placement and size"`
+	Timeouts TimeoutConfig  `toml:"timeouts" comment:"Popup timeouts per urgency (e.g. \"5s\", \"1m\"; \"0\" = never expire)"`
+	Behavior BehaviorConfig `toml:"behavior" comment:"Popup behavior"`
+	Audio    AudioConfig    `toml:"audio" comment:"Notification sounds"`
+	Theme    ThemeConfig    `toml:"theme" comment:"CSS theme (~/.config/histui/themes/<name>.css or bundled)"`
+	Layout   LayoutConfig   `toml:"layout" comment:"Popup layout templates (~/.config/histui/layouts/<name>.xml or bundled)\nPer-notification templates are added as [[layout.rules]] with app, urgency,\ncategory and template; the first matching rule wins"`
+	DnD      DnDConfig      `toml:"dnd" comment:"Do Not Disturb"`
+	Mouse    MouseConfig    `toml:"mouse" comment:"Mouse buttons: dismiss, do-action, close-all, context-menu or none"`
+
+	// Retention policies applied to the history on startup and hourly
+	// (first match wins; configured as [[retention]])
+	Retention []RetentionPolicy `toml:"retention,omitempty"`
 }
 
 // LayoutConfig contains layout template settings.
 type LayoutConfig struct {
-	Template string `toml:"template"` // Template name without .xml extension
+	Template string       `toml:"template" comment:"Default template name without .xml extension"`
+	Rules    []LayoutRule `toml:"rules,omitempty"` // Per-notification template selection (first match wins)
 }
 
 // DisplayConfig contains display-related settings.
 type DisplayConfig struct {
-	Position   string  `toml:"position"`    // "top-right", "top-left", etc.
-	OffsetX    int     `toml:"offset_x"`    // Pixels from screen edge
-	OffsetY    int     `toml:"offset_y"`    // Pixels from screen edge
-	Width      int     `toml:"width"`       // Popup width in pixels
-	MaxHeight  int     `toml:"max_height"`  // Maximum popup height
-	MaxVisible int     `toml:"max_visible"` // Maximum simultaneous popups
-	Gap        int     `toml:"gap"`         // Gap between stacked popups
-	Monitor    int     `toml:"monitor"`     // 0 = all, 1+ = specific monitor
-	Opacity    float64 `toml:"opacity"`     // 0.0-1.0, background opacity for blur effects
+	Position   string  `toml:"position" comment:"top-left, top-right, top-center, bottom-left, bottom-right or bottom-center"`
+	OffsetX    int     `toml:"offset_x" comment:"Pixels from the screen edge"`
+	OffsetY    int     `toml:"offset_y" comment:"Pixels from the screen edge"`
+	Width      int     `toml:"width" comment:"Popup width in pixels (100-1000)"`
+	MaxHeight  int     `toml:"max_height" comment:"Maximum popup height in pixels"`
+	MaxVisible int     `toml:"max_visible" comment:"Maximum simultaneous popups (1-20)"`
+	Gap        int     `toml:"gap" comment:"Gap between stacked popups in pixels"`
+	Monitor    int     `toml:"monitor" comment:"0 = all monitors, 1+ = specific monitor"`
+	Opacity    float64 `toml:"opacity" comment:"Background opacity (0.0-1.0) for compositor blur"`
 }
 
 // TimeoutConfig contains timeout settings per urgency level.
 // Durations can be specified as "5s", "10s", "1m", etc. or as integer milliseconds.
 // A value of "0" or 0 means never expire.
 type TimeoutConfig struct {
-	Low      Duration `toml:"low"`      // e.g., "5s", "1m", or 5000
-	Normal   Duration `toml:"normal"`   // e.g., "10s", "1m", or 10000
-	Critical Duration `toml:"critical"` // e.g., "0" for never expire
+	Low      Duration `toml:"low"`
+	Normal   Duration `toml:"normal"`
+	Critical Duration `toml:"critical"`
 }
 
 // BehaviorConfig contains behavior settings.
 type BehaviorConfig struct {
-	StackDuplicates bool `toml:"stack_duplicates"` // Combine identical notifications
-	ShowCount       bool `toml:"show_count"`       // Show "(2)" for stacked duplicates
-	PauseOnHover    bool `toml:"pause_on_hover"`   // Pause timeout when mouse hovers
-	HistoryLength   int  `toml:"history_length"`   // Max notifications in session memory
+	StackDuplicates bool `toml:"stack_duplicates" comment:"Combine identical notifications"`
+	ShowCount       bool `toml:"show_count" comment:"Show \"(2)\" for stacked duplicates"`
+	PauseOnHover    bool `toml:"pause_on_hover" comment:"Pause the timeout while the mouse hovers"`
+	HistoryLength   int  `toml:"history_length" comment:"Maximum notifications kept in session memory"`
 }
 
 // AudioConfig contains audio settings.
 type AudioConfig struct {
-	Enabled bool        `toml:"enabled"`
-	Volume  int         `toml:"volume"` // 0-100
-	Sounds  SoundConfig `toml:"sounds"`
+	Enabled bool        `toml:"enabled" comment:"Play sounds"`
+	Volume  int         `toml:"volume" comment:"0-100"`
+	Sounds  SoundConfig `toml:"sounds" comment:"Sound files per urgency (empty = no sound; ~ is expanded)"`
 }
 
 // SoundConfig contains per-urgency sound file paths.
@@ -114,8 +119,8 @@ type SoundConfig struct {
 
 // ThemeConfig contains theme settings.
 type ThemeConfig struct {
-	Name        string `toml:"name"`         // Theme name without .css extension
-	ColorScheme string `toml:"color_scheme"` // "system", "light", or "dark"
+	Name        string `toml:"name" comment:"Theme name without .css extension"`
+	ColorScheme string `toml:"color_scheme" comment:"system, light or dark"`
 }
 
 // ColorScheme represents the color scheme preference.
@@ -134,15 +139,15 @@ func ValidColorSchemes() []ColorScheme {
 
 // DnDConfig contains Do Not Disturb settings.
 type DnDConfig struct {
-	Enabled        bool `toml:"enabled"`         // Initial state
-	CriticalBypass bool `toml:"critical_bypass"` // Show critical even in DnD mode
+	Enabled        bool `toml:"enabled" comment:"Initial state"`
+	CriticalBypass bool `toml:"critical_bypass" comment:"Show critical notifications even in DnD mode"`
 }
 
 // MouseConfig contains mouse button action mappings.
 type MouseConfig struct {
-	Left   string `toml:"left"`   // "dismiss", "do-action", "close-all", "context-menu", "none"
-	Middle string `toml:"middle"` // "dismiss", "do-action", "close-all", "context-menu", "none"
-	Right  string `toml:"right"`  // "dismiss", "do-action", "close-all", "context-menu", "none"
+	Left   string `toml:"left"`
+	Middle string `toml:"middle"`
+	Right  string `toml:"right"`
 }
 
 // MouseAction represents a mouse button action.
@@ -306,20 +311,20 @@ func (c *DaemonConfig) Validate() error {
 		}
 	}
 	if !validPos {
-		return fmt.Errorf("invalid position %q, must be one of: %v", c.Display.Position, ValidPositions())
+		return fieldErrorf("display.position", "invalid position %q, must be one of: %v", c.Display.Position, ValidPositions())
 	}
 
 	// Validate dimensions
 	if c.Display.Width < 100 || c.Display.Width > 1000 {
-		return fmt.Errorf("width must be between 100 and 1000, got %d", c.Display.Width)
+		return fieldErrorf("display.width", "width must be between 100 and 1000, got %d", c.Display.Width)
 	}
 	if c.Display.MaxVisible < 1 || c.Display.MaxVisible > 20 {
-		return fmt.Errorf("max_visible must be between 1 and 20, got %d", c.Display.MaxVisible)
+		return fieldErrorf("display.max_visible", "max_visible must be between 1 and 20, got %d", c.Display.MaxVisible)
 	}
 
 	// Validate volume
 	if c.Audio.Volume < 0 || c.Audio.Volume > 100 {
-		return fmt.Errorf("volume must be between 0 and 100, got %d", c.Audio.Volume)
+		return fieldErrorf("audio.volume", "volume must be between 0 and 100, got %d", c.Audio.Volume)
 	}
 
 	// Validate mouse actions
@@ -330,12 +335,29 @@ func (c *DaemonConfig) Validate() error {
 		string(MouseActionContextMenu): true,
 		string(MouseActionNone):        true,
 	}
-	for _, action := range []string{c.Mouse.Left, c.Mouse.Middle, c.Mouse.Right} {
-		if !validActions[action] {
-			return fmt.Errorf("invalid mouse action %q", action)
+	buttons := []struct{ key, action string }{
+		{"mouse.left", c.Mouse.Left},
+		{"mouse.middle", c.Mouse.Middle},
+		{"mouse.right", c.Mouse.Right},
+	}
+	for _, b := range buttons {
+		if !validActions[b.action] {
+			return fieldErrorf(b.key, "invalid mouse action %q", b.action)
+		}
+	}
+
+	// Validate layout rules
+	for i, rule := range c.Layout.Rules {
+		if err := rule.validate(); err != nil {
+			return &FieldError{Key: fmt.Sprintf("layout.rules[%d]", i), Err: fmt.Errorf("layout rule %d: %w", i+1, err)}
 		}
 	}
 
+	// Validate retention policies
+	if _, err := c.RetentionRules(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Change is an option whose value differs between two configs.
+// Default or Value is empty when the key exists on one side only.
+type Change struct {
+	Key     string `json:"key"`
+	Default string `json:"default"`
+	Value   string `json:"value"`
+}
+
+// Diff returns the options in current that differ from defaults, sorted by
+// key. Both must be the same config type (Config or DaemonConfig).
+func Diff(defaults, current any) ([]Change, error) {
+	a, err := flattenTOML(defaults)
+	if err != nil {
+		return nil, err
+	}
+	b, err := flattenTOML(current)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	var changes []Change
+	for k := range keys {
+		if a[k] != b[k] {
+			changes = append(changes, Change{Key: k, Default: a[k], Value: b[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+// flattenTOML encodes v and returns its leaf values keyed by dotted path.
+// Values are formatted as TOML literals.
+func flattenTOML(v any) (map[string]string, error) {
+	data, err := toml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := toml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	flat := make(map[string]string)
+	var walk func(prefix string, node any)
+	walk = func(prefix string, node any) {
+		switch n := node.(type) {
+		case map[string]any:
+			for k, child := range n {
+				key := k
+				if prefix != "" {
+					key = prefix + "." + k
+				}
+				walk(key, child)
+			}
+		case []any:
+			for i, child := range n {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), child)
+			}
+		default:
+			flat[prefix] = formatTOMLValue(n)
+		}
+	}
+	walk("", tree)
+	return flat, nil
+}
+
+// formatTOMLValue formats a decoded TOML value for display.
+func formatTOMLValue(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	data, err := toml.Marshal(map[string]any{"v": v})
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(data), "v = "))
+}
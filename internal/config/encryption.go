@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// HistoryKeyEnv is the environment variable holding the history key.
+// It takes precedence over the configured key command and file.
+const HistoryKeyEnv = "HISTUI_HISTORY_KEY"
+
+// keyCommandTimeout bounds how long a keyring command may take, e.g. to
+// prompt for an unlock.
+const keyCommandTimeout = 30 * time.Second
+
+// EncryptionConfig locates the key the history file is encrypted with.
+// Encryption is enabled with histui store encrypt; histui and histuid then
+// read and write the history transparently.
+type EncryptionConfig struct {
+	KeyFile    string `toml:"key_file" comment:"File holding an X25519 identity or a passphrase (empty = history.key next to this file, if it exists)"`
+	KeyCommand string `toml:"key_command" comment:"Command printing the key, run by sh, e.g. \"secret-tool lookup service histui\""`
+}
+
+// DefaultKeyPath returns the key file used when key_file is not set.
+func DefaultKeyPath() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "history.key")
+}
+
+// HistoryKey loads the history key from $HISTUI_HISTORY_KEY, the key command
+// or the key file, in that order, and describes where it came from.
+// Returns a nil key if none is configured.
+func (c EncryptionConfig) HistoryKey() (*store.HistoryKey, string, error) {
+	if value := os.Getenv(HistoryKeyEnv); value != "" {
+		key, err := store.ParseHistoryKey([]byte(value))
+		if err != nil {
+			return nil, "", fmt.Errorf("$%s: %w", HistoryKeyEnv, err)
+		}
+		return key, "$" + HistoryKeyEnv, nil
+	}
+
+	if c.KeyCommand != "" {
+		data, err := runKeyCommand(c.KeyCommand)
+		if err != nil {
+			return nil, "", &FieldError{Key: "encryption.key_command", Err: err}
+		}
+		key, err := store.ParseHistoryKey(data)
+		if err != nil {
+			return nil, "", &FieldError{Key: "encryption.key_command", Err: err}
+		}
+		return key, "key_command", nil
+	}
+
+	path := expandPath(c.KeyFile)
+	if path == "" {
+		path = DefaultKeyPath()
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, "", nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", &FieldError{Key: "encryption.key_file", Err: err}
+	}
+	key, err := store.ParseHistoryKey(data)
+	if err != nil {
+		return nil, "", &FieldError{Key: "encryption.key_file", Err: fmt.Errorf("%s: %w", path, err)}
+	}
+	return key, path, nil
+}
+
+// runKeyCommand runs a keyring command through the shell, so quoted
+// arguments work, and returns its output.
+func runKeyCommand(command string) ([]byte, error) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return nil, errors.New("empty key command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyCommandTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", parts[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", parts[0], err)
+	}
+	return out, nil
+}
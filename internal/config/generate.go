@@ -0,0 +1,49 @@
+package config
+
+import (
+	"bytes"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const configHeader = `# histui configuration (~/.config/histui/config.toml)
+# Generated from the built-in defaults; every option is listed.
+# Run 'histui config validate' after editing.
+
+`
+
+const daemonConfigHeader = `# histuid configuration (~/.config/histui/histuid.toml)
+# Generated from the built-in defaults; every option is listed.
+# Run 'histui config validate' after editing; histuid reloads it automatically.
+
+`
+
+// MarshalCommented encodes a Config or DaemonConfig as TOML, with each
+// option preceded by the comment from its struct tag.
+func MarshalCommented(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.SetIndentTables(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DefaultConfigTOML returns a commented config.toml with every default.
+func DefaultConfigTOML() ([]byte, error) {
+	return withHeader(configHeader, DefaultConfig())
+}
+
+// DefaultDaemonConfigTOML returns a commented histuid.toml with every default.
+func DefaultDaemonConfigTOML() ([]byte, error) {
+	return withHeader(daemonConfigHeader, DefaultDaemonConfig())
+}
+
+func withHeader(header string, v any) ([]byte, error) {
+	data, err := MarshalCommented(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), data...), nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+// LayoutRule selects a layout template for notifications matching every
+// non-empty field. Rules are configured as [[layout.rules]] and checked in
+// order; the first match wins.
+type LayoutRule struct {
+	App      string `toml:"app" comment:"Application name (case-insensitive)"`
+	Urgency  string `toml:"urgency" comment:"low, normal or critical"`
+	Category string `toml:"category" comment:"Exact category or class prefix (\"im\" matches \"im.received\")"`
+	Template string `toml:"template" comment:"Template name without .xml extension"`
+}
+
+// Matches reports whether the rule applies to a notification.
+func (r LayoutRule) Matches(app string, urgency int, category string) bool {
+	if r.App != "" && !strings.EqualFold(r.App, app) {
+		return false
+	}
+	if r.Urgency != "" {
+		u, err := core.ParseUrgency(r.Urgency)
+		if err != nil || u != urgency {
+			return false
+		}
+	}
+	if r.Category != "" && category != r.Category && !strings.HasPrefix(category, r.Category+".") {
+		return false
+	}
+	return true
+}
+
+// validate checks the rule has a template and a valid urgency.
+func (r LayoutRule) validate() error {
+	if r.Template == "" {
+		return fmt.Errorf("template is required")
+	}
+	if r.Urgency != "" {
+		if _, err := core.ParseUrgency(r.Urgency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateFor returns the template name for a notification: the first
+// matching rule's template, otherwise the default template.
+func (c LayoutConfig) TemplateFor(app string, urgency int, category string) string {
+	for _, rule := range c.Rules {
+		if rule.Matches(app, urgency, category) {
+			return rule.Template
+		}
+	}
+	return c.Template
+}
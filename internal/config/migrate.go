@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// migration rewrites an outdated option in a decoded config file.
+// apply returns a description of each change it made.
+type migration func(tree map[string]any) []string
+
+// configMigrations upgrade config.toml. Add an entry when an option is
+// renamed or its format changes, and keep accepting the old form on load.
+var configMigrations []migration
+
+// daemonConfigMigrations upgrade histuid.toml.
+var daemonConfigMigrations = []migration{
+	migrateMillisecondTimeouts,
+}
+
+// MigrateConfig upgrades config.toml contents to the current format.
+// Returns the rewritten file and a description of each change; if nothing
+// changed, data is returned as-is with no changes. Comments are not
+// preserved in rewritten files.
+func MigrateConfig(data []byte) ([]byte, []string, error) {
+	return migrate(data, configMigrations, func(b []byte) []Problem { return CheckConfig(b) })
+}
+
+// MigrateDaemonConfig upgrades histuid.toml contents to the current format.
+func MigrateDaemonConfig(data []byte) ([]byte, []string, error) {
+	return migrate(data, daemonConfigMigrations, func(b []byte) []Problem { return CheckDaemonConfig(b) })
+}
+
+func migrate(data []byte, migrations []migration, check func([]byte) []Problem) ([]byte, []string, error) {
+	var tree map[string]any
+	if err := toml.Unmarshal(data, &tree); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	var changes []string
+	for _, m := range migrations {
+		changes = append(changes, m(tree)...)
+	}
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	out, err := toml.Marshal(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problems := check(out); len(problems) > 0 {
+		return nil, nil, fmt.Errorf("migrated config is invalid: %s", problems[0])
+	}
+	return out, changes, nil
+}
+
+// migrateMillisecondTimeouts converts integer millisecond timeouts (the
+// original format) to duration strings.
+func migrateMillisecondTimeouts(tree map[string]any) []string {
+	timeouts, ok := tree["timeouts"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var changes []string
+	for _, key := range []string{"low", "normal", "critical"} {
+		ms, ok := timeouts[key].(int64)
+		if !ok {
+			continue
+		}
+		d := Duration(time.Duration(ms) * time.Millisecond)
+		text, _ := d.MarshalText()
+		timeouts[key] = string(text)
+		changes = append(changes, fmt.Sprintf("timeouts.%s: %d (milliseconds) -> %q", key, ms, text))
+	}
+	return changes
+}
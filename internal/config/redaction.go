@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/redact"
+)
+
+// Default redaction settings.
+const (
+	DefaultRedactionAction      = "ttl"
+	DefaultRedactionTTL         = "15m"
+	DefaultRedactionReplacement = "[{name}]"
+)
+
+// RedactionConfig controls how secrets are removed from notifications before
+// they are written to the history. It applies to histuid and to imports
+// from dunst and stdin alike.
+type RedactionConfig struct {
+	Enabled     bool            `toml:"enabled" comment:"Redact secrets before notifications are stored"`
+	Detectors   []string        `toml:"detectors" comment:"Built-in detectors: otp, token_url, credit_card"`
+	Action      string          `toml:"action" comment:"replace, drop or ttl (keep for ttl, then replace)"`
+	TTL         string          `toml:"ttl" comment:"How long the ttl action keeps the original value (e.g. 15m, 1h)"`
+	Replacement string          `toml:"replacement" comment:"Text substituted by replace; {name} is the detector or rule name"`
+	Rules       []RedactionRule `toml:"rules,omitempty"` // User patterns, configured as [[redaction.rules]]
+}
+
+// RedactionRule is a user-defined redaction pattern.
+type RedactionRule struct {
+	Name        string `toml:"name" comment:"Recorded in histui_redacted when the rule matches"`
+	Pattern     string `toml:"pattern" comment:"Go regular expression; if it has groups only the groups are redacted"`
+	Action      string `toml:"action" comment:"replace, drop or ttl (empty = [redaction] action)"`
+	TTL         string `toml:"ttl" comment:"Overrides [redaction] ttl"`
+	Replacement string `toml:"replacement" comment:"Overrides [redaction] replacement"`
+}
+
+// Redactor builds a redactor from the configuration.
+// Returns nil if redaction is disabled. User rules are applied before the
+// built-in detectors.
+func (c RedactionConfig) Redactor() (*redact.Redactor, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	action, ttl, err := parseRedactionAction(c.Action, c.TTL, "", "")
+	if err != nil {
+		return nil, &FieldError{Key: "redaction", Err: err}
+	}
+
+	var rules []redact.Rule
+	for i, r := range c.Rules {
+		ruleAction, ruleTTL, err := parseRedactionAction(r.Action, r.TTL, c.Action, c.TTL)
+		if err != nil {
+			return nil, &FieldError{Key: fmt.Sprintf("redaction.rules[%d]", i), Err: fmt.Errorf("redaction rule %q: %w", r.Name, err)}
+		}
+		if r.Name == "" {
+			return nil, &FieldError{Key: fmt.Sprintf("redaction.rules[%d]", i), Err: fmt.Errorf("redaction rule %d: name is required", i+1)}
+		}
+		replacement := r.Replacement
+		if replacement == "" {
+			replacement = c.Replacement
+		}
+		rule, err := redact.NewPatternRule(r.Name, r.Pattern, ruleAction, ruleTTL, replacement)
+		if err != nil {
+			return nil, &FieldError{Key: fmt.Sprintf("redaction.rules[%d]", i), Err: fmt.Errorf("redaction rule %q: %w", r.Name, err)}
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, name := range c.Detectors {
+		rule, err := redact.Builtin(name, action, ttl, c.Replacement)
+		if err != nil {
+			return nil, &FieldError{Key: "redaction.detectors", Err: err}
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return redact.New(rules...), nil
+}
+
+// parseRedactionAction parses an action and TTL, falling back to the
+// section defaults for empty values.
+func parseRedactionAction(action, ttl, defaultAction, defaultTTL string) (redact.Action, time.Duration, error) {
+	if action == "" {
+		action = defaultAction
+	}
+	if ttl == "" {
+		ttl = defaultTTL
+	}
+
+	a, err := redact.ParseAction(action)
+	if err != nil {
+		return "", 0, err
+	}
+	if a != redact.ActionTTL {
+		return a, 0, nil
+	}
+
+	d, err := core.ParseDuration(ttl)
+	if err != nil {
+		return "", 0, fmt.Errorf("ttl: %w", err)
+	}
+	if d <= 0 {
+		return "", 0, fmt.Errorf("ttl must be positive for the ttl action, got %q", ttl)
+	}
+	return a, d, nil
+}
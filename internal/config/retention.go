@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+// RetentionPolicy removes history entries matching a filter once they reach
+// a maximum age. Policies are configured as [[retention]] and checked in
+// order; the first matching policy governs a notification, and
+// notifications matching no policy are kept.
+type RetentionPolicy struct {
+	Name   string `toml:"name" comment:"Label shown by histui prune --policy"`
+	Filter string `toml:"filter" comment:"Filter expression (same syntax as --filter; empty matches everything)"`
+	MaxAge string `toml:"max_age" comment:"Remove matches older than this (e.g. 48h, 7d, 2w; 0 = keep forever)"`
+}
+
+// Label returns the policy name, falling back to its filter.
+func (p RetentionPolicy) Label() string {
+	switch {
+	case p.Name != "":
+		return p.Name
+	case p.Filter != "":
+		return p.Filter
+	default:
+		return "all"
+	}
+}
+
+// Rule parses the policy into a retention rule.
+// Relative timestamps in the filter are resolved against the current time.
+func (p RetentionPolicy) Rule() (core.RetentionRule, error) {
+	rule := core.RetentionRule{Name: p.Label()}
+	if p.Filter != "" {
+		expr, err := core.ParseFilter(p.Filter)
+		if err != nil {
+			return rule, fmt.Errorf("filter: %w", err)
+		}
+		rule.Filter = expr
+	}
+	maxAge, err := core.ParseDuration(p.MaxAge)
+	if err != nil {
+		return rule, fmt.Errorf("max_age: %w", err)
+	}
+	if maxAge < 0 {
+		return rule, fmt.Errorf("max_age must not be negative, got %s", p.MaxAge)
+	}
+	rule.MaxAge = maxAge
+	return rule, nil
+}
+
+// RetentionRules parses every retention policy, in order.
+func (c *DaemonConfig) RetentionRules() ([]core.RetentionRule, error) {
+	rules := make([]core.RetentionRule, 0, len(c.Retention))
+	for i, p := range c.Retention {
+		rule, err := p.Rule()
+		if err != nil {
+			return nil, &FieldError{Key: fmt.Sprintf("retention[%d]", i), Err: fmt.Errorf("retention policy %q: %w", p.Label(), err)}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default TUI presets and layout.
+const (
+	DefaultKeyPreset   = "default"
+	DefaultColorPreset = "auto"
+	DefaultSplitRatio  = 50
+	MinSplitRatio      = 20
+	MaxSplitRatio      = 80
+)
+
+// TUIKeysConfig overrides the TUI key bindings. A preset supplies every
+// binding; each action listed here replaces the preset's keys for it.
+// Keys use Bubble Tea names: "a", "A", "ctrl+a", "alt+a", "enter", "esc",
+// "tab", "shift+tab", "up", "pgdown", "home", " " (space) and so on.
+type TUIKeysConfig struct {
+	Preset string `toml:"preset" comment:"default, vim or emacs\nEach action below replaces the preset's keys when set, e.g. dismiss = [\"x\"]"`
+
+	Up              []string `toml:"up" comment:"Move up"`
+	Down            []string `toml:"down" comment:"Move down"`
+	PageUp          []string `toml:"page_up" comment:"Previous page"`
+	PageDown        []string `toml:"page_down" comment:"Next page"`
+	Home            []string `toml:"home" comment:"Go to top"`
+	End             []string `toml:"end" comment:"Go to bottom"`
+	Enter           []string `toml:"enter" comment:"View details"`
+	Back            []string `toml:"back" comment:"Back; clears marks and the search in the list"`
+	Copy            []string `toml:"copy" comment:"Copy body"`
+	CopySummary     []string `toml:"copy_summary" comment:"Copy summary"`
+	CopyJSON        []string `toml:"copy_json" comment:"Copy marked (or all) as JSON"`
+	CopyYAML        []string `toml:"copy_yaml" comment:"Copy marked (or all) as YAML"`
+	CopyCode        []string `toml:"copy_code" comment:"Copy the one-time code found in the notification"`
+	OpenLink        []string `toml:"open_link" comment:"Open a link or email address found in the notification"`
+	Export          []string `toml:"export" comment:"Export marked (or all) to a file"`
+	Dismiss         []string `toml:"dismiss" comment:"Dismiss/undismiss"`
+	Delete          []string `toml:"delete" comment:"Delete permanently"`
+	MarkSeen        []string `toml:"mark_seen" comment:"Mark seen"`
+	Snooze          []string `toml:"snooze" comment:"Snooze/cancel snooze"`
+	Star            []string `toml:"star" comment:"Star/unstar"`
+	Tag             []string `toml:"tag" comment:"Add or remove tags"`
+	Note            []string `toml:"note" comment:"Edit note"`
+	Search          []string `toml:"search" comment:"Search/filter"`
+	Refresh         []string `toml:"refresh" comment:"Refresh"`
+	ToggleDismissed []string `toml:"toggle_dismissed" comment:"Show/hide dismissed and snoozed"`
+	Stats           []string `toml:"stats" comment:"Statistics"`
+	Control         []string `toml:"control" comment:"Do Not Disturb and histuid popup controls"`
+	Timeline        []string `toml:"timeline" comment:"Group the list by day"`
+	Calendar        []string `toml:"calendar" comment:"Activity heatmap by day and hour"`
+	NextView        []string `toml:"next_view" comment:"Next saved view"`
+	PrevView        []string `toml:"prev_view" comment:"Previous saved view"`
+	Preview         []string `toml:"preview" comment:"Show/hide the detail preview"`
+	GrowList        []string `toml:"grow_list" comment:"Give the list more room in the preview layout"`
+	ShrinkList      []string `toml:"shrink_list" comment:"Give the preview more room"`
+	Mark            []string `toml:"mark" comment:"Mark/unmark for bulk actions"`
+	MarkRange       []string `toml:"mark_range" comment:"Start/end a marked range"`
+	MarkAll         []string `toml:"mark_all" comment:"Mark all listed"`
+	Help            []string `toml:"help" comment:"Help"`
+	Quit            []string `toml:"quit" comment:"Quit"`
+}
+
+// defaultKeys are the bindings of the default preset, by action.
+var defaultKeys = map[string][]string{
+	"up":               {"k", "up"},
+	"down":             {"j", "down"},
+	"page_up":          {"pgup", "ctrl+u"},
+	"page_down":        {"pgdown", "ctrl+d"},
+	"home":             {"g", "home"},
+	"end":              {"G", "end"},
+	"enter":            {"enter"},
+	"back":             {"esc", "backspace"},
+	"copy":             {"c"},
+	"copy_summary":     {"s"},
+	"copy_json":        {"C"},
+	"copy_yaml":        {"alt+c"},
+	"copy_code":        {"y"},
+	"open_link":        {"o"},
+	"export":           {"e"},
+	"dismiss":          {"d"},
+	"delete":           {"D"},
+	"mark_seen":        {"m"},
+	"snooze":           {"z"},
+	"star":             {"f"},
+	"tag":              {"t"},
+	"note":             {"n"},
+	"search":           {"/"},
+	"refresh":          {"r"},
+	"toggle_dismissed": {"a"},
+	"stats":            {"S"},
+	"control":          {"H"},
+	"timeline":         {"T"},
+	"calendar":         {"M"},
+	"next_view":        {"tab"},
+	"prev_view":        {"shift+tab"},
+	"preview":          {"p"},
+	"grow_list":        {">"},
+	"shrink_list":      {"<"},
+	"mark":             {" "},
+	"mark_range":       {"V"},
+	"mark_all":         {"*"},
+	"help":             {"?"},
+	"quit":             {"q", "ctrl+c"},
+}
+
+// keyPresets change the default bindings; actions they do not list keep
+// their default keys.
+var keyPresets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"page_up":   {"ctrl+b", "ctrl+u", "pgup"},
+		"page_down": {"ctrl+f", "ctrl+d", "pgdown"},
+	},
+	"emacs": {
+		"up":        {"ctrl+p", "up"},
+		"down":      {"ctrl+n", "down"},
+		"page_up":   {"alt+v", "pgup"},
+		"page_down": {"ctrl+v", "pgdown"},
+		"home":      {"alt+<", "home"},
+		"end":       {"alt+>", "end"},
+		"back":      {"ctrl+g", "esc", "backspace"},
+		"search":    {"ctrl+s", "/"},
+	},
+}
+
+// KeyPresetNames returns the key binding presets in alphabetical order.
+func KeyPresetNames() []string {
+	return sortedKeys(keyPresets)
+}
+
+// Bindings resolves the preset and overrides into keys by action name (the
+// TOML names, e.g. "copy_json"). Returns an error if a key is bound to more
+// than one action.
+func (c TUIKeysConfig) Bindings() (map[string][]string, error) {
+	preset := c.Preset
+	if preset == "" {
+		preset = DefaultKeyPreset
+	}
+	changes, ok := keyPresets[preset]
+	if !ok {
+		return nil, fieldErrorf("tui.keys.preset", "unknown key preset %q (valid: %s)", c.Preset, strings.Join(KeyPresetNames(), ", "))
+	}
+
+	bindings := make(map[string][]string, len(defaultKeys))
+	for action, keys := range defaultKeys {
+		bindings[action] = keys
+	}
+	for action, keys := range changes {
+		bindings[action] = keys
+	}
+
+	overridden := make(map[string]bool)
+	v := reflect.ValueOf(c)
+	for i := range v.NumField() {
+		keys, ok := v.Field(i).Interface().([]string)
+		if !ok || len(keys) == 0 {
+			continue
+		}
+		action, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("toml"), ",")
+		for _, k := range keys {
+			if k == "" || (len(k) > 1 && strings.TrimSpace(k) != k) {
+				return nil, fieldErrorf("tui.keys."+action, "invalid key %q", k)
+			}
+		}
+		bindings[action] = keys
+		overridden[action] = true
+	}
+
+	// Report a conflict against an overridden action, so it points at
+	// the user's line rather than the preset
+	owner := make(map[string]string)
+	for _, action := range sortedKeys(bindings) {
+		for _, k := range bindings[action] {
+			other, taken := owner[k]
+			if !taken {
+				owner[k] = action
+				continue
+			}
+			at := action
+			if overridden[other] && !overridden[action] {
+				at = other
+			}
+			return nil, fieldErrorf("tui.keys."+at, "key %q is bound to both %s and %s", KeyName(k), other, action)
+		}
+	}
+	return bindings, nil
+}
+
+// KeyName makes a key readable in messages and help.
+func KeyName(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// TUIColorsConfig overrides the TUI colors. A preset supplies every color;
+// each one set here replaces the preset's. Colors are ANSI numbers (0-255)
+// or hex (#rgb, #rrggbb).
+type TUIColorsConfig struct {
+	Preset string `toml:"preset" comment:"auto (adapts to the terminal background), dark, light or high-contrast\nEach color below replaces the preset's when set: 0-255 or #rrggbb"`
+
+	Title               string `toml:"title" comment:"Notification summaries in the list"`
+	Description         string `toml:"description" comment:"Second line of list items"`
+	Selected            string `toml:"selected" comment:"Summary and border of the item under the cursor"`
+	SelectedDescription string `toml:"selected_description" comment:"Second line of the item under the cursor"`
+	Dismissed           string `toml:"dismissed" comment:"Dismissed notifications"`
+	Marked              string `toml:"marked" comment:"Marked notifications and the selection count"`
+	Header              string `toml:"header" comment:"Detail and help titles, active view tab"`
+	Label               string `toml:"label" comment:"Detail labels, keybind bar text"`
+	Key                 string `toml:"key" comment:"Keys in the keybind bar and help"`
+	Status              string `toml:"status" comment:"Status messages"`
+	Error               string `toml:"error" comment:"Error messages"`
+}
+
+// colorPresets supply every color by role (the TOML names). The auto preset
+// is empty: the TUI then uses colors that adapt to the terminal background.
+var colorPresets = map[string]map[string]string{
+	"auto": {},
+	"dark": {
+		"title":                "#dddddd",
+		"description":          "#8a8a8a",
+		"selected":             "#ee6ff8",
+		"selected_description": "#ad58b4",
+		"dismissed":            "#5f5f5f",
+		"marked":               "#ffd75f",
+		"header":               "#5fafff",
+		"label":                "#8a8a8a",
+		"key":                  "#87d787",
+		"status":               "#d0d0d0",
+		"error":                "#ff5f5f",
+	},
+	"light": {
+		"title":                "#1a1a1a",
+		"description":          "#6c6c6c",
+		"selected":             "#7d56f4",
+		"selected_description": "#9b79f7",
+		"dismissed":            "#a8a8a8",
+		"marked":               "#af5f00",
+		"header":               "#005fd7",
+		"label":                "#6c6c6c",
+		"key":                  "#008700",
+		"status":               "#303030",
+		"error":                "#d70000",
+	},
+	"high-contrast": {
+		"title":                "15",
+		"description":          "15",
+		"selected":             "11",
+		"selected_description": "11",
+		"dismissed":            "7",
+		"marked":               "14",
+		"header":               "15",
+		"label":                "15",
+		"key":                  "11",
+		"status":               "15",
+		"error":                "9",
+	},
+}
+
+// ColorPresetNames returns the color presets in alphabetical order.
+func ColorPresetNames() []string {
+	return sortedKeys(colorPresets)
+}
+
+// Colors resolves the preset and overrides into colors by role (the TOML
+// names, e.g. "selected_description"). Roles left to the terminal are
+// missing from the map.
+func (c TUIColorsConfig) Colors() (map[string]string, error) {
+	preset := c.Preset
+	if preset == "" {
+		preset = DefaultColorPreset
+	}
+	base, ok := colorPresets[preset]
+	if !ok {
+		return nil, fieldErrorf("tui.colors.preset", "unknown color preset %q (valid: %s)", c.Preset, strings.Join(ColorPresetNames(), ", "))
+	}
+
+	colors := make(map[string]string, len(base))
+	for role, color := range base {
+		colors[role] = color
+	}
+
+	v := reflect.ValueOf(c)
+	for i := range v.NumField() {
+		role, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("toml"), ",")
+		color, ok := v.Field(i).Interface().(string)
+		if !ok || color == "" || role == "preset" {
+			continue
+		}
+		if !validColor(color) {
+			return nil, fieldErrorf("tui.colors."+role, "invalid color %q (use 0-255 or #rrggbb)", color)
+		}
+		colors[role] = color
+	}
+	return colors, nil
+}
+
+// validColor reports whether s is an ANSI color number or a hex color.
+func validColor(s string) bool {
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// sortedKeys returns the keys of a string-keyed map in alphabetical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
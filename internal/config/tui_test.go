@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTUIKeys_Presets(t *testing.T) {
+	for _, preset := range KeyPresetNames() {
+		bindings, err := TUIKeysConfig{Preset: preset}.Bindings()
+		require.NoError(t, err, preset)
+		assert.Len(t, bindings, len(defaultKeys), preset)
+	}
+
+	bindings, err := TUIKeysConfig{Preset: "emacs"}.Bindings()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ctrl+n", "down"}, bindings["down"])
+	assert.Equal(t, []string{"d"}, bindings["dismiss"], "unchanged actions keep the default keys")
+
+	_, err = TUIKeysConfig{Preset: "nano"}.Bindings()
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "tui.keys.preset", fieldErr.Key)
+}
+
+func TestTUIKeys_Overrides(t *testing.T) {
+	bindings, err := TUIKeysConfig{Preset: "vim", Dismiss: []string{"x"}, Star: []string{"d"}}.Bindings()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x"}, bindings["dismiss"])
+	assert.Equal(t, []string{"d"}, bindings["star"])
+	assert.Equal(t, []string{"ctrl+b", "ctrl+u", "pgup"}, bindings["page_up"])
+
+	// A conflict is reported against the overridden action
+	_, err = TUIKeysConfig{Star: []string{"d"}}.Bindings()
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "tui.keys.star", fieldErr.Key)
+	assert.Contains(t, err.Error(), `key "d" is bound to both dismiss and star`)
+
+	_, err = TUIKeysConfig{Mark: []string{" "}, Tag: []string{" "}}.Bindings()
+	assert.ErrorContains(t, err, `key "space" is bound to both mark and tag`)
+}
+
+func TestTUIColors(t *testing.T) {
+	colors, err := TUIColorsConfig{}.Colors()
+	require.NoError(t, err)
+	assert.Empty(t, colors, "auto leaves colors to the TUI")
+
+	colors, err = TUIColorsConfig{Preset: "light", Marked: "#f0a", Error: "196"}.Colors()
+	require.NoError(t, err)
+	assert.Equal(t, "#1a1a1a", colors["title"])
+	assert.Equal(t, "#f0a", colors["marked"])
+	assert.Equal(t, "196", colors["error"])
+
+	for _, preset := range ColorPresetNames() {
+		_, err := TUIColorsConfig{Preset: preset}.Colors()
+		assert.NoError(t, err, preset)
+	}
+
+	for _, bad := range []string{"red", "256", "#12345", "#ggg"} {
+		_, err := TUIColorsConfig{Header: bad}.Colors()
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr, bad)
+		assert.Equal(t, "tui.colors.header", fieldErr.Key)
+	}
+}
+
+func TestCheckConfig_TUIKeys(t *testing.T) {
+	problems := CheckConfig([]byte("[tui.keys]\npreset = \"vim\"\nstar = [\"d\"]\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Equal(t, "tui.keys.star", problems[0].Key)
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+// ViewConfig is a named query shared by `histui get --view`, `histui status --view`
+// and the TUI view tabs.
+type ViewConfig struct {
+	Filter   string `toml:"filter" comment:"Filter expression (same syntax as --filter)"`
+	Sort     string `toml:"sort" comment:"timestamp, app, urgency or relevance (empty = default)"`
+	Order    string `toml:"order" comment:"asc or desc (empty = default)"`
+	Limit    int    `toml:"limit" comment:"Maximum notifications (0 = unlimited)"`
+	Template string `toml:"template" comment:"Output template for get (empty = default)"`
+}
+
+// FilterExpr parses the view's filter expression.
+// Relative timestamps are resolved against the current time on each call.
+func (v ViewConfig) FilterExpr() (*core.FilterExpr, error) {
+	return core.ParseFilter(v.Filter)
+}
+
+// SortOptions returns the view's sort options, falling back to the defaults.
+func (v ViewConfig) SortOptions() core.SortOptions {
+	field, _ := core.ParseSortField(v.Sort)
+	order, _ := core.ParseSortOrder(v.Order)
+	return core.SortOptions{Field: field, Order: order}
+}
+
+// validate checks the view's filter, sort and limit.
+func (v ViewConfig) validate() error {
+	if _, err := v.FilterExpr(); err != nil {
+		return fmt.Errorf("filter: %w", err)
+	}
+	if _, err := core.ParseSortField(v.Sort); err != nil {
+		return err
+	}
+	if _, err := core.ParseSortOrder(v.Order); err != nil {
+		return err
+	}
+	if v.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", v.Limit)
+	}
+	return nil
+}
+
+// ViewNames returns the configured view names in alphabetical order.
+func (c *Config) ViewNames() []string {
+	names := make([]string, 0, len(c.Views))
+	for name := range c.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// View returns the named view, or an error listing the available views.
+func (c *Config) View(name string) (ViewConfig, error) {
+	if v, ok := c.Views[name]; ok {
+		return v, nil
+	}
+	names := c.ViewNames()
+	if len(names) == 0 {
+		return ViewConfig{}, fmt.Errorf("unknown view %q (no views configured)", name)
+	}
+	return ViewConfig{}, fmt.Errorf("unknown view %q (available: %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// FieldType is the kind of value a filter field holds, which decides the
+// operators it takes and how its value is parsed.
+type FieldType string
+
+const (
+	FieldText    FieldType = "text"    // Compared as text
+	FieldBool    FieldType = "bool"    // true or false
+	FieldNumber  FieldType = "number"  // Compared as integers
+	FieldUrgency FieldType = "urgency" // low < normal < critical
+	FieldTime    FieldType = "time"    // Times, dates and ranges
+	FieldTags    FieldType = "tags"    // Text, matching any of a list
+)
+
+// Operators returns the operators a field of this type accepts.
+func (t FieldType) Operators() []FilterOp {
+	switch t {
+	case FieldText, FieldTags:
+		return []FilterOp{FilterOpEqual, FilterOpNotEqual, FilterOpContains, FilterOpRegex}
+	case FieldBool:
+		return []FilterOp{FilterOpEqual, FilterOpNotEqual}
+	default:
+		return []FilterOp{FilterOpEqual, FilterOpNotEqual, FilterOpGreater, FilterOpLess, FilterOpGreaterEq, FilterOpLessEq}
+	}
+}
+
+// Values describes the values a field of this type takes.
+func (t FieldType) Values() string {
+	switch t {
+	case FieldBool:
+		return "true/false"
+	case FieldNumber:
+		return "number"
+	case FieldUrgency:
+		return "low, normal, critical"
+	case FieldTime:
+		return "1h, today, monday, 2026-10-01[ 15:04], a..b"
+	default:
+		return "text"
+	}
+}
+
+// FilterField describes a field filter conditions can test.
+type FilterField struct {
+	Name        string    // Name used in expressions
+	Aliases     []string  // Other accepted names
+	Type        FieldType // Kind of value
+	Description string    // One-line description for help
+
+	// value returns the field's value for n: a string, bool, int, time.Time
+	// or []string by Type, or nil if n has none.
+	value func(n *model.Notification) any
+}
+
+// ExtensionPrefix starts fields that read model.Extensions by JSON key, so
+// "ext.sound_name=bell" tests Extensions.SoundName.
+const ExtensionPrefix = "ext."
+
+// extensions returns n's extensions, or empty ones if it has none.
+func extensions(n *model.Notification) *model.Extensions {
+	if n.Extensions == nil {
+		return &model.Extensions{}
+	}
+	return n.Extensions
+}
+
+// filterFields lists the fields filter expressions can test, in the order
+// help shows them.
+var filterFields = []FilterField{
+	{Name: "app", Aliases: []string{"app_name", "appname"}, Type: FieldText, Description: "Application name",
+		value: func(n *model.Notification) any { return n.AppName }},
+	{Name: "summary", Aliases: []string{"title"}, Type: FieldText, Description: "Notification title",
+		value: func(n *model.Notification) any { return n.Summary }},
+	{Name: "body", Aliases: []string{"message"}, Type: FieldText, Description: "Notification body",
+		value: func(n *model.Notification) any { return n.Body }},
+	{Name: "category", Aliases: []string{"cat"}, Type: FieldText, Description: "Notification category",
+		value: func(n *model.Notification) any { return n.Category }},
+	{Name: "urgency", Aliases: []string{"priority"}, Type: FieldUrgency, Description: "Urgency level",
+		value: func(n *model.Notification) any { return n.Urgency }},
+	{Name: "timestamp", Aliases: []string{"time", "ts"}, Type: FieldTime, Description: "When it was sent",
+		value: func(n *model.Notification) any { return n.TimestampTime() }},
+	{Name: "dismissed", Aliases: []string{"dismiss"}, Type: FieldBool, Description: "Dismissed",
+		value: func(n *model.Notification) any { return n.IsDismissed() }},
+	{Name: "seen", Type: FieldBool, Description: "Seen",
+		value: func(n *model.Notification) any { return n.IsSeen() }},
+	{Name: "snoozed", Aliases: []string{"snooze"}, Type: FieldBool, Description: "Snoozed and not yet due",
+		value: func(n *model.Notification) any { return n.HasPendingSnooze() }},
+	{Name: "acted", Type: FieldBool, Description: "Acted on, e.g. copied",
+		value: func(n *model.Notification) any { return n.IsActed() }},
+	{Name: "starred", Aliases: []string{"star"}, Type: FieldBool, Description: "Starred",
+		value: func(n *model.Notification) any { return n.IsStarred() }},
+	{Name: "tag", Aliases: []string{"tags"}, Type: FieldTags, Description: "User tag (any tag matches)",
+		value: func(n *model.Notification) any { return n.HistuiTags }},
+	{Name: "note", Type: FieldText, Description: "Note text",
+		value: func(n *model.Notification) any { return n.HistuiNote }},
+	{Name: "source", Type: FieldText, Description: "Where histui got it, e.g. dunst, histuid or stdin",
+		value: func(n *model.Notification) any { return n.HistuiSource }},
+	{Name: "imported_at", Aliases: []string{"imported"}, Type: FieldTime, Description: "When histui stored it",
+		value: func(n *model.Notification) any { return n.ImportedAtTime() }},
+	{Name: "icon", Type: FieldText, Description: "Icon path",
+		value: func(n *model.Notification) any { return n.IconPath }},
+	{Name: "desktop_entry", Aliases: []string{"desktop"}, Type: FieldText, Description: "Sender's .desktop file name",
+		value: func(n *model.Notification) any { return extensions(n).DesktopEntry }},
+	{Name: "stack_tag", Type: FieldText, Description: "Dunst stack tag",
+		value: func(n *model.Notification) any { return extensions(n).StackTag }},
+	{Name: "progress", Type: FieldNumber, Description: "Progress bar value, 0-100; never matches without one",
+		value: func(n *model.Notification) any {
+			if n.Extensions == nil || n.Extensions.Progress < 0 {
+				return nil
+			}
+			return n.Extensions.Progress
+		}},
+	{Name: "has_actions", Aliases: []string{"actions"}, Type: FieldBool, Description: "Has actions to invoke",
+		value: func(n *model.Notification) any { return len(extensions(n).Actions) > 0 }},
+	{Name: "resident", Type: FieldBool, Description: "Stays after an action is invoked",
+		value: func(n *model.Notification) any { return extensions(n).Resident }},
+	{Name: "transient", Type: FieldBool, Description: "Asked not to be kept",
+		value: func(n *model.Notification) any { return extensions(n).Transient }},
+}
+
+// FilterFields returns the fields filter expressions can test. Fields
+// starting with ExtensionPrefix are looked up separately; see
+// LookupFilterField.
+func FilterFields() []FilterField {
+	return append([]FilterField(nil), filterFields...)
+}
+
+// LookupFilterField returns the field called name, matching aliases and
+// ignoring case. "ext.<key>" names the model.Extensions field with that JSON
+// key, if it is text, a number or true/false.
+func LookupFilterField(name string) (FilterField, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if key, ok := strings.CutPrefix(name, ExtensionPrefix); ok {
+		return extensionField(key)
+	}
+	for _, f := range filterFields {
+		if f.Name == name {
+			return f, nil
+		}
+		for _, alias := range f.Aliases {
+			if alias == name {
+				return f, nil
+			}
+		}
+	}
+	return FilterField{}, fmt.Errorf("unknown filter field: %s", name)
+}
+
+// extensionField returns the field for the model.Extensions field with JSON
+// key key. Notifications without extensions have the zero value.
+func extensionField(key string) (FilterField, error) {
+	name := ExtensionPrefix + key
+	typ := reflect.TypeFor[model.Extensions]()
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag != key {
+			continue
+		}
+
+		var ft FieldType
+		switch sf.Type.Kind() {
+		case reflect.String:
+			ft = FieldText
+		case reflect.Bool:
+			ft = FieldBool
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			ft = FieldNumber
+		default:
+			return FilterField{}, fmt.Errorf("filter field %s cannot be filtered (not text, a number or true/false)", name)
+		}
+
+		index := sf.Index
+		return FilterField{
+			Name:        name,
+			Type:        ft,
+			Description: "Extension field " + key,
+			value: func(n *model.Notification) any {
+				v := reflect.ValueOf(extensions(n)).Elem().FieldByIndex(index)
+				if ft == FieldNumber {
+					return int(v.Int())
+				}
+				return v.Interface()
+			},
+		}, nil
+	}
+	return FilterField{}, fmt.Errorf("unknown filter field: %s (extension fields: %s)", name, strings.Join(ExtensionKeys(), ", "))
+}
+
+// ExtensionKeys returns the JSON keys of the model.Extensions fields that
+// ext.<key> can test.
+func ExtensionKeys() []string {
+	var keys []string
+	typ := reflect.TypeFor[model.Extensions]()
+	for i := range typ.NumField() {
+		key, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if _, err := extensionField(key); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// supports reports whether fields of type t accept op.
+func (t FieldType) supports(op FilterOp) bool {
+	for _, o := range t.Operators() {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// operatorList joins ops for messages and help: "=, !=, ~, ~=".
+func operatorList(ops []FilterOp) string {
+	parts := make([]string, len(ops))
+	for i, op := range ops {
+		parts[i] = string(op)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestLookupFilterField(t *testing.T) {
+	f, err := LookupFilterField("Desktop")
+	require.NoError(t, err)
+	assert.Equal(t, "desktop_entry", f.Name)
+
+	f, err = LookupFilterField("ext.sound_name")
+	require.NoError(t, err)
+	assert.Equal(t, FieldText, f.Type)
+
+	f, err = LookupFilterField("ext.progress")
+	require.NoError(t, err)
+	assert.Equal(t, FieldNumber, f.Type)
+
+	_, err = LookupFilterField("ext.image_data")
+	assert.ErrorContains(t, err, "cannot be filtered")
+	_, err = LookupFilterField("ext.nope")
+	assert.ErrorContains(t, err, "sound_name")
+	_, err = LookupFilterField("nope")
+	assert.Error(t, err)
+}
+
+func TestParseFilter_Operators(t *testing.T) {
+	for _, ok := range []string{"progress>=50", "progress!=0", "icon~firefox", "imported_at>1h", "has_actions=true", "ext.resident=true"} {
+		_, err := ParseFilter(ok)
+		assert.NoError(t, err, ok)
+	}
+	for _, bad := range []string{"seen>true", "app>slack", "progress~5", "progress=half", "imported_at~today", "ext.transient<true"} {
+		_, err := ParseFilter(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFilterExpr_MatchExtensionFields(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	notifications := []model.Notification{
+		{HistuiID: "plain", HistuiSource: "dunst", HistuiImportedAt: now.Add(-2 * time.Hour).Unix()},
+		{HistuiID: "download", HistuiSource: "histuid", HistuiImportedAt: now.Add(-10 * time.Minute).Unix(),
+			IconPath: "/usr/share/icons/firefox.png",
+			Extensions: &model.Extensions{
+				DesktopEntry: "firefox",
+				Progress:     60,
+				Actions:      []model.Action{{Key: "default", Label: "Open"}},
+			}},
+		{HistuiID: "chat", HistuiSource: "histuid", HistuiImportedAt: now.Add(-5 * time.Minute).Unix(), HistuiActedAt: now.Unix(),
+			Extensions: &model.Extensions{
+				StackTag:  "chat",
+				Progress:  -1,
+				SoundName: "message-new-instant",
+				Resident:  true,
+				Transient: true,
+			}},
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"source=histuid", []string{"download", "chat"}},
+		{"desktop_entry=firefox", []string{"download"}},
+		{"stack_tag~ch", []string{"chat"}},
+		{"progress>=50", []string{"download"}},
+		{"progress!=100", []string{"download"}},
+		{"has_actions=true", []string{"download"}},
+		{"has_actions=false", []string{"plain", "chat"}},
+		{"resident=true,transient=true", []string{"chat"}},
+		{"icon~=firefox\\.png$", []string{"download"}},
+		{"acted=true", []string{"chat"}},
+		{"imported_at>1h", []string{"download", "chat"}},
+		{"ext.sound_name=message-new-instant", []string{"chat"}},
+		{"ext.progress<0", []string{"chat"}},
+		{"ext.transient=false", []string{"plain", "download"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := ParseFilterAt(tt.filter, now)
+			require.NoError(t, err)
+			var got []string
+			for _, n := range FilterWithExpr(notifications, expr) {
+				got = append(got, n.HistuiID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// readmeFieldsStart and readmeFieldsEnd mark the field table in the README.
+const (
+	readmeFieldsStart = "<!-- filter-fields: generated from core.FilterFields -->\n"
+	readmeFieldsEnd   = "<!-- /filter-fields -->"
+)
+
+// readmeFields renders the README's field table from FilterFields.
+func readmeFields() string {
+	var sb strings.Builder
+	sb.WriteString("| Field | Values | Description |\n|---|---|---|\n")
+	for _, f := range FilterFields() {
+		name := "`" + f.Name + "`"
+		if len(f.Aliases) > 0 {
+			name += " (`" + strings.Join(f.Aliases, "`, `") + "`)"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s |\n", name, f.Type.Values(), f.Description)
+	}
+	fmt.Fprintf(&sb, "| `%s<key>` | by field | Extension field by JSON key: `%s` |\n",
+		ExtensionPrefix, strings.Join(ExtensionKeys(), "`, `"))
+	return sb.String()
+}
+
+func TestREADMEFilterFields(t *testing.T) {
+	data, err := os.ReadFile("../../README.md")
+	require.NoError(t, err)
+	_, rest, ok := strings.Cut(string(data), readmeFieldsStart)
+	require.True(t, ok, "README is missing %q", readmeFieldsStart)
+	table, _, ok := strings.Cut(rest, readmeFieldsEnd)
+	require.True(t, ok, "README is missing %q", readmeFieldsEnd)
+
+	assert.Equal(t, readmeFields(), table, "README field table is out of date; replace it with:\n%s", readmeFields())
+}
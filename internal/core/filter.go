@@ -27,15 +27,16 @@ const (
 
 // FilterCondition represents a single filter condition.
 type FilterCondition struct {
-	Field    string   // Field name: app, summary, body, urgency, timestamp, category, dismissed, seen
+	Field    string   // Field name, as listed by FilterFields or ext.<key>
 	Operator FilterOp // Comparison operator
 	Value    string   // Value to compare against
 
 	// Cached parsed values for efficiency
-	regex       *regexp.Regexp // Compiled regex for ~= operator
-	urgencyVal  int            // Parsed urgency value
-	timestampOp time.Time      // Parsed timestamp for comparison
-	boolVal     bool           // Parsed bool value
+	field    FilterField    // Field being tested
+	regex    *regexp.Regexp // Compiled regex for ~= operator
+	intVal   int            // Parsed number or urgency value
+	timeSpan TimeSpan       // Parsed time or range for time comparisons
+	boolVal  bool           // Parsed bool value
 }
 
 // FilterExpr represents a compound filter expression.
@@ -47,6 +48,8 @@ type FilterExpr struct {
 // FilterOptions specifies criteria for filtering notifications.
 type FilterOptions struct {
 	Since     time.Duration // Filter to notifications newer than now-since (0=all)
+	After     time.Time     // Filter to notifications at or after this time (zero=any)
+	Before    time.Time     // Filter to notifications before this time (zero=any)
 	AppFilter string        // Exact match on app name
 	Urgency   *int          // Filter by urgency level (nil=any)
 	Limit     int           // Maximum results (0=unlimited)
@@ -65,6 +68,12 @@ func Filter(notifications []model.Notification, opts FilterOptions) []model.Noti
 				continue
 			}
 		}
+		if !opts.After.IsZero() && n.TimestampTime().Before(opts.After) {
+			continue
+		}
+		if !opts.Before.IsZero() && !n.TimestampTime().Before(opts.Before) {
+			continue
+		}
 
 		// App filter
 		if opts.AppFilter != "" && n.AppName != opts.AppFilter {
@@ -119,6 +128,46 @@ func ParseDuration(s string) (time.Duration, error) {
 	return time.ParseDuration(s)
 }
 
+// whenLayouts are the absolute time formats accepted by ParseWhen.
+var whenLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+// ParseWhen parses a point in the future relative to now.
+// Supports durations (30m, 2h, 1d), clock times (15:00, the next occurrence)
+// and absolute times (2006-01-02 15:04, RFC3339).
+func ParseWhen(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	for _, layout := range whenLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			if !t.After(now) {
+				return time.Time{}, fmt.Errorf("time %s is in the past", s)
+			}
+			return t, nil
+		}
+	}
+
+	d, err := ParseDuration(s)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid time: %s (use a duration like 30m, a clock time like 15:00, or 2006-01-02 15:04)", s)
+	}
+	return now.Add(d), nil
+}
+
 // ParseUrgency parses an urgency string to its integer value.
 // Accepts: low, normal, critical, 0, 1, 2
 func ParseUrgency(s string) (int, error) {
@@ -140,8 +189,9 @@ func ParseUrgency(s string) (int, error) {
 // Format: "field=value,field2~value2,field3>value3"
 // Multiple conditions are comma-separated and ANDed together.
 //
-// Supported fields: app, summary, body, urgency, category, dismissed, seen, timestamp
-// Supported operators: = (equal), != (not equal), ~ (contains), ~= (regex), >, <, >=, <=
+// Fields are listed by FilterFields; ext.<key> tests the model.Extensions
+// field with that JSON key. Operators: = (equal), != (not equal),
+// ~ (contains), ~= (regex), >, <, >=, <=, as the field's type allows.
 //
 // Examples:
 //   - "app=discord" - exact app name match
@@ -150,7 +200,21 @@ func ParseUrgency(s string) (int, error) {
 //   - "app=slack,urgency=critical" - Slack critical notifications
 //   - "body~=(?i)meeting" - body matches regex (case-insensitive "meeting")
 //   - "timestamp>1h" - notifications from the last hour
+//   - "timestamp=yesterday" - notifications from yesterday
+//   - "timestamp=2026-10-01..2026-10-07" - the first week of October
+//   - "tag=work,starred=true" - starred notifications tagged "work"
+//   - "progress>=50,has_actions=true" - half done, with actions
+//   - "ext.sound_name=message-new-instant" - an extension field
+//
+// Relative times are resolved against the current time in the local time
+// zone; see ParseFilterAt.
 func ParseFilter(expr string) (*FilterExpr, error) {
+	return ParseFilterAt(expr, time.Now())
+}
+
+// ParseFilterAt parses a filter expression, resolving relative times
+// against now. Dates and times without an offset are in now's location.
+func ParseFilterAt(expr string, now time.Time) (*FilterExpr, error) {
 	if expr == "" {
 		return &FilterExpr{}, nil
 	}
@@ -166,7 +230,7 @@ func ParseFilter(expr string) (*FilterExpr, error) {
 			continue
 		}
 
-		cond, err := parseCondition(part)
+		cond, err := parseCondition(part, now)
 		if err != nil {
 			return nil, err
 		}
@@ -177,7 +241,7 @@ func ParseFilter(expr string) (*FilterExpr, error) {
 }
 
 // parseCondition parses a single condition like "app=discord" or "body~error"
-func parseCondition(s string) (FilterCondition, error) {
+func parseCondition(s string, now time.Time) (FilterCondition, error) {
 	// Try operators in order of specificity (longest first)
 	operators := []FilterOp{
 		FilterOpNotEqual,  // != (must be before =)
@@ -203,7 +267,7 @@ func parseCondition(s string) (FilterCondition, error) {
 			}
 
 			// Pre-parse and validate based on field type
-			if err := cond.init(); err != nil {
+			if err := cond.init(now); err != nil {
 				return FilterCondition{}, err
 			}
 
@@ -215,40 +279,48 @@ func parseCondition(s string) (FilterCondition, error) {
 }
 
 // init pre-parses and validates the condition value.
-func (c *FilterCondition) init() error {
-	switch c.Field {
-	case "app", "app_name", "appname":
-		c.Field = "app" // Normalize
-	case "summary", "title":
-		c.Field = "summary"
-	case "body", "message":
-		c.Field = "body"
-	case "category", "cat":
-		c.Field = "category"
-	case "urgency", "priority":
-		c.Field = "urgency"
-		// Parse urgency value
+func (c *FilterCondition) init(now time.Time) error {
+	field, err := LookupFilterField(c.Field)
+	if err != nil {
+		return err
+	}
+	c.field = field
+	c.Field = field.Name // Normalize aliases
+
+	if !field.Type.supports(c.Operator) {
+		return fmt.Errorf("operator %s not supported for %s (use %s)", c.Operator, c.Field, operatorList(field.Type.Operators()))
+	}
+
+	switch field.Type {
+	case FieldUrgency:
 		u, err := ParseUrgency(c.Value)
 		if err != nil {
 			return err
 		}
-		c.urgencyVal = u
-	case "dismissed", "dismiss":
-		c.Field = "dismissed"
-		c.boolVal = parseBool(c.Value)
-	case "seen":
-		c.Field = "seen"
+		c.intVal = u
+	case FieldNumber:
+		v, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %s (use a whole number)", c.Field, c.Value)
+		}
+		c.intVal = v
+	case FieldBool:
 		c.boolVal = parseBool(c.Value)
-	case "timestamp", "time", "ts":
-		c.Field = "timestamp"
-		// Parse duration for relative time comparisons
-		dur, err := ParseDuration(c.Value)
+	case FieldTags:
+		if c.Operator == FilterOpEqual || c.Operator == FilterOpNotEqual {
+			c.Value = strings.ToLower(c.Value) // Tags are stored lowercase
+		}
+	case FieldTime:
+		// Ranges only make sense for (in)equality
+		parse := ParseTimeSpan
+		if c.Operator == FilterOpEqual || c.Operator == FilterOpNotEqual {
+			parse = ParseTimeRange
+		}
+		span, err := parse(c.Value, now)
 		if err != nil {
-			return fmt.Errorf("invalid timestamp value: %w", err)
+			return fmt.Errorf("invalid %s value: %w", c.Field, err)
 		}
-		c.timestampOp = time.Now().Add(-dur)
-	default:
-		return fmt.Errorf("unknown filter field: %s", c.Field)
+		c.timeSpan = span
 	}
 
 	// Compile regex if needed
@@ -285,25 +357,35 @@ func (f *FilterExpr) Match(n model.Notification) bool {
 	return true
 }
 
+// HasTime reports whether any condition compares a time field. Relative
+// times in such conditions are fixed when the expression is parsed.
+func (f *FilterExpr) HasTime() bool {
+	for _, cond := range f.Conditions {
+		if cond.field.Type == FieldTime {
+			return true
+		}
+	}
+	return false
+}
+
 // Match tests if a notification matches this single condition.
+// Notifications without a value for the field, like progress on one
+// without a progress bar, never match.
 func (c *FilterCondition) Match(n model.Notification) bool {
-	switch c.Field {
-	case "app":
-		return c.matchString(n.AppName)
-	case "summary":
-		return c.matchString(n.Summary)
-	case "body":
-		return c.matchString(n.Body)
-	case "category":
-		return c.matchString(n.Category)
-	case "urgency":
-		return c.matchInt(n.Urgency, c.urgencyVal)
-	case "dismissed":
-		return c.matchBool(n.IsDismissed())
-	case "seen":
-		return c.matchBool(n.IsSeen())
-	case "timestamp":
-		return c.matchTimestamp(time.Unix(n.Timestamp, 0))
+	if c.field.value == nil {
+		return false
+	}
+	switch v := c.field.value(&n).(type) {
+	case string:
+		return c.matchString(v)
+	case bool:
+		return c.matchBool(v)
+	case int:
+		return c.matchInt(v, c.intVal)
+	case time.Time:
+		return c.matchTimestamp(v)
+	case []string:
+		return c.matchTags(v)
 	default:
 		return false
 	}
@@ -325,6 +407,25 @@ func (c *FilterCondition) matchString(fieldValue string) bool {
 	}
 }
 
+// matchTags matches a tag list. Positive operators match if any tag matches;
+// != matches if no tag equals the value.
+func (c *FilterCondition) matchTags(tags []string) bool {
+	if c.Operator == FilterOpNotEqual {
+		for _, t := range tags {
+			if t == c.Value {
+				return false
+			}
+		}
+		return true
+	}
+	for _, t := range tags {
+		if c.matchString(t) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchInt matches an integer field with numeric comparison.
 func (c *FilterCondition) matchInt(fieldValue, condValue int) bool {
 	switch c.Operator {
@@ -357,17 +458,24 @@ func (c *FilterCondition) matchBool(fieldValue bool) bool {
 	}
 }
 
-// matchTimestamp matches a timestamp field.
+// matchTimestamp matches a time field against the condition's span:
+// = and != test whether it falls in the span, > and < whether it is after
+// or before the whole span, and >= and <= include the span.
 func (c *FilterCondition) matchTimestamp(fieldValue time.Time) bool {
+	span := c.timeSpan
 	switch c.Operator {
+	case FilterOpEqual:
+		return span.Contains(fieldValue)
+	case FilterOpNotEqual:
+		return !span.Contains(fieldValue)
 	case FilterOpGreater:
-		return fieldValue.After(c.timestampOp)
+		return !fieldValue.Before(span.End)
 	case FilterOpLess:
-		return fieldValue.Before(c.timestampOp)
+		return fieldValue.Before(span.Start)
 	case FilterOpGreaterEq:
-		return fieldValue.After(c.timestampOp) || fieldValue.Equal(c.timestampOp)
+		return !fieldValue.Before(span.Start)
 	case FilterOpLessEq:
-		return fieldValue.Before(c.timestampOp) || fieldValue.Equal(c.timestampOp)
+		return fieldValue.Before(span.End)
 	default:
 		return false
 	}
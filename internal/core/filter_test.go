@@ -67,6 +67,25 @@ func TestFilter_BySince(t *testing.T) {
 	assert.Equal(t, "1", result[0].HistuiID)
 }
 
+func TestFilter_ByWindow(t *testing.T) {
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.Local)
+	notifications := []model.Notification{
+		{HistuiID: "1", Timestamp: start.Add(-time.Second).Unix()},
+		{HistuiID: "2", Timestamp: start.Unix()},
+		{HistuiID: "3", Timestamp: start.Add(59 * time.Minute).Unix()},
+		{HistuiID: "4", Timestamp: start.Add(time.Hour).Unix()},
+	}
+
+	result := Filter(notifications, FilterOptions{After: start, Before: start.Add(time.Hour)})
+	require.Len(t, result, 2)
+	assert.Equal(t, "2", result[0].HistuiID)
+	assert.Equal(t, "3", result[1].HistuiID)
+
+	result = Filter(notifications, FilterOptions{After: start.Add(time.Hour)})
+	require.Len(t, result, 1)
+	assert.Equal(t, "4", result[0].HistuiID)
+}
+
 func TestFilter_WithLimit(t *testing.T) {
 	notifications := []model.Notification{
 		{HistuiID: "1"},
@@ -131,6 +150,40 @@ func TestParseDuration(t *testing.T) {
 	}
 }
 
+func TestParseWhen(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		hasError bool
+	}{
+		{"30m", now.Add(30 * time.Minute), false},
+		{"1d", now.Add(24 * time.Hour), false},
+		{"15:00", time.Date(2026, 3, 14, 15, 0, 0, 0, time.Local), false},
+		{"09:00", time.Date(2026, 3, 15, 9, 0, 0, 0, time.Local), false},
+		{"12:30", time.Date(2026, 3, 15, 12, 30, 0, 0, time.Local), false},
+		{"2026-03-20 08:15", time.Date(2026, 3, 20, 8, 15, 0, 0, time.Local), false},
+		{"2026-03-01 08:15", time.Time{}, true},
+		{"0", time.Time{}, true},
+		{"-5m", time.Time{}, true},
+		{"", time.Time{}, true},
+		{"soon", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseWhen(tt.input, now)
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, tt.expected.Equal(result), "got %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseUrgency(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -222,10 +275,10 @@ func TestFilterExpr_Match(t *testing.T) {
 	now := time.Now()
 
 	notifications := []model.Notification{
-		{HistuiID: "1", AppName: "discord", Summary: "New message", Body: "Hello world", Urgency: model.UrgencyNormal, Timestamp: now.Unix()},
-		{HistuiID: "2", AppName: "slack", Summary: "Meeting reminder", Body: "Meeting in 5 minutes", Urgency: model.UrgencyCritical, Timestamp: now.Unix()},
+		{HistuiID: "1", AppName: "discord", Summary: "New message", Body: "Hello world", Urgency: model.UrgencyNormal, HistuiTags: []string{"friends"}, Timestamp: now.Unix()},
+		{HistuiID: "2", AppName: "slack", Summary: "Meeting reminder", Body: "Meeting in 5 minutes", Urgency: model.UrgencyCritical, HistuiTags: []string{"work", "followup"}, HistuiStarred: true, HistuiNote: "Ask about budget", Timestamp: now.Unix()},
 		{HistuiID: "3", AppName: "discord", Summary: "Error occurred", Body: "Something went wrong", Urgency: model.UrgencyCritical, Timestamp: now.Unix()},
-		{HistuiID: "4", AppName: "firefox", Summary: "Download complete", Body: "file.zip downloaded", Urgency: model.UrgencyLow, HistuiDismissedAt: now.Unix(), Timestamp: now.Unix()},
+		{HistuiID: "4", AppName: "firefox", Summary: "Download complete", Body: "file.zip downloaded", Urgency: model.UrgencyLow, HistuiDismissedAt: now.Unix(), HistuiSnoozedUntil: now.Add(time.Hour).Unix(), Timestamp: now.Unix()},
 	}
 
 	tests := []struct {
@@ -242,6 +295,12 @@ func TestFilterExpr_Match(t *testing.T) {
 		{"urgency_less_eq", "urgency<=normal", []string{"1", "4"}},
 		{"dismissed", "dismissed=true", []string{"4"}},
 		{"not_dismissed", "dismissed=false", []string{"1", "2", "3"}},
+		{"snoozed", "snoozed=true", []string{"4"}},
+		{"tag_equal", "tag=Work", []string{"2"}},
+		{"tag_not_equal", "tag!=work", []string{"1", "3", "4"}},
+		{"tag_contains", "tag~follow", []string{"2"}},
+		{"starred", "starred=true", []string{"2"}},
+		{"note_contains", "note~budget", []string{"2"}},
 		{"combined", "app=discord,urgency=critical", []string{"3"}},
 	}
 
@@ -29,20 +29,18 @@ func LookupByIndex(notifications []model.Notification, index int) *model.Notific
 	return &notifications[idx]
 }
 
-// Search finds notifications matching a search term in summary or body.
-// Case-insensitive substring match.
+// Search finds notifications matching a search term in summary, body or
+// app name, fuzzily; see MatchSearch. Order is kept, so sort by
+// SortByRelevance to rank them.
 func Search(notifications []model.Notification, term string) []model.Notification {
-	if term == "" {
+	if strings.TrimSpace(term) == "" {
 		return notifications
 	}
 
-	term = strings.ToLower(term)
 	var result []model.Notification
-
-	for _, n := range notifications {
-		if strings.Contains(strings.ToLower(n.Summary), term) ||
-			strings.Contains(strings.ToLower(n.Body), term) {
-			result = append(result, n)
+	for i := range notifications {
+		if _, ok := MatchSearch(&notifications[i], term); ok {
+			result = append(result, notifications[i])
 		}
 	}
 
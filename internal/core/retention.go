@@ -0,0 +1,49 @@
+package core
+
+import (
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// RetentionRule removes notifications matching Filter once they are older
+// than MaxAge. A zero MaxAge keeps matching notifications forever.
+type RetentionRule struct {
+	Name   string
+	Filter *FilterExpr // nil matches every notification
+	MaxAge time.Duration
+}
+
+// RetentionResult is the outcome of applying retention rules.
+type RetentionResult struct {
+	Expired []model.Notification // Notifications to remove
+	ByRule  []int                // Expired count per rule, in rule order
+	Exempt  int                  // Expired but kept because starred or snoozed
+}
+
+// ApplyRetention returns the notifications that retention rules would remove.
+// Each notification is governed by the first rule whose filter matches it;
+// notifications matching no rule are kept. Pending snoozes are always kept,
+// and starred notifications are kept unless includeStarred is set.
+func ApplyRetention(notifications []model.Notification, rules []RetentionRule, now time.Time, includeStarred bool) RetentionResult {
+	result := RetentionResult{ByRule: make([]int, len(rules))}
+
+	for _, n := range notifications {
+		for i, rule := range rules {
+			if rule.Filter != nil && !rule.Filter.Match(n) {
+				continue
+			}
+			if rule.MaxAge > 0 && time.Unix(n.Timestamp, 0).Before(now.Add(-rule.MaxAge)) {
+				if n.HasPendingSnooze() || (n.IsStarred() && !includeStarred) {
+					result.Exempt++
+				} else {
+					result.Expired = append(result.Expired, n)
+					result.ByRule[i]++
+				}
+			}
+			break
+		}
+	}
+
+	return result
+}
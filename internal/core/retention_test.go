@@ -0,0 +1,61 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local)
+	daysAgo := func(d int) int64 { return now.Add(-time.Duration(d) * 24 * time.Hour).Unix() }
+
+	notifications := []model.Notification{
+		{HistuiID: "old-low", Urgency: model.UrgencyLow, Timestamp: daysAgo(3)},
+		{HistuiID: "new-low", Urgency: model.UrgencyLow, Timestamp: daysAgo(1)},
+		{HistuiID: "old-critical", Urgency: model.UrgencyCritical, Timestamp: daysAgo(90)},
+		{HistuiID: "old-chat", AppName: "slack", Urgency: model.UrgencyNormal, Timestamp: daysAgo(40)},
+		{HistuiID: "chat", AppName: "slack", Urgency: model.UrgencyNormal, Timestamp: daysAgo(20)},
+		{HistuiID: "starred", AppName: "slack", Urgency: model.UrgencyNormal, HistuiStarred: true, Timestamp: daysAgo(40)},
+		{HistuiID: "snoozed", Urgency: model.UrgencyLow, HistuiSnoozedUntil: now.Add(time.Hour).Unix(), Timestamp: daysAgo(3)},
+		{HistuiID: "unmatched", AppName: "mail", Urgency: model.UrgencyNormal, Timestamp: daysAgo(365)},
+	}
+
+	parse := func(s string) *FilterExpr {
+		expr, err := ParseFilter(s)
+		require.NoError(t, err)
+		return expr
+	}
+	rules := []RetentionRule{
+		{Name: "critical", Filter: parse("urgency=critical")}, // Forever
+		{Name: "low", Filter: parse("urgency=low"), MaxAge: 2 * 24 * time.Hour},
+		{Name: "chat", Filter: parse("app=slack"), MaxAge: 30 * 24 * time.Hour},
+	}
+
+	ids := func(ns []model.Notification) []string {
+		out := make([]string, len(ns))
+		for i, n := range ns {
+			out[i] = n.HistuiID
+		}
+		return out
+	}
+
+	result := ApplyRetention(notifications, rules, now, false)
+	assert.ElementsMatch(t, []string{"old-low", "old-chat"}, ids(result.Expired))
+	assert.Equal(t, []int{0, 1, 1}, result.ByRule)
+	assert.Equal(t, 2, result.Exempt, "starred and snoozed are kept")
+
+	result = ApplyRetention(notifications, rules, now, true)
+	assert.ElementsMatch(t, []string{"old-low", "old-chat", "starred"}, ids(result.Expired))
+	assert.Equal(t, 1, result.Exempt)
+
+	// A catch-all rule without a filter governs everything unmatched
+	rules = append(rules, RetentionRule{Name: "rest", MaxAge: 180 * 24 * time.Hour})
+	result = ApplyRetention(notifications, rules, now, false)
+	assert.Contains(t, ids(result.Expired), "unmatched")
+	assert.Equal(t, 1, result.ByRule[3])
+}
@@ -0,0 +1,290 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// Field weights: a match in the summary counts for more than one in the app
+// name, and that more than one in the body.
+const (
+	summaryWeight = 3
+	appWeight     = 2
+	bodyWeight    = 1
+)
+
+// Scores for how a query word matched, before field weights.
+const (
+	scoreExact     = 100 // A whole word
+	scorePrefix    = 80  // The start of a word
+	scoreSubstring = 60  // Inside a word
+	scoreSubseq    = 40  // Its letters in order within a word, less per gap
+	scoreTypo      = 30  // A word an edit away, less per further edit
+	scorePhrase    = 50  // The whole query as typed, on top of its words
+)
+
+// SearchMatch is how well a notification matched a search query.
+type SearchMatch struct {
+	Score int // Higher is a better match
+
+	// Rune indexes of matched characters in each field, for highlighting
+	Summary []int
+	Body    []int
+	App     []int
+}
+
+// searchText is a field prepared for matching: its runes lowercased, and
+// where its words start and end.
+type searchText struct {
+	runes []rune
+	words [][2]int // Rune index ranges, end exclusive
+}
+
+func newSearchText(s string) searchText {
+	t := searchText{runes: lowerRunes(s)}
+	start := -1
+	for i, r := range t.runes {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			t.words = append(t.words, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		t.words = append(t.words, [2]int{start, len(t.runes)})
+	}
+	return t
+}
+
+// lowerRunes lowercases s rune by rune, so indexes match the original's.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// searchTerms splits a query into lowercased words.
+func searchTerms(query string) [][]rune {
+	var terms [][]rune
+	for _, f := range strings.Fields(query) {
+		terms = append(terms, lowerRunes(f))
+	}
+	return terms
+}
+
+// MatchSearch matches a search query against n's summary, app name and
+// body. Each word of the query must match one of them, in any order, as a
+// substring, as letters in order within a word ("mtg" finds "meeting") or,
+// for words of four letters or more, with a typo. The score favours whole
+// words over parts of them and the summary over the app name and body.
+func MatchSearch(n *model.Notification, query string) (SearchMatch, bool) {
+	var m SearchMatch
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return m, true
+	}
+
+	fields := []struct {
+		text      searchText
+		weight    int
+		positions *[]int
+	}{
+		{newSearchText(n.Summary), summaryWeight, &m.Summary},
+		{newSearchText(n.AppName), appWeight, &m.App},
+		{newSearchText(n.Body), bodyWeight, &m.Body},
+	}
+
+	for _, term := range terms {
+		best := 0
+		for _, f := range fields {
+			score, positions := matchTerm(f.text, term)
+			if score == 0 {
+				continue
+			}
+			*f.positions = append(*f.positions, positions...)
+			best = max(best, score*f.weight)
+		}
+		if best == 0 {
+			return SearchMatch{}, false
+		}
+		m.Score += best
+	}
+
+	phrase := lowerRunes(strings.Join(strings.Fields(query), " "))
+	for _, f := range fields {
+		if len(terms) > 1 && indexRunes(f.text.runes, phrase, 0) >= 0 {
+			m.Score += scorePhrase * f.weight
+			break
+		}
+	}
+
+	for _, f := range fields {
+		slices.Sort(*f.positions)
+		*f.positions = slices.Compact(*f.positions)
+	}
+	return m, true
+}
+
+// MatchPositions returns the rune indexes in text matched by the words of
+// query, for highlighting text as shown rather than as stored.
+func MatchPositions(text, query string) []int {
+	t := newSearchText(text)
+	var positions []int
+	for _, term := range searchTerms(query) {
+		if score, p := matchTerm(t, term); score > 0 {
+			positions = append(positions, p...)
+		}
+	}
+	slices.Sort(positions)
+	return slices.Compact(positions)
+}
+
+// matchTerm returns the best score for term in t and the positions it
+// matched, or 0 if it does not match.
+func matchTerm(t searchText, term []rune) (int, []int) {
+	if len(term) == 0 {
+		return 0, nil
+	}
+
+	// Substrings, best where they are whole words or start one
+	best, at := 0, -1
+	for i := indexRunes(t.runes, term, 0); i >= 0; i = indexRunes(t.runes, term, i+1) {
+		end := i + len(term)
+		startsWord := i == 0 || !isWordRune(t.runes[i-1])
+		endsWord := end == len(t.runes) || !isWordRune(t.runes[end])
+		score := scoreSubstring
+		switch {
+		case startsWord && endsWord:
+			score = scoreExact
+		case startsWord:
+			score = scorePrefix
+		}
+		if score > best {
+			best, at = score, i
+		}
+	}
+	if best > 0 {
+		return best, span(at, at+len(term))
+	}
+
+	var positions []int
+	for _, w := range t.words {
+		word := t.runes[w[0]:w[1]]
+		if score, p := matchSubsequence(word, term); score > best {
+			best, positions = score, offset(p, w[0])
+		}
+		if score := matchTypo(word, term); score > best {
+			best, positions = score, span(w[0], w[1])
+		}
+	}
+	return best, positions
+}
+
+// matchSubsequence matches term's letters in order within word, starting
+// with its first letter.
+func matchSubsequence(word, term []rune) (int, []int) {
+	if len(term) < 2 || len(word) < len(term) || word[0] != term[0] {
+		return 0, nil
+	}
+	positions := []int{0}
+	j := 1
+	for i := 1; i < len(word) && j < len(term); i++ {
+		if word[i] == term[j] {
+			positions = append(positions, i)
+			j++
+		}
+	}
+	if j < len(term) {
+		return 0, nil
+	}
+	gaps := positions[len(positions)-1] + 1 - len(term)
+	return max(scoreSubseq-2*gaps, 1), positions
+}
+
+// matchTypo matches term against word, or the start of word, allowing an
+// edit for terms of four letters or more and two from eight.
+func matchTypo(word, term []rune) int {
+	allowed := 0
+	switch {
+	case len(term) >= 8:
+		allowed = 2
+	case len(term) >= 4:
+		allowed = 1
+	default:
+		return 0
+	}
+
+	d := editDistance(word, term)
+	if len(word) > len(term) {
+		d = min(d, editDistance(word[:len(term)], term))
+	}
+	if d == 0 || d > allowed {
+		return 0
+	}
+	return scoreTypo - 10*(d-1)
+}
+
+// editDistance returns the edits (insertions, deletions, substitutions
+// and swaps of neighbouring letters) that turn a into b.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// indexRunes returns the index of the first sub in s at or after from, or
+// -1.
+func indexRunes(s, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// span returns the indexes from start up to end.
+func span(start, end int) []int {
+	positions := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		positions = append(positions, i)
+	}
+	return positions
+}
+
+// offset adds by to each of positions.
+func offset(positions []int, by int) []int {
+	for i := range positions {
+		positions[i] += by
+	}
+	return positions
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestMatchSearch(t *testing.T) {
+	n := model.Notification{
+		AppName: "Thunderbird",
+		Summary: "Team meeting moved",
+		Body:    "The weekly sync is now at 15:00",
+	}
+
+	tests := []struct {
+		query string
+		match bool
+	}{
+		{"meeting", true},
+		{"moved meeting", true}, // Out of order
+		{"meetign", true},       // Swapped letters
+		{"metting", true},       // Wrong letter
+		{"mtg", true},           // Letters in order
+		{"thunder sync", true},  // Across fields
+		{"weekly standup", false},
+		{"xyz", false},
+		{"meet", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		_, ok := MatchSearch(&n, tt.query)
+		assert.Equal(t, tt.match, ok, tt.query)
+	}
+}
+
+func TestMatchSearch_Positions(t *testing.T) {
+	n := model.Notification{AppName: "Slack", Summary: "Über Meeting", Body: "meeting notes"}
+	m, ok := MatchSearch(&n, "meet über")
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 1, 2, 3, 5, 6, 7, 8}, m.Summary)
+	assert.Equal(t, []int{0, 1, 2, 3}, m.Body)
+	assert.Empty(t, m.App)
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, MatchPositions("meeting", "meetign"))
+	assert.Equal(t, []int{0, 3, 6}, MatchPositions("meeting", "mtg"))
+}
+
+func TestMatchSearch_Ranking(t *testing.T) {
+	score := func(n model.Notification, query string) int {
+		m, ok := MatchSearch(&n, query)
+		require.True(t, ok, "%+v should match %q", n, query)
+		return m.Score
+	}
+
+	// Whole words beat prefixes beat substrings beat typos
+	assert.Greater(t, score(model.Notification{Summary: "build failed"}, "build"), score(model.Notification{Summary: "builder failed"}, "build"))
+	assert.Greater(t, score(model.Notification{Summary: "builder failed"}, "build"), score(model.Notification{Summary: "rebuild failed"}, "build"))
+	assert.Greater(t, score(model.Notification{Summary: "rebuild failed"}, "build"), score(model.Notification{Summary: "biuld failed"}, "build"))
+
+	// The summary beats the app name beats the body
+	assert.Greater(t, score(model.Notification{Summary: "deploy"}, "deploy"), score(model.Notification{AppName: "deploy"}, "deploy"))
+	assert.Greater(t, score(model.Notification{AppName: "deploy"}, "deploy"), score(model.Notification{Body: "deploy"}, "deploy"))
+
+	// The words together as typed beat them apart
+	assert.Greater(t, score(model.Notification{Body: "disk full"}, "disk full"), score(model.Notification{Body: "full disk"}, "disk full"))
+}
+
+func TestSort_ByRelevance(t *testing.T) {
+	notifications := []model.Notification{
+		{HistuiID: "body", Body: "deploy finished", Timestamp: 3},
+		{HistuiID: "summary-old", Summary: "Deploy finished", Timestamp: 1},
+		{HistuiID: "summary-new", Summary: "Deploy finished", Timestamp: 2},
+		{HistuiID: "typo", Summary: "Depoly finished", Timestamp: 4},
+	}
+
+	// A typo in the summary ranks below the word itself in the body
+	Sort(notifications, SortOptions{Field: SortByRelevance, Order: SortDesc, Query: "deploy"})
+	var got []string
+	for _, n := range notifications {
+		got = append(got, n.HistuiID)
+	}
+	assert.Equal(t, []string{"summary-new", "summary-old", "body", "typo"}, got)
+}
@@ -2,6 +2,7 @@
 package core
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
@@ -15,6 +16,7 @@ const (
 	SortByTimestamp SortField = "timestamp"
 	SortByApp       SortField = "app"
 	SortByUrgency   SortField = "urgency"
+	SortByRelevance SortField = "relevance" // How well it matches SortOptions.Query
 )
 
 // SortOrder represents ascending or descending order.
@@ -29,6 +31,7 @@ const (
 type SortOptions struct {
 	Field SortField // Field to sort by
 	Order SortOrder // Sort order (asc/desc)
+	Query string    // Search query for SortByRelevance
 }
 
 // DefaultSortOptions returns default sort options (newest first).
@@ -45,6 +48,11 @@ func Sort(notifications []model.Notification, opts SortOptions) {
 		return
 	}
 
+	if opts.Field == SortByRelevance {
+		sortByRelevance(notifications, opts)
+		return
+	}
+
 	sort.SliceStable(notifications, func(i, j int) bool {
 		var less bool
 
@@ -66,28 +74,65 @@ func Sort(notifications []model.Notification, opts SortOptions) {
 	})
 }
 
+// sortByRelevance sorts notifications by how well they match opts.Query,
+// the best last in ascending order, then by timestamp.
+func sortByRelevance(notifications []model.Notification, opts SortOptions) {
+	type scored struct {
+		n     model.Notification
+		score int
+	}
+	ranked := make([]scored, len(notifications))
+	for i := range notifications {
+		m, _ := MatchSearch(&notifications[i], opts.Query)
+		ranked[i] = scored{n: notifications[i], score: m.Score}
+	}
+
+	less := func(a, b scored) bool {
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		return a.n.Timestamp < b.n.Timestamp
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if opts.Order == SortDesc {
+			return less(ranked[j], ranked[i])
+		}
+		return less(ranked[i], ranked[j])
+	})
+
+	for i := range ranked {
+		notifications[i] = ranked[i].n
+	}
+}
+
 // ParseSortField parses a sort field string.
+// Empty input selects timestamp. Unknown fields also fall back to timestamp
+// but return an error so callers can report them.
 func ParseSortField(s string) (SortField, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "timestamp", "time", "t":
+	case "timestamp", "time", "t", "":
 		return SortByTimestamp, nil
 	case "app", "appname", "a":
 		return SortByApp, nil
 	case "urgency", "u":
 		return SortByUrgency, nil
+	case "relevance", "r":
+		return SortByRelevance, nil
 	default:
-		return SortByTimestamp, nil
+		return SortByTimestamp, fmt.Errorf("invalid sort field: %s (use timestamp, app, urgency, or relevance)", s)
 	}
 }
 
 // ParseSortOrder parses a sort order string.
+// Empty input selects desc. Unknown orders also fall back to desc
+// but return an error so callers can report them.
 func ParseSortOrder(s string) (SortOrder, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "asc", "ascending", "a":
 		return SortAsc, nil
-	case "desc", "descending", "d":
+	case "desc", "descending", "d", "":
 		return SortDesc, nil
 	default:
-		return SortDesc, nil
+		return SortDesc, fmt.Errorf("invalid sort order: %s (use asc or desc)", s)
 	}
 }
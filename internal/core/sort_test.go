@@ -132,6 +132,8 @@ func TestParseSortField(t *testing.T) {
 		{"a", SortByApp},
 		{"urgency", SortByUrgency},
 		{"u", SortByUrgency},
+		{"relevance", SortByRelevance},
+		{"r", SortByRelevance},
 		{"unknown", SortByTimestamp}, // defaults to timestamp
 	}
 
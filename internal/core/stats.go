@@ -0,0 +1,197 @@
+// Package core provides filtering, sorting, and lookup logic.
+package core
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// StatsBucket holds counts for a single group (app, urgency, category).
+type StatsBucket struct {
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+	Dismissed int    `json:"dismissed"`
+	Ignored   int    `json:"ignored"`
+}
+
+// Stats holds aggregate analytics over a set of notifications.
+type Stats struct {
+	Total     int `json:"total"`
+	Dismissed int `json:"dismissed"` // Dismissed by the user
+	Seen      int `json:"seen"`      // Seen but not dismissed
+	Ignored   int `json:"ignored"`   // Never seen nor dismissed
+
+	// DismissRatio is dismissed / (dismissed + ignored), 0 when both are zero.
+	DismissRatio float64 `json:"dismiss_ratio"`
+
+	// MedianTimeToDismiss is the median delay between arrival and dismissal.
+	MedianTimeToDismiss time.Duration `json:"-"`
+	MedianDismissSecs   int64         `json:"median_time_to_dismiss_seconds"`
+
+	ByApp      []StatsBucket `json:"by_app"`      // Sorted by count, descending
+	ByUrgency  []StatsBucket `json:"by_urgency"`  // Ordered low, normal, critical
+	ByCategory []StatsBucket `json:"by_category"` // Sorted by count, descending
+	ByHour     [24]int       `json:"by_hour"`     // Local hour of day
+	ByWeekday  [7]int        `json:"by_weekday"`  // Sunday first
+}
+
+// WeekdayNames lists short weekday names in the order used by Stats.ByWeekday.
+var WeekdayNames = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// ComputeStats aggregates notifications by app, urgency, category, hour and weekday.
+// Hours and weekdays use the local timezone.
+func ComputeStats(notifications []model.Notification) Stats {
+	var st Stats
+	st.Total = len(notifications)
+
+	apps := make(map[string]*StatsBucket)
+	categories := make(map[string]*StatsBucket)
+	urgencies := make(map[int]*StatsBucket)
+	var dismissDelays []int64
+
+	for _, n := range notifications {
+		dismissed := n.IsDismissed()
+		ignored := !dismissed && !n.IsSeen()
+
+		switch {
+		case dismissed:
+			st.Dismissed++
+			if delay := n.HistuiDismissedAt - n.Timestamp; delay >= 0 {
+				dismissDelays = append(dismissDelays, delay)
+			}
+		case ignored:
+			st.Ignored++
+		default:
+			st.Seen++
+		}
+
+		category := n.Category
+		if category == "" {
+			category = "(none)"
+		}
+		urgency, ok := model.UrgencyNames[n.Urgency]
+		if !ok {
+			urgency = "unknown"
+		}
+
+		addToBucket(apps, n.AppName, dismissed, ignored)
+		addToBucket(categories, category, dismissed, ignored)
+		if urgencies[n.Urgency] == nil {
+			urgencies[n.Urgency] = &StatsBucket{Name: urgency}
+		}
+		countBucket(urgencies[n.Urgency], dismissed, ignored)
+
+		ts := n.TimestampTime()
+		st.ByHour[ts.Hour()]++
+		st.ByWeekday[ts.Weekday()]++
+	}
+
+	if st.Dismissed+st.Ignored > 0 {
+		st.DismissRatio = float64(st.Dismissed) / float64(st.Dismissed+st.Ignored)
+	}
+
+	if len(dismissDelays) > 0 {
+		st.MedianDismissSecs = median(dismissDelays)
+		st.MedianTimeToDismiss = time.Duration(st.MedianDismissSecs) * time.Second
+	}
+
+	for _, app := range UniqueApps(notifications) {
+		st.ByApp = append(st.ByApp, *apps[app])
+	}
+	sortBuckets(st.ByApp)
+
+	for _, b := range categories {
+		st.ByCategory = append(st.ByCategory, *b)
+	}
+	sortBuckets(st.ByCategory)
+
+	levels := make([]int, 0, len(urgencies))
+	for level := range urgencies {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	for _, level := range levels {
+		st.ByUrgency = append(st.ByUrgency, *urgencies[level])
+	}
+
+	return st
+}
+
+// addToBucket counts a notification in the named bucket, creating it if needed.
+func addToBucket(buckets map[string]*StatsBucket, name string, dismissed, ignored bool) {
+	if name == "" {
+		return
+	}
+	b, ok := buckets[name]
+	if !ok {
+		b = &StatsBucket{Name: name}
+		buckets[name] = b
+	}
+	countBucket(b, dismissed, ignored)
+}
+
+// countBucket increments the bucket counters.
+func countBucket(b *StatsBucket, dismissed, ignored bool) {
+	b.Count++
+	if dismissed {
+		b.Dismissed++
+	}
+	if ignored {
+		b.Ignored++
+	}
+}
+
+// sortBuckets sorts buckets by count (descending), then name.
+func sortBuckets(buckets []StatsBucket) {
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return strings.ToLower(buckets[i].Name) < strings.ToLower(buckets[j].Name)
+	})
+}
+
+// median returns the median of the values (sorts in place).
+func median(values []int64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
+
+// sparkBlocks are the glyphs used by Sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders counts as a single line of block characters.
+// Zero values render as a space so quiet periods stand out.
+func Sparkline(values []int) string {
+	maxVal := 0
+	for _, v := range values {
+		maxVal = max(maxVal, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 || maxVal == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		idx := (v * (len(sparkBlocks) - 1)) / maxVal
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
+
+// Bar renders a horizontal bar of width proportional to value/maxVal.
+func Bar(value, maxVal, width int) string {
+	if maxVal <= 0 || value <= 0 || width <= 0 {
+		return ""
+	}
+	n := max((value*width)/maxVal, 1)
+	return strings.Repeat("█", n)
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestComputeStats(t *testing.T) {
+	base := time.Date(2026, 10, 12, 9, 30, 0, 0, time.Local) // Monday
+	ts := base.Unix()
+
+	notifications := []model.Notification{
+		{AppName: "slack", Urgency: model.UrgencyNormal, Category: "im.received", Timestamp: ts, HistuiDismissedAt: ts + 60},
+		{AppName: "slack", Urgency: model.UrgencyNormal, Category: "im.received", Timestamp: ts, HistuiDismissedAt: ts + 180},
+		{AppName: "slack", Urgency: model.UrgencyLow, Timestamp: ts},
+		{AppName: "mail", Urgency: model.UrgencyCritical, Timestamp: ts + 3600, HistuiSeenAt: ts + 3700},
+		{AppName: "mail", Urgency: model.UrgencyNormal, Timestamp: ts + 3600, HistuiDismissedAt: ts + 3900},
+	}
+
+	st := ComputeStats(notifications)
+
+	assert.Equal(t, 5, st.Total)
+	assert.Equal(t, 3, st.Dismissed)
+	assert.Equal(t, 1, st.Seen)
+	assert.Equal(t, 1, st.Ignored)
+	assert.InDelta(t, 0.75, st.DismissRatio, 0.001)
+	assert.Equal(t, 3*time.Minute, st.MedianTimeToDismiss)
+	assert.Equal(t, int64(180), st.MedianDismissSecs)
+
+	require.Len(t, st.ByApp, 2)
+	assert.Equal(t, StatsBucket{Name: "slack", Count: 3, Dismissed: 2, Ignored: 1}, st.ByApp[0])
+	assert.Equal(t, StatsBucket{Name: "mail", Count: 2, Dismissed: 1}, st.ByApp[1])
+
+	require.Len(t, st.ByUrgency, 3)
+	assert.Equal(t, "low", st.ByUrgency[0].Name)
+	assert.Equal(t, "normal", st.ByUrgency[1].Name)
+	assert.Equal(t, 3, st.ByUrgency[1].Count)
+	assert.Equal(t, "critical", st.ByUrgency[2].Name)
+
+	require.Len(t, st.ByCategory, 2)
+	assert.Equal(t, "(none)", st.ByCategory[0].Name)
+	assert.Equal(t, 3, st.ByCategory[0].Count)
+
+	assert.Equal(t, 3, st.ByHour[9])
+	assert.Equal(t, 2, st.ByHour[10])
+	assert.Equal(t, 5, st.ByWeekday[time.Monday])
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	st := ComputeStats(nil)
+	assert.Equal(t, 0, st.Total)
+	assert.Zero(t, st.DismissRatio)
+	assert.Zero(t, st.MedianTimeToDismiss)
+	assert.Empty(t, st.ByApp)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, " ▁█", Sparkline([]int{0, 1, 8}))
+	assert.Equal(t, "   ", Sparkline([]int{0, 0, 0}))
+	assert.Equal(t, "", Sparkline(nil))
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "", Bar(0, 10, 10))
+	assert.Equal(t, "█████", Bar(5, 10, 10))
+	assert.Equal(t, "█", Bar(1, 100, 10)) // Non-zero values always show
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeSpan is the period a time value names, from Start up to End. Dates,
+// today, yesterday and weekday names name a whole day; durations and times
+// name the second they fall in. A zero Start or End leaves that side open.
+type TimeSpan struct {
+	Start time.Time
+	End   time.Time // Exclusive
+}
+
+// Contains reports whether t falls in the span.
+func (s TimeSpan) Contains(t time.Time) bool {
+	return (s.Start.IsZero() || !t.Before(s.Start)) && (s.End.IsZero() || t.Before(s.End))
+}
+
+// spanLayouts are the absolute formats accepted by ParseTimeSpan, with how
+// long the period each names is. Layouts with an offset are taken as
+// written; the others are in the location of now.
+var spanLayouts = []struct {
+	layout string
+	day    bool
+}{
+	{"2006-01-02", true},
+	{"2006-01-02 15:04", false},
+	{"2006-01-02 15:04:05", false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02T15:04Z07:00", false},
+	{time.RFC3339, false},
+}
+
+// ParseTimeSpan parses a time value relative to now. Supports:
+//   - durations before now: 30m, 48h, 7d, 2w
+//   - now, today, yesterday
+//   - weekday names (monday or mon): the most recent one, today included
+//   - dates and times: 2026-10-01, 2026-10-01 15:04, 2026-10-01T15:04:05
+//   - RFC 3339 with an offset: 2026-10-01T15:04:05Z, 2026-10-01T15:04+02:00
+//
+// Values without an offset are in now's location, which is the local time
+// zone ($TZ) for the CLI.
+func ParseTimeSpan(s string, now time.Time) (TimeSpan, error) {
+	s = strings.TrimSpace(s)
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	day := func(t time.Time) TimeSpan {
+		return TimeSpan{Start: t, End: t.AddDate(0, 0, 1)}
+	}
+	second := func(t time.Time) TimeSpan {
+		t = t.Truncate(time.Second)
+		return TimeSpan{Start: t, End: t.Add(time.Second)}
+	}
+
+	switch lower := strings.ToLower(s); lower {
+	case "":
+		return TimeSpan{}, fmt.Errorf("empty time")
+	case "now":
+		return second(now), nil
+	case "today":
+		return day(today), nil
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1)), nil
+	default:
+		if wd, ok := parseWeekday(lower); ok {
+			back := (int(now.Weekday()) - int(wd) + 7) % 7
+			return day(today.AddDate(0, 0, -back)), nil
+		}
+	}
+
+	for _, l := range spanLayouts {
+		t, err := time.ParseInLocation(l.layout, s, loc)
+		if err != nil {
+			continue
+		}
+		if l.day {
+			return day(t), nil
+		}
+		return second(t), nil
+	}
+
+	d, err := ParseDuration(s)
+	if err != nil || d < 0 {
+		return TimeSpan{}, fmt.Errorf("invalid time: %s (use a duration like 7d, today, yesterday, a weekday, 2006-01-02 or 2006-01-02 15:04)", s)
+	}
+	return second(now.Add(-d)), nil
+}
+
+// ParseTimeRange parses "a..b" as the span from the start of a to the end
+// of b, so "2026-10-01..2026-10-07" includes all of October 7th. Either
+// side may be left out for an open range. A single value is its own span.
+func ParseTimeRange(s string, now time.Time) (TimeSpan, error) {
+	from, to, ok := strings.Cut(s, "..")
+	if !ok {
+		return ParseTimeSpan(s, now)
+	}
+
+	var span TimeSpan
+	if strings.TrimSpace(from) != "" {
+		start, err := ParseTimeSpan(from, now)
+		if err != nil {
+			return TimeSpan{}, err
+		}
+		span.Start = start.Start
+	}
+	if strings.TrimSpace(to) != "" {
+		end, err := ParseTimeSpan(to, now)
+		if err != nil {
+			return TimeSpan{}, err
+		}
+		span.End = end.End
+	}
+	if span.Start.IsZero() && span.End.IsZero() {
+		return TimeSpan{}, fmt.Errorf("invalid time range: %s", s)
+	}
+	if !span.Start.IsZero() && !span.End.IsZero() && !span.Start.Before(span.End) {
+		return TimeSpan{}, fmt.Errorf("invalid time range: %s (ends before it starts)", s)
+	}
+	return span, nil
+}
+
+// parseWeekday parses a weekday name or its three-letter abbreviation.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// SetTimeRange limits opts to the --since and --until values, relative to
+// now. Since includes its whole period and until does too, so "--since
+// monday --until yesterday" covers both days. Empty values, and since "0",
+// leave that side open.
+func (opts *FilterOptions) SetTimeRange(since, until string, now time.Time) error {
+	if since = strings.TrimSpace(since); since != "" && since != "0" {
+		span, err := ParseTimeSpan(since, now)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.After = span.Start
+	}
+	if until = strings.TrimSpace(until); until != "" {
+		span, err := ParseTimeSpan(until, now)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		opts.Before = span.End
+	}
+	return nil
+}
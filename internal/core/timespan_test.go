@@ -0,0 +1,158 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestParseTimeSpan(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Wednesday
+	now := time.Date(2026, 10, 14, 10, 30, 15, 0, berlin)
+	day := func(y int, m time.Month, d int) TimeSpan {
+		start := time.Date(y, m, d, 0, 0, 0, 0, berlin)
+		return TimeSpan{Start: start, End: start.AddDate(0, 0, 1)}
+	}
+	second := func(t time.Time) TimeSpan {
+		return TimeSpan{Start: t, End: t.Add(time.Second)}
+	}
+
+	tests := []struct {
+		input string
+		want  TimeSpan
+	}{
+		{"2h", second(now.Add(-2 * time.Hour))},
+		{"7d", second(now.AddDate(0, 0, -7))},
+		{"now", second(now)},
+		{"today", day(2026, 10, 14)},
+		{"Yesterday", day(2026, 10, 13)},
+		{"wednesday", day(2026, 10, 14)},
+		{"mon", day(2026, 10, 12)},
+		{"thu", day(2026, 10, 8)},
+		{"2026-10-01", day(2026, 10, 1)},
+		{"2026-10-01 15:04", second(time.Date(2026, 10, 1, 15, 4, 0, 0, berlin))},
+		{"2026-10-01T15:04:05", second(time.Date(2026, 10, 1, 15, 4, 5, 0, berlin))},
+		{"2026-10-01T15:04Z", second(time.Date(2026, 10, 1, 15, 4, 0, 0, time.UTC))},
+		{"2026-10-01T15:04:05+02:00", second(time.Date(2026, 10, 1, 13, 4, 5, 0, time.UTC))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeSpan(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Start.Equal(got.Start), "start %s, want %s", got.Start, tt.want.Start)
+			assert.True(t, tt.want.End.Equal(got.End), "end %s, want %s", got.End, tt.want.End)
+		})
+	}
+
+	for _, bad := range []string{"", "soon", "2026-13-01", "-1h"} {
+		_, err := ParseTimeSpan(bad, now)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseTimeSpan_DSTDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 26, 12, 0, 0, 0, berlin)
+
+	// Clocks went back on the 25th, so it lasted 25 hours
+	span, err := ParseTimeSpan("yesterday", now)
+	require.NoError(t, err)
+	assert.Equal(t, 25*time.Hour, span.End.Sub(span.Start))
+}
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+
+	span, err := ParseTimeRange("2026-10-01..2026-10-07", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), span.Start)
+	assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC), span.End)
+	assert.True(t, span.Contains(time.Date(2026, 10, 7, 23, 59, 59, 0, time.UTC)))
+	assert.False(t, span.Contains(span.End))
+
+	span, err = ParseTimeRange("monday..", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), span.Start)
+	assert.True(t, span.End.IsZero())
+	assert.True(t, span.Contains(now.AddDate(1, 0, 0)))
+
+	span, err = ParseTimeRange("..2026-10-01 12:00", now)
+	require.NoError(t, err)
+	assert.True(t, span.Start.IsZero())
+	assert.True(t, span.Contains(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, span.Contains(time.Date(2026, 10, 1, 12, 0, 1, 0, time.UTC)))
+
+	for _, bad := range []string{"..", "2026-10-07..2026-10-01", "2026-10-01..later"} {
+		_, err := ParseTimeRange(bad, now)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFilterOptions_SetTimeRange(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	var opts FilterOptions
+	require.NoError(t, opts.SetTimeRange("monday", "yesterday", now))
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), opts.After)
+	assert.Equal(t, time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), opts.Before)
+
+	opts = FilterOptions{}
+	require.NoError(t, opts.SetTimeRange("0", "", now))
+	assert.True(t, opts.After.IsZero(), "0 is all time")
+	assert.True(t, opts.Before.IsZero())
+
+	assert.ErrorContains(t, opts.SetTimeRange("", "whenever", now), "--until")
+}
+
+func TestFilterExpr_MatchTimestamp(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	at := func(id string, ts time.Time) model.Notification {
+		return model.Notification{HistuiID: id, Timestamp: ts.Unix()}
+	}
+	notifications := []model.Notification{
+		at("recent", now.Add(-30*time.Minute)),
+		at("today", time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)),
+		at("yesterday", time.Date(2026, 10, 13, 23, 59, 59, 0, time.UTC)),
+		at("oct1", time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)),
+		at("oct7", time.Date(2026, 10, 7, 18, 0, 0, 0, time.UTC)),
+		at("sep", time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"timestamp>1h", []string{"recent"}},
+		{"timestamp<1h", []string{"today", "yesterday", "oct1", "oct7", "sep"}},
+		{"timestamp=today", []string{"recent", "today"}},
+		{"timestamp!=today", []string{"yesterday", "oct1", "oct7", "sep"}},
+		{"timestamp=yesterday", []string{"yesterday"}},
+		{"timestamp=2026-10-01..2026-10-07", []string{"oct1", "oct7"}},
+		{"timestamp=..2026-09-30", []string{"sep"}},
+		{"timestamp>2026-10-07", []string{"recent", "today", "yesterday"}},
+		{"timestamp>=2026-10-07", []string{"recent", "today", "yesterday", "oct7"}},
+		{"timestamp<2026-10-01", []string{"sep"}},
+		{"timestamp<=2026-10-01", []string{"oct1", "sep"}},
+		{"timestamp>=2026-10-14T00:00Z", []string{"recent", "today"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := ParseFilterAt(tt.filter, now)
+			require.NoError(t, err)
+			var got []string
+			for _, n := range FilterWithExpr(notifications, expr) {
+				got = append(got, n.HistuiID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ParseFilterAt("timestamp>2026-10-01..2026-10-07", now)
+	assert.Error(t, err, "ranges only with = and !=")
+}
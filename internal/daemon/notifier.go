@@ -3,6 +3,7 @@ package daemon
 
 import (
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -177,6 +178,26 @@ func (n *InternalNotifier) NotifyThemeError(err error) {
 	)
 }
 
+// NotifyLayoutReloaded sends a notification about layout templates being reloaded.
+func (n *InternalNotifier) NotifyLayoutReloaded(names []string) {
+	n.Notify(
+		"layout-reload",
+		"Layouts Reloaded",
+		"Layout templates reloaded: "+strings.Join(names, ", ")+".",
+		NotificationLevelInfo,
+	)
+}
+
+// NotifyLayoutError sends a notification about layout template errors.
+func (n *InternalNotifier) NotifyLayoutError(err error) {
+	n.Notify(
+		"layout-error",
+		"Layout Error",
+		"Invalid layout template:\n"+err.Error(),
+		NotificationLevelWarning,
+	)
+}
+
 // NotifyDnDChanged sends a notification about DnD state change.
 func (n *InternalNotifier) NotifyDnDChanged(enabled bool, reason string) {
 	var summary, body string
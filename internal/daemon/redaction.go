@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/redact"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// DefaultRedactionInterval is how often histuid redacts values whose
+// redaction TTL has passed.
+const DefaultRedactionInterval = time.Minute
+
+// RedactionExpirer redacts values kept by TTL redaction rules once their
+// TTL passes, on startup and at a fixed interval.
+type RedactionExpirer struct {
+	mu       sync.Mutex
+	logger   *slog.Logger
+	store    *store.Store
+	redactor *redact.Redactor
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewRedactionExpirer creates an expirer for the given store and redactor.
+func NewRedactionExpirer(s *store.Store, r *redact.Redactor, logger *slog.Logger) *RedactionExpirer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RedactionExpirer{
+		logger:   logger,
+		store:    s,
+		redactor: r,
+	}
+}
+
+// Start expires due redactions immediately and then every interval until
+// the context is cancelled or Stop is called.
+func (e *RedactionExpirer) Start(ctx context.Context, interval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.cancel = cancel
+	e.mu.Unlock()
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		e.Run()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.Run()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic expiry.
+func (e *RedactionExpirer) Stop() {
+	e.mu.Lock()
+	cancel := e.cancel
+	e.cancel = nil
+	e.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		e.wg.Wait()
+	}
+}
+
+// Run expires due redactions once and returns the number of notifications
+// changed.
+func (e *RedactionExpirer) Run() int {
+	count, err := e.redactor.ExpireDue(e.store, time.Now())
+	if err != nil {
+		e.logger.Warn("failed to expire redactions", "error", err)
+		return 0
+	}
+	if count > 0 {
+		e.logger.Debug("expired redactions", "count", count)
+	}
+	return count
+}
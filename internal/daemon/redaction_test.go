@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/redact"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func TestRedactionExpirer(t *testing.T) {
+	s := store.NewStore(nil)
+	defer s.Close()
+
+	past := time.Now().Add(-time.Minute).Unix()
+	for _, n := range []model.Notification{
+		{HistuiID: "due", HistuiSource: "test", AppName: "bank", Summary: "1", Body: "Your code is 135790", HistuiRedactAt: past, Timestamp: past},
+		{HistuiID: "pending", HistuiSource: "test", AppName: "bank", Summary: "2", Body: "Your code is 246801", HistuiRedactAt: time.Now().Add(time.Hour).Unix(), Timestamp: past},
+	} {
+		require.NoError(t, s.Add(n))
+	}
+
+	rule, err := redact.Builtin(redact.DetectorOTP, redact.ActionTTL, 15*time.Minute, "[{name}]")
+	require.NoError(t, err)
+
+	e := NewRedactionExpirer(s, redact.New(rule), nil)
+	e.Start(context.Background(), time.Hour)
+	defer e.Stop()
+
+	// Start expires due redactions straight away
+	require.Eventually(t, func() bool { return s.GetByID("due").Body == "Your code is [otp]" }, time.Second, 10*time.Millisecond)
+	assert.Zero(t, s.GetByID("due").HistuiRedactAt)
+	assert.Equal(t, "Your code is 246801", s.GetByID("pending").Body)
+	assert.Equal(t, 0, e.Run())
+}
@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/config"
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// DefaultRetentionInterval is how often histuid applies retention policies.
+const DefaultRetentionInterval = time.Hour
+
+// Pruner applies retention policies to the history store on startup and
+// at a fixed interval.
+type Pruner struct {
+	mu       sync.Mutex
+	logger   *slog.Logger
+	store    *store.Store
+	policies []config.RetentionPolicy
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewPruner creates a pruner for the given store.
+func NewPruner(s *store.Store, logger *slog.Logger) *Pruner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Pruner{
+		logger: logger,
+		store:  s,
+	}
+}
+
+// SetPolicies replaces the retention policies, e.g. after a config reload.
+func (p *Pruner) SetPolicies(policies []config.RetentionPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policies = policies
+}
+
+// Start applies the policies immediately and then every interval until
+// the context is cancelled or Stop is called.
+func (p *Pruner) Start(ctx context.Context, interval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	p.mu.Lock()
+	p.cancel = cancel
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		p.Run()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.Run()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic pruning.
+func (p *Pruner) Stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		p.wg.Wait()
+	}
+}
+
+// Run applies the retention policies once and returns the number of
+// notifications removed.
+func (p *Pruner) Run() int {
+	p.mu.Lock()
+	cfg := config.DaemonConfig{Retention: p.policies}
+	p.mu.Unlock()
+
+	if len(cfg.Retention) == 0 {
+		return 0
+	}
+
+	// Parse on every run so relative timestamps in filters stay current
+	rules, err := cfg.RetentionRules()
+	if err != nil {
+		p.logger.Warn("invalid retention policy", "error", err)
+		return 0
+	}
+
+	result := core.ApplyRetention(p.store.All(), rules, time.Now(), false)
+	if len(result.Expired) == 0 {
+		return 0
+	}
+
+	removed := 0
+	err = p.store.Batch(func(tx *store.Tx) error {
+		for _, n := range result.Expired {
+			if tx.Delete(n.HistuiID) {
+				removed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.logger.Warn("failed to apply retention policies", "error", err)
+		return 0
+	}
+
+	p.logger.Info("applied retention policies", "removed", removed, "exempt", result.Exempt)
+	return removed
+}
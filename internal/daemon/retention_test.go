@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/config"
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func TestPruner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	persistence, err := store.NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s := store.NewStore(persistence)
+	defer s.Close()
+
+	old := time.Now().Add(-72 * time.Hour).Unix()
+	for _, n := range []model.Notification{
+		{HistuiID: "old-low", HistuiSource: "test", AppName: "a", Summary: "1", Urgency: model.UrgencyLow, Timestamp: old},
+		{HistuiID: "new-low", HistuiSource: "test", AppName: "a", Summary: "2", Urgency: model.UrgencyLow, Timestamp: time.Now().Unix()},
+		{HistuiID: "old-critical", HistuiSource: "test", AppName: "a", Summary: "3", Urgency: model.UrgencyCritical, Timestamp: old},
+	} {
+		require.NoError(t, s.Add(n))
+	}
+
+	p := NewPruner(s, nil)
+	assert.Equal(t, 0, p.Run(), "no policies removes nothing")
+
+	p.SetPolicies([]config.RetentionPolicy{
+		{Filter: "urgency=low", MaxAge: "2d"},
+	})
+	p.Start(context.Background(), time.Hour)
+	defer p.Stop()
+
+	// Start applies the policies straight away
+	require.Eventually(t, func() bool { return s.GetByID("old-low") == nil }, time.Second, 10*time.Millisecond)
+	assert.NotNil(t, s.GetByID("new-low"))
+	assert.NotNil(t, s.GetByID("old-critical"))
+
+	// Pruned notifications leave the file rather than growing it
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "old-low")
+	assert.Contains(t, string(data), "new-low")
+}
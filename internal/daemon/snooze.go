@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// SnoozeScheduler re-displays snoozed notifications when their snooze expires.
+// Snoozes are persisted in the history file, so the scheduler is rebuilt from
+// the store on startup and whenever the file changes.
+type SnoozeScheduler struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+	timers map[string]*snoozeTimer // histui_id -> pending timer
+	onDue  func(histuiID string)
+}
+
+// snoozeTimer is a scheduled re-display.
+type snoozeTimer struct {
+	until time.Time
+	timer *time.Timer
+}
+
+// NewSnoozeScheduler creates a new snooze scheduler.
+func NewSnoozeScheduler(logger *slog.Logger) *SnoozeScheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SnoozeScheduler{
+		logger: logger,
+		timers: make(map[string]*snoozeTimer),
+	}
+}
+
+// SetDueCallback sets the function called when a snooze expires.
+// It runs on a timer goroutine.
+func (s *SnoozeScheduler) SetDueCallback(cb func(histuiID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onDue = cb
+}
+
+// Schedule arranges for a notification to be due at the given time,
+// replacing any existing schedule. Times in the past are due immediately.
+func (s *SnoozeScheduler) Schedule(histuiID string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[histuiID]; ok {
+		if t.until.Equal(until) {
+			return
+		}
+		t.timer.Stop()
+	}
+
+	s.logger.Debug("scheduling snoozed notification", "histui_id", histuiID, "until", until)
+	s.timers[histuiID] = &snoozeTimer{
+		until: until,
+		timer: time.AfterFunc(time.Until(until), func() { s.fire(histuiID, until) }),
+	}
+}
+
+// Cancel removes a pending schedule.
+func (s *SnoozeScheduler) Cancel(histuiID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[histuiID]; ok {
+		t.timer.Stop()
+		delete(s.timers, histuiID)
+	}
+}
+
+// Sync schedules every notification with a pending snooze and cancels
+// schedules for notifications that no longer have one.
+func (s *SnoozeScheduler) Sync(notifications []model.Notification) {
+	pending := make(map[string]time.Time)
+	for i := range notifications {
+		n := &notifications[i]
+		if n.HasPendingSnooze() {
+			pending[n.HistuiID] = n.SnoozedUntilTime()
+		}
+	}
+
+	s.mu.Lock()
+	var stale []string
+	for id := range s.timers {
+		if _, ok := pending[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range stale {
+		s.Cancel(id)
+	}
+	for id, until := range pending {
+		s.Schedule(id, until)
+	}
+}
+
+// Pending returns the number of scheduled re-displays.
+func (s *SnoozeScheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+// Stop cancels all pending schedules.
+func (s *SnoozeScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, t := range s.timers {
+		t.timer.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// fire runs the due callback unless the schedule was replaced meanwhile.
+func (s *SnoozeScheduler) fire(histuiID string, until time.Time) {
+	s.mu.Lock()
+	t, ok := s.timers[histuiID]
+	if !ok || !t.until.Equal(until) {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.timers, histuiID)
+	cb := s.onDue
+	s.mu.Unlock()
+
+	s.logger.Debug("snooze expired", "histui_id", histuiID)
+	if cb != nil {
+		cb(histuiID)
+	}
+}
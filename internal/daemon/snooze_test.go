@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestSnoozeScheduler(t *testing.T) {
+	s := NewSnoozeScheduler(nil)
+	defer s.Stop()
+
+	var mu sync.Mutex
+	var due []string
+	s.SetDueCallback(func(histuiID string) {
+		mu.Lock()
+		defer mu.Unlock()
+		due = append(due, histuiID)
+	})
+	dueIDs := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), due...)
+	}
+
+	now := time.Now()
+	s.Sync([]model.Notification{
+		{HistuiID: "overdue", HistuiSnoozedUntil: now.Add(-time.Minute).Unix()},
+		{HistuiID: "later", HistuiSnoozedUntil: now.Add(time.Hour).Unix()},
+		{HistuiID: "cancelled", HistuiSnoozedUntil: now.Add(time.Hour).Unix()},
+		{HistuiID: "plain"},
+	})
+
+	// Snoozes that expired while the daemon was stopped fire immediately
+	require.Eventually(t, func() bool { return len(dueIDs()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"overdue"}, dueIDs())
+	assert.Equal(t, 2, s.Pending())
+
+	// Unsnoozed elsewhere: the schedule is dropped
+	s.Sync([]model.Notification{
+		{HistuiID: "later", HistuiSnoozedUntil: now.Add(time.Hour).Unix()},
+		{HistuiID: "cancelled"},
+	})
+	assert.Equal(t, 1, s.Pending())
+
+	// Rescheduling replaces the pending timer
+	s.Schedule("later", time.Now().Add(20*time.Millisecond))
+	require.Eventually(t, func() bool { return len(dueIDs()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"overdue", "later"}, dueIDs())
+	assert.Equal(t, 0, s.Pending())
+}
@@ -0,0 +1,315 @@
+package dbus
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+const (
+	// ControlInterface is the histuid control interface name.
+	ControlInterface = "io.github.jmylchreest.histuid.Control"
+	// ControlPath is the histuid control object path.
+	ControlPath = "/io/github/jmylchreest/histuid/Control"
+	// ControlBusName is the bus name claimed by histuid for control requests.
+	// It differs from the application ID, which GApplication already owns.
+	ControlBusName = "io.github.jmylchreest.histuid.Control"
+)
+
+// InvokeActionHandler is called when a client asks histuid to invoke a
+// notification action. histuiID identifies the notification in the store.
+type InvokeActionHandler func(histuiID, actionKey string) error
+
+// Status is what histuid is currently showing, as returned by the Status
+// method.
+type Status struct {
+	Active uint32 // Popups on screen
+	Queued uint32 // Notifications waiting for room on screen
+	Theme  string // Loaded theme
+	DnD    bool   // Whether Do Not Disturb is suppressing popups
+}
+
+// StatusHandler reports histuid's current status.
+type StatusHandler func() Status
+
+// SetDnDHandler turns Do Not Disturb on or off on behalf of source. A
+// positive duration turns it off again after d.
+type SetDnDHandler func(enabled bool, d time.Duration, source string) error
+
+// CloseAllHandler closes every popup and clears the queue.
+type CloseAllHandler func()
+
+// ShowNotificationHandler shows a stored notification as a popup again.
+type ShowNotificationHandler func(histuiID string) error
+
+// ControlServer exposes histuid-specific methods used by the histui CLI.
+// It shares the session bus connection with the NotificationServer.
+type ControlServer struct {
+	conn   *dbus.Conn
+	logger *slog.Logger
+
+	invokeActionHandler     InvokeActionHandler
+	statusHandler           StatusHandler
+	setDnDHandler           SetDnDHandler
+	closeAllHandler         CloseAllHandler
+	showNotificationHandler ShowNotificationHandler
+}
+
+// controlObject is the exported D-Bus object. It is kept separate from
+// ControlServer so only the D-Bus methods are visible on the bus.
+type controlObject struct {
+	server *ControlServer
+}
+
+// NewControlServer creates a new ControlServer.
+func NewControlServer(logger *slog.Logger) *ControlServer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ControlServer{logger: logger}
+}
+
+// SetInvokeActionHandler sets the handler for InvokeAction requests.
+func (c *ControlServer) SetInvokeActionHandler(handler InvokeActionHandler) {
+	c.invokeActionHandler = handler
+}
+
+// SetStatusHandler sets the handler for Status requests.
+func (c *ControlServer) SetStatusHandler(handler StatusHandler) {
+	c.statusHandler = handler
+}
+
+// SetDnDHandler sets the handler for SetDnD requests.
+func (c *ControlServer) SetDnDHandler(handler SetDnDHandler) {
+	c.setDnDHandler = handler
+}
+
+// SetCloseAllHandler sets the handler for CloseAll requests.
+func (c *ControlServer) SetCloseAllHandler(handler CloseAllHandler) {
+	c.closeAllHandler = handler
+}
+
+// SetShowNotificationHandler sets the handler for ShowNotification requests.
+func (c *ControlServer) SetShowNotificationHandler(handler ShowNotificationHandler) {
+	c.showNotificationHandler = handler
+}
+
+// Start exports the control object on conn and claims ControlBusName.
+func (c *ControlServer) Start(conn *dbus.Conn) error {
+	if conn == nil {
+		return fmt.Errorf("not connected to D-Bus")
+	}
+	c.conn = conn
+
+	if err := conn.Export(&controlObject{server: c}, ControlPath, ControlInterface); err != nil {
+		return fmt.Errorf("failed to export control object: %w", err)
+	}
+
+	node := &introspect.Node{
+		Name: ControlPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    ControlInterface,
+				Methods: controlMethods(),
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), ControlPath,
+		"org.freedesktop.DBus.Introspectable"); err != nil {
+		return fmt.Errorf("failed to export introspectable: %w", err)
+	}
+
+	reply, err := conn.RequestName(ControlBusName, dbus.NameFlagDoNotQueue|dbus.NameFlagReplaceExisting)
+	if err != nil {
+		return fmt.Errorf("failed to request bus name: %w", err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("bus name %s already taken", ControlBusName)
+	}
+
+	c.logger.Info("D-Bus control server started", "interface", ControlInterface, "path", ControlPath)
+	return nil
+}
+
+// Stop releases the control bus name.
+func (c *ControlServer) Stop() error {
+	if c.conn == nil {
+		return nil
+	}
+	if _, err := c.conn.ReleaseName(ControlBusName); err != nil {
+		c.logger.Warn("failed to release bus name", "name", ControlBusName, "error", err)
+	}
+	c.conn = nil
+	return nil
+}
+
+// InvokeAction invokes an action on a stored notification.
+// D-Bus method: InvokeAction(s histui_id, s action_key)
+func (o *controlObject) InvokeAction(histuiID, actionKey string) *dbus.Error {
+	o.server.logger.Debug("InvokeAction called", "histui_id", histuiID, "action_key", actionKey)
+
+	if o.server.invokeActionHandler == nil {
+		return dbus.MakeFailedError(fmt.Errorf("action invocation not supported"))
+	}
+	if err := o.server.invokeActionHandler(histuiID, actionKey); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// Status reports the popups on screen and queued, the theme and DnD.
+// D-Bus method: Status() -> (u active, u queued, s theme, b dnd)
+func (o *controlObject) Status() (Status, *dbus.Error) {
+	if o.server.statusHandler == nil {
+		return Status{}, dbus.MakeFailedError(fmt.Errorf("status not supported"))
+	}
+	return o.server.statusHandler(), nil
+}
+
+// SetDnD turns Do Not Disturb on or off. A non-zero seconds turns it off
+// again after that long.
+// D-Bus method: SetDnD(b enabled, u seconds, s source)
+func (o *controlObject) SetDnD(enabled bool, seconds uint32, source string) *dbus.Error {
+	o.server.logger.Debug("SetDnD called", "enabled", enabled, "seconds", seconds, "source", source)
+
+	if o.server.setDnDHandler == nil {
+		return dbus.MakeFailedError(fmt.Errorf("setting DnD not supported"))
+	}
+	if err := o.server.setDnDHandler(enabled, time.Duration(seconds)*time.Second, source); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// CloseAll closes every popup and clears the queue.
+// D-Bus method: CloseAll()
+func (o *controlObject) CloseAll() *dbus.Error {
+	o.server.logger.Debug("CloseAll called")
+
+	if o.server.closeAllHandler == nil {
+		return dbus.MakeFailedError(fmt.Errorf("closing popups not supported"))
+	}
+	o.server.closeAllHandler()
+	return nil
+}
+
+// ShowNotification shows a stored notification as a popup again.
+// D-Bus method: ShowNotification(s histui_id)
+func (o *controlObject) ShowNotification(histuiID string) *dbus.Error {
+	o.server.logger.Debug("ShowNotification called", "histui_id", histuiID)
+
+	if o.server.showNotificationHandler == nil {
+		return dbus.MakeFailedError(fmt.Errorf("showing notifications not supported"))
+	}
+	if err := o.server.showNotificationHandler(histuiID); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// controlMethods returns introspection data for the control interface.
+func controlMethods() []introspect.Method {
+	return []introspect.Method{
+		{
+			Name: "InvokeAction",
+			Args: []introspect.Arg{
+				{Name: "histui_id", Type: "s", Direction: "in"},
+				{Name: "action_key", Type: "s", Direction: "in"},
+			},
+		},
+		{
+			Name: "Status",
+			Args: []introspect.Arg{
+				{Name: "status", Type: "(uusb)", Direction: "out"},
+			},
+		},
+		{
+			Name: "SetDnD",
+			Args: []introspect.Arg{
+				{Name: "enabled", Type: "b", Direction: "in"},
+				{Name: "seconds", Type: "u", Direction: "in"},
+				{Name: "source", Type: "s", Direction: "in"},
+			},
+		},
+		{
+			Name: "CloseAll",
+		},
+		{
+			Name: "ShowNotification",
+			Args: []introspect.Arg{
+				{Name: "histui_id", Type: "s", Direction: "in"},
+			},
+		},
+	}
+}
+
+// ControlClient calls methods on a running histuid.
+type ControlClient struct {
+	obj dbus.BusObject
+}
+
+// NewControlClient connects to histuid's control interface on the session bus.
+// Returns an error if histuid is not running.
+func NewControlClient() (*ControlClient, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	var hasOwner bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, ControlBusName).Store(&hasOwner); err != nil {
+		return nil, fmt.Errorf("failed to query bus name: %w", err)
+	}
+	if !hasOwner {
+		return nil, fmt.Errorf("histuid is not running")
+	}
+
+	return &ControlClient{obj: conn.Object(ControlBusName, ControlPath)}, nil
+}
+
+// InvokeAction asks histuid to invoke actionKey on the notification with histuiID.
+func (c *ControlClient) InvokeAction(histuiID, actionKey string) error {
+	if err := c.obj.Call(ControlInterface+".InvokeAction", 0, histuiID, actionKey).Err; err != nil {
+		return fmt.Errorf("failed to invoke action: %w", err)
+	}
+	return nil
+}
+
+// Status returns what histuid is currently showing.
+func (c *ControlClient) Status() (Status, error) {
+	var status Status
+	if err := c.obj.Call(ControlInterface+".Status", 0).Store(&status); err != nil {
+		return Status{}, fmt.Errorf("failed to get status: %w", err)
+	}
+	return status, nil
+}
+
+// SetDnD asks histuid to turn Do Not Disturb on or off on behalf of source.
+// A positive duration turns it off again after d, to the second.
+func (c *ControlClient) SetDnD(enabled bool, d time.Duration, source string) error {
+	seconds := uint32(max(d, 0) / time.Second)
+	if err := c.obj.Call(ControlInterface+".SetDnD", 0, enabled, seconds, source).Err; err != nil {
+		return fmt.Errorf("failed to set Do Not Disturb: %w", err)
+	}
+	return nil
+}
+
+// CloseAll asks histuid to close every popup.
+func (c *ControlClient) CloseAll() error {
+	if err := c.obj.Call(ControlInterface+".CloseAll", 0).Err; err != nil {
+		return fmt.Errorf("failed to close popups: %w", err)
+	}
+	return nil
+}
+
+// ShowNotification asks histuid to show a stored notification again.
+func (c *ControlClient) ShowNotification(histuiID string) error {
+	if err := c.obj.Call(ControlInterface+".ShowNotification", 0, histuiID).Err; err != nil {
+		return fmt.Errorf("failed to show notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dbus
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// NameOwner describes the process owning a well-known bus name.
+type NameOwner struct {
+	Name        string // Well-known name, e.g. org.freedesktop.Notifications
+	UniqueName  string // Unique connection name, e.g. :1.42 (empty if unowned)
+	PID         uint32 // Owning process (0 if unknown)
+	ProcessName string // From /proc/<pid>/comm (empty if unknown)
+}
+
+// Owned reports whether the name has an owner.
+func (o *NameOwner) Owned() bool {
+	return o.UniqueName != ""
+}
+
+// LookupNameOwner returns the current owner of a well-known name on conn.
+// An unowned name is not an error; the returned owner has no UniqueName.
+func LookupNameOwner(conn *dbus.Conn, name string) (*NameOwner, error) {
+	owner := &NameOwner{Name: name}
+	bus := conn.BusObject()
+
+	var hasOwner bool
+	if err := bus.Call("org.freedesktop.DBus.NameHasOwner", 0, name).Store(&hasOwner); err != nil {
+		return nil, fmt.Errorf("failed to query bus name %s: %w", name, err)
+	}
+	if !hasOwner {
+		return owner, nil
+	}
+
+	if err := bus.Call("org.freedesktop.DBus.GetNameOwner", 0, name).Store(&owner.UniqueName); err != nil {
+		return nil, fmt.Errorf("failed to get owner of %s: %w", name, err)
+	}
+
+	// PID and process name are best effort (may be unavailable in sandboxes)
+	if err := bus.Call("org.freedesktop.DBus.GetConnectionUnixProcessID", 0, name).Store(&owner.PID); err == nil && owner.PID > 0 {
+		if comm, err := os.ReadFile("/proc/" + strconv.FormatUint(uint64(owner.PID), 10) + "/comm"); err == nil {
+			owner.ProcessName = strings.TrimSpace(string(comm))
+		}
+	}
+
+	return owner, nil
+}
+
+// QueryServerInformation calls GetServerInformation on the notification
+// server currently owning DBusBusName.
+func QueryServerInformation(conn *dbus.Conn) (ServerInfo, error) {
+	var info ServerInfo
+	call := conn.Object(DBusBusName, DBusPath).Call(DBusInterface+".GetServerInformation", 0)
+	if err := call.Store(&info.Name, &info.Vendor, &info.Version, &info.SpecVersion); err != nil {
+		return info, fmt.Errorf("failed to get server information: %w", err)
+	}
+	return info, nil
+}
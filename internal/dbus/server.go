@@ -281,6 +281,19 @@ func (s *NotificationServer) NotifyInternal(notification *DBusNotification) uint
 	return id
 }
 
+// ReserveID allocates a fresh notification ID and tracks it as active
+// without invoking the notify handler. It is used when histuid displays
+// a stored notification again, such as a snoozed one.
+func (s *NotificationServer) ReserveID() uint32 {
+	id := s.nextID.Add(1)
+
+	s.mu.Lock()
+	s.activeIDs[id] = true
+	s.mu.Unlock()
+
+	return id
+}
+
 // IsActive returns true if the notification ID is currently active.
 func (s *NotificationServer) IsActive(id uint32) bool {
 	s.mu.RLock()
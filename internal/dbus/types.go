@@ -255,6 +255,44 @@ func (n *DBusNotification) FrameColor() string {
 	return ""
 }
 
+// NotificationFromModel rebuilds a D-Bus notification from a stored one,
+// so histuid can display it again (e.g. when a snooze expires).
+func NotificationFromModel(n *model.Notification) *DBusNotification {
+	notification := &DBusNotification{
+		AppName:       n.AppName,
+		AppIcon:       n.IconPath,
+		Summary:       n.Summary,
+		Body:          n.Body,
+		ExpireTimeout: -1,
+		Hints: map[string]dbus.Variant{
+			"urgency": dbus.MakeVariant(byte(n.Urgency)),
+		},
+	}
+	if n.Category != "" {
+		notification.Hints["category"] = dbus.MakeVariant(n.Category)
+	}
+
+	if ext := n.Extensions; ext != nil {
+		for _, a := range ext.Actions {
+			notification.Actions = append(notification.Actions, a.Key, a.Label)
+		}
+		if ext.DesktopEntry != "" {
+			notification.Hints["desktop-entry"] = dbus.MakeVariant(ext.DesktopEntry)
+		}
+		if ext.SoundFile != "" {
+			notification.Hints["sound-file"] = dbus.MakeVariant(ext.SoundFile)
+		}
+		if ext.SoundName != "" {
+			notification.Hints["sound-name"] = dbus.MakeVariant(ext.SoundName)
+		}
+		if ext.Resident {
+			notification.Hints["resident"] = dbus.MakeVariant(true)
+		}
+	}
+
+	return notification
+}
+
 // ServerCapabilities lists the capabilities advertised by histuid.
 var ServerCapabilities = []string{
 	"actions",         // Support notification actions
@@ -374,6 +374,38 @@ func TestColorHints(t *testing.T) {
 	assert.Equal(t, "", n.FrameColor())
 }
 
+func TestNotificationFromModel(t *testing.T) {
+	n := &model.Notification{
+		AppName:  "mail",
+		Summary:  "Inbox",
+		Body:     "3 new messages",
+		IconPath: "mail-unread",
+		Urgency:  model.UrgencyCritical,
+		Category: "email.arrived",
+		Extensions: &model.Extensions{
+			Actions:      []model.Action{{Key: "default", Label: "Open"}},
+			DesktopEntry: "org.gnome.Evolution",
+			Resident:     true,
+		},
+	}
+
+	got := NotificationFromModel(n)
+	assert.Equal(t, "mail", got.AppName)
+	assert.Equal(t, "mail-unread", got.AppIcon)
+	assert.Equal(t, "Inbox", got.Summary)
+	assert.Equal(t, int32(-1), got.ExpireTimeout)
+	assert.Equal(t, model.UrgencyCritical, got.Urgency())
+	assert.Equal(t, "email.arrived", got.Category())
+	assert.Equal(t, "org.gnome.Evolution", got.DesktopEntry())
+	assert.True(t, got.Resident())
+	assert.Equal(t, []Action{{Key: "default", Label: "Open"}}, got.ParsedActions())
+
+	// Notifications without extensions still carry their urgency
+	plain := NotificationFromModel(&model.Notification{Summary: "plain", Urgency: model.UrgencyLow})
+	assert.Equal(t, model.UrgencyLow, plain.Urgency())
+	assert.Empty(t, plain.Actions)
+}
+
 func TestDefaultServerInfo(t *testing.T) {
 	info := DefaultServerInfo()
 	assert.Equal(t, "histuid", info.Name)
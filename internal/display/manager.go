@@ -12,6 +12,7 @@ import (
 
 	"github.com/jmylchreest/histui/internal/config"
 	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/layout"
 )
 
 // QueuedNotification represents a notification waiting to be displayed.
@@ -43,6 +44,10 @@ type CloseCallback func(dbusID uint32, reason dbus.CloseReason)
 // ActionCallback is called when an action is invoked.
 type ActionCallback func(dbusID uint32, actionKey string)
 
+// SnoozeCallback is called when the user snoozes a popup.
+// The popup is closed as dismissed afterwards.
+type SnoozeCallback func(dbusID uint32, d time.Duration)
+
 // Manager manages notification popup windows with memory-efficient queuing.
 // Only MaxVisible popups exist as GTK objects at any time.
 // Additional notifications are queued and displayed when space becomes available.
@@ -51,6 +56,7 @@ type Manager struct {
 	config  *config.DaemonConfig
 	logger  *slog.Logger
 	display *gdk.Display
+	layouts *layout.Loader
 
 	// Active popups - only MaxVisible at a time
 	mu     sync.RWMutex
@@ -64,6 +70,7 @@ type Manager struct {
 	// Callbacks
 	onClose  CloseCallback
 	onAction ActionCallback
+	onSnooze SnoozeCallback
 
 	// Timeout management
 	timeoutCh chan uint32
@@ -79,10 +86,17 @@ func NewManager(app *gtk.Application, cfg *config.DaemonConfig, logger *slog.Log
 		cfg = config.DefaultDaemonConfig()
 	}
 
+	layoutsDir, err := layout.LayoutsDir()
+	if err != nil {
+		logger.Warn("failed to get layouts directory", "error", err)
+		layoutsDir = ""
+	}
+
 	return &Manager{
 		app:        app,
 		config:     cfg,
 		logger:     logger,
+		layouts:    layout.NewLoader(layoutsDir),
 		popups:     make(map[uint32]*PopupState),
 		queue:      list.New(),
 		queueIndex: make(map[uint32]*list.Element),
@@ -129,6 +143,11 @@ func (m *Manager) SetActionCallback(cb ActionCallback) {
 	m.onAction = cb
 }
 
+// SetSnoozeCallback sets the callback for popup snooze events.
+func (m *Manager) SetSnoozeCallback(cb SnoozeCallback) {
+	m.onSnooze = cb
+}
+
 // isDuplicate checks if two notifications are considered duplicates for stacking.
 func isDuplicate(a, b *dbus.DBusNotification) bool {
 	return a.AppName == b.AppName &&
@@ -246,7 +265,7 @@ func (m *Manager) showPopupLocked(notification *dbus.DBusNotification, dbusID ui
 	position := len(m.popups)
 
 	// Create the popup (this is where GTK objects are allocated)
-	popup, err := NewPopup(m.app, notification, m.config, m.logger)
+	popup, err := NewPopup(m.app, notification, m.config, m.layouts, m.logger)
 	if err != nil {
 		return err
 	}
@@ -271,6 +290,12 @@ func (m *Manager) showPopupLocked(notification *dbus.DBusNotification, dbusID ui
 		go m.CloseAll()
 	})
 
+	if m.onSnooze != nil {
+		popup.OnSnooze(func(d time.Duration) {
+			m.onSnooze(dbusID, d)
+		})
+	}
+
 	// Calculate expiration time
 	timeout := m.config.GetTimeoutForUrgency(notification.Urgency())
 	var expiresAt time.Time
@@ -476,6 +501,13 @@ func (m *Manager) GetActiveHistuiIDs() []string {
 	return ids
 }
 
+// Counts returns the number of popups on screen and queued.
+func (m *Manager) Counts() (active, queued int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.popups), m.queue.Len()
+}
+
 // Close closes a popup by D-Bus ID.
 func (m *Manager) Close(dbusID uint32, reason dbus.CloseReason) {
 	m.mu.Lock()
@@ -673,6 +705,12 @@ func (m *Manager) TotalCount() int {
 	return len(m.popups) + m.queue.Len()
 }
 
+// Layouts returns the layout template loader used for new popups.
+// Invalidating its cache makes future popups pick up edited templates.
+func (m *Manager) Layouts() *layout.Loader {
+	return m.layouts
+}
+
 // UpdateConfig updates the configuration and adjusts displayed popups if necessary.
 // This is called when the config file is hot-reloaded.
 func (m *Manager) UpdateConfig(cfg *config.DaemonConfig) {
@@ -7,11 +7,14 @@ import (
 
 	"github.com/diamondburned/gotk4-adwaita/pkg/adw"
 	layershell "github.com/diamondburned/gotk4-layer-shell/pkg/gtk4layershell"
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
 
 	"github.com/jmylchreest/histui/internal/config"
 	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/icons"
 	"github.com/jmylchreest/histui/internal/layout"
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 )
 
@@ -21,6 +24,7 @@ type Popup struct {
 	notification *dbus.DBusNotification
 	config       *config.DaemonConfig
 	layout       *layout.LayoutConfig
+	model        *model.Notification // For layout conditions and <text> templates
 	logger       *slog.Logger
 
 	// Widgets
@@ -35,12 +39,14 @@ type Popup struct {
 	closeBtn      *gtk.Button
 	stackCountLbl *gtk.Label
 	imageWidget   *gtk.Image
+	contextMenu   *gtk.Popover
 
 	// Callbacks
 	onClose    func(reason dbus.CloseReason)
 	onAction   func(actionKey string)
 	onHover    func(hovering bool)
 	onCloseAll func()
+	onSnooze   func(d time.Duration)
 
 	// State
 	position   int
@@ -54,24 +60,22 @@ type Popup struct {
 }
 
 // NewPopup creates a new notification popup.
-func NewPopup(app *gtk.Application, notification *dbus.DBusNotification, cfg *config.DaemonConfig, logger *slog.Logger) (*Popup, error) {
+func NewPopup(app *gtk.Application, notification *dbus.DBusNotification, cfg *config.DaemonConfig, layouts *layout.Loader, logger *slog.Logger) (*Popup, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
-	// Load layout template
-	var layoutConfig *layout.LayoutConfig
-	templateName := cfg.Layout.Template
-	if templateName == "" {
-		templateName = "default"
+	if layouts == nil {
+		layouts = layout.NewLoader("")
 	}
 
-	if tmpl, found := layout.GetEmbeddedTemplate(templateName); found {
-		layoutConfig = tmpl
-	} else {
+	// Select the layout template for this notification
+	templateName := cfg.Layout.TemplateFor(notification.AppName, notification.Urgency(), notification.Category())
+	layoutConfig, err := layouts.Load(templateName)
+	if err != nil {
 		// Fall back to default layout
 		layoutConfig = layout.DefaultLayout()
-		logger.Warn("layout template not found, using default", "template", templateName)
+		logger.Warn("layout template not found, using default", "template", templateName, "error", err)
 	}
 
 	p := &Popup{
@@ -81,6 +85,7 @@ func NewPopup(app *gtk.Application, notification *dbus.DBusNotification, cfg *co
 		logger:       logger,
 		timestamp:    time.Now(),
 	}
+	p.model = notificationModel(notification, p.timestamp)
 
 	// Create the window
 	p.window = gtk.NewWindow()
@@ -159,9 +164,12 @@ func (p *Popup) applyThemeClasses() {
 	if p.notification.AppIcon != "" {
 		p.box.AddCSSClass("has-icon")
 	}
-	if len(p.notification.ParsedActions()) > 0 {
+	if len(p.notification.ParsedActions()) > 0 || len(p.model.Codes()) > 0 {
 		p.box.AddCSSClass("has-actions")
 	}
+	if len(p.model.Codes()) > 0 {
+		p.box.AddCSSClass("has-code")
+	}
 	if p.notification.Resident() {
 		p.box.AddCSSClass("is-resident")
 	}
@@ -233,8 +241,8 @@ func (p *Popup) buildUI() {
 	p.box.SetMarginStart(12)
 	p.box.SetMarginEnd(12)
 
-	// Build from layout template
-	for _, elem := range p.layout.Elements {
+	// Build from layout template, skipping elements whose conditions fail
+	for _, elem := range p.layout.Evaluate(p.model) {
 		if widget := p.buildElement(elem); widget != nil {
 			p.box.Append(widget)
 		}
@@ -270,6 +278,8 @@ func (p *Popup) buildElement(elem layout.LayoutElement) gtk.Widgetter {
 		return p.buildImage()
 	case layout.ElementTypeBox:
 		return p.buildBox(elem)
+	case layout.ElementTypeText:
+		return p.buildText(elem)
 	default:
 		return nil
 	}
@@ -310,14 +320,42 @@ func (p *Popup) buildBox(elem layout.LayoutElement) gtk.Widgetter {
 	return box
 }
 
+// buildText creates a label rendered from a <text format="..."> template.
+func (p *Popup) buildText(elem layout.LayoutElement) gtk.Widgetter {
+	text, err := elem.RenderText(p.model)
+	if err != nil {
+		p.logger.Warn("failed to render text element", "format", elem.Attributes[layout.AttrFormat], "error", err)
+		return nil
+	}
+	if text == "" {
+		return nil
+	}
+
+	lbl := gtk.NewLabel(text)
+	lbl.AddCSSClass("notification-text")
+	if class := elem.Attributes["class"]; class != "" {
+		lbl.AddCSSClass(sanitizeClassName(class))
+	}
+	lbl.SetXAlign(0)
+	lbl.SetWrap(true)
+	return lbl
+}
+
 // buildIcon creates the notification icon.
 func (p *Popup) buildIcon() gtk.Widgetter {
 	p.iconImage = gtk.NewImage()
 	p.iconImage.AddCSSClass("notification-icon")
 	p.iconImage.SetPixelSize(48)
-	if p.notification.AppIcon != "" {
+
+	// Resolve themed names, file paths and desktop entries to a file so
+	// icons from other themes and .desktop-only apps still render.
+	path := icons.Default().Resolve(p.notification.AppIcon, p.notification.DesktopEntry(), p.notification.AppName, 48)
+	switch {
+	case path != "":
+		p.iconImage.SetFromFile(path)
+	case p.notification.AppIcon != "":
 		p.iconImage.SetFromIconName(p.notification.AppIcon)
-	} else {
+	default:
 		p.iconImage.SetFromIconName("dialog-information")
 	}
 	return p.iconImage
@@ -379,9 +417,9 @@ func (p *Popup) buildBody() gtk.Widgetter {
 	p.bodyLbl.SetWrapMode(2) // PANGO_WRAP_WORD_CHAR
 	p.bodyLbl.SetMaxWidthChars(50)
 
-	// Apply markup if body contains markup tags
-	if strings.Contains(p.notification.Body, "<") {
-		p.bodyLbl.SetMarkup(sanitizeMarkup(p.notification.Body))
+	// Apply markup if body contains markup tags or entities
+	if markup.HasMarkup(p.notification.Body) {
+		p.bodyLbl.SetMarkup(markup.Sanitize(p.notification.Body))
 	} else {
 		p.bodyLbl.SetText(p.notification.Body)
 	}
@@ -389,10 +427,12 @@ func (p *Popup) buildBody() gtk.Widgetter {
 	return p.bodyLbl
 }
 
-// buildActions creates the action buttons container.
+// buildActions creates the action buttons container, with a copy button
+// for each one-time code found in the text.
 func (p *Popup) buildActions() gtk.Widgetter {
 	actions := p.notification.ParsedActions()
-	if len(actions) == 0 {
+	codes := p.model.Codes()
+	if len(actions) == 0 && len(codes) == 0 {
 		return nil
 	}
 
@@ -419,9 +459,31 @@ func (p *Popup) buildActions() gtk.Widgetter {
 		p.actionBox.Append(btn)
 	}
 
+	for _, code := range codes {
+		code := code // Capture for closure
+		btn := gtk.NewButtonWithLabel("Copy " + code)
+		btn.AddCSSClass("notification-action")
+		btn.AddCSSClass("notification-copy-code")
+		btn.ConnectClicked(func() {
+			p.copyCode(code)
+		})
+		p.actionBox.Append(btn)
+	}
+
 	return p.actionBox
 }
 
+// copyCode copies a one-time code to the clipboard and dismisses the popup.
+// The clipboard belongs to the display, so the code stays available after
+// the window closes.
+func (p *Popup) copyCode(code string) {
+	p.window.Clipboard().SetText(code)
+	p.Close()
+	if p.onClose != nil {
+		p.onClose(dbus.CloseReasonDismissed)
+	}
+}
+
 // buildProgress creates the progress bar.
 func (p *Popup) buildProgress() gtk.Widgetter {
 	progress := p.notification.Progress()
@@ -506,13 +568,13 @@ func (p *Popup) connectSignals() {
 	clickCtrl.SetButton(0) // All buttons
 	clickCtrl.ConnectReleased(func(nPress int, x, y float64) {
 		button := clickCtrl.CurrentButton()
-		p.handleClick(button)
+		p.handleClick(button, x, y)
 	})
 	p.window.AddController(clickCtrl)
 }
 
-// handleClick processes mouse button clicks.
-func (p *Popup) handleClick(button uint) {
+// handleClick processes mouse button clicks at the given position.
+func (p *Popup) handleClick(button uint, x, y float64) {
 	var action string
 	switch button {
 	case 1: // Left
@@ -564,11 +626,97 @@ func (p *Popup) handleClick(button uint) {
 				p.onClose(dbus.CloseReasonDismissed)
 			}
 		}
+	case config.MouseActionContextMenu:
+		p.showContextMenu(x, y)
 	case config.MouseActionNone:
 		// Do nothing
 	}
 }
 
+// snoozeChoices are the snooze durations offered by the context menu.
+var snoozeChoices = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"Snooze 15 minutes", 15 * time.Minute},
+	{"Snooze 1 hour", time.Hour},
+	{"Snooze 4 hours", 4 * time.Hour},
+}
+
+// showContextMenu opens a menu at the click position listing the
+// notification's actions, one-time codes to copy, snooze choices and dismiss.
+func (p *Popup) showContextMenu(x, y float64) {
+	if p.contextMenu != nil {
+		p.contextMenu.Unparent()
+	}
+
+	menu := gtk.NewBox(gtk.OrientationVertical, 2)
+
+	popover := gtk.NewPopover()
+	popover.AddCSSClass("notification-menu")
+	popover.SetHasArrow(false)
+	popover.SetChild(menu)
+	popover.SetParent(p.box)
+	rect := gdk.NewRectangle(int(x), int(y), 1, 1)
+	popover.SetPointingTo(&rect)
+	p.contextMenu = popover
+
+	addItem := func(label string, activate func()) {
+		btn := gtk.NewButtonWithLabel(label)
+		btn.AddCSSClass("flat")
+		btn.AddCSSClass("notification-menu-item")
+		btn.ConnectClicked(func() {
+			popover.Popdown()
+			activate()
+		})
+		menu.Append(btn)
+	}
+
+	for _, action := range p.notification.ParsedActions() {
+		actionKey := action.Key // Capture for closure
+		addItem(action.Label, func() {
+			if p.onAction != nil {
+				p.onAction(actionKey)
+			}
+			if !p.notification.Resident() {
+				p.Close()
+				if p.onClose != nil {
+					p.onClose(dbus.CloseReasonDismissed)
+				}
+			}
+		})
+	}
+
+	for _, code := range p.model.Codes() {
+		code := code // Capture for closure
+		addItem("Copy "+code, func() {
+			p.copyCode(code)
+		})
+	}
+
+	if p.onSnooze != nil {
+		for _, choice := range snoozeChoices {
+			d := choice.duration // Capture for closure
+			addItem(choice.label, func() {
+				p.onSnooze(d)
+				p.Close()
+				if p.onClose != nil {
+					p.onClose(dbus.CloseReasonDismissed)
+				}
+			})
+		}
+	}
+
+	addItem("Dismiss", func() {
+		p.Close()
+		if p.onClose != nil {
+			p.onClose(dbus.CloseReasonDismissed)
+		}
+	})
+
+	popover.Popup()
+}
+
 // Show displays the popup at the given stack position.
 func (p *Popup) Show(position int) {
 	p.position = position
@@ -582,6 +730,10 @@ func (p *Popup) Close() {
 		return
 	}
 	p.closed = true
+	if p.contextMenu != nil {
+		p.contextMenu.Unparent()
+		p.contextMenu = nil
+	}
 	p.window.Close()
 }
 
@@ -661,6 +813,12 @@ func (p *Popup) OnCloseAll(cb func()) {
 	p.onCloseAll = cb
 }
 
+// OnSnooze sets the callback for snoozing from the context menu.
+// Snooze choices are only offered once a callback is set.
+func (p *Popup) OnSnooze(cb func(d time.Duration)) {
+	p.onSnooze = cb
+}
+
 // SetStackCount updates the stack count badge.
 // A count of 1 or less hides the badge.
 func (p *Popup) SetStackCount(count int) {
@@ -738,13 +896,31 @@ func detectSystemColorScheme() string {
 	return "light"
 }
 
-// sanitizeMarkup removes unsupported Pango markup tags.
-// GTK4 labels support a subset of Pango markup.
-func sanitizeMarkup(markup string) string {
-	// For now, pass through as-is
-	// TODO: Add proper sanitization if needed
-	return markup
-}
-
 // Ensure adw is used (for libadwaita initialization)
 var _ = adw.MAJOR_VERSION
+
+// notificationModel converts a D-Bus notification into the model used by
+// layout conditions and <text> templates.
+func notificationModel(n *dbus.DBusNotification, received time.Time) *model.Notification {
+	m := &model.Notification{
+		AppName:       n.AppName,
+		Summary:       n.Summary,
+		Body:          n.Body,
+		Timestamp:     received.Unix(),
+		ExpireTimeout: int(n.ExpireTimeout),
+		Category:      n.Category(),
+		IconPath:      n.AppIcon,
+		Extensions: &model.Extensions{
+			StackTag:     n.StackTag(),
+			Progress:     n.Progress(),
+			DesktopEntry: n.DesktopEntry(),
+			Resident:     n.Resident(),
+			Transient:    n.Transient(),
+		},
+	}
+	m.SetUrgency(n.Urgency())
+	for _, a := range n.ParsedActions() {
+		m.Extensions.Actions = append(m.Extensions.Actions, model.Action{Key: a.Key, Label: a.Label})
+	}
+	return m
+}
@@ -0,0 +1,60 @@
+package icons
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DesktopEntry holds the fields histui uses from a .desktop file.
+type DesktopEntry struct {
+	ID          string // Desktop file ID, e.g. "org.mozilla.firefox"
+	Path        string // Path to the .desktop file
+	Name        string // Human-friendly application name
+	GenericName string // Generic name, e.g. "Web Browser"
+	Icon        string // Icon name or absolute path
+	Exec        string // Command line with field codes removed
+	NoDisplay   bool   // Hidden from menus
+}
+
+// ParseDesktopEntry parses the [Desktop Entry] group of a .desktop file.
+func ParseDesktopEntry(r io.Reader) (*DesktopEntry, error) {
+	file, err := parseINI(r)
+	if err != nil {
+		return nil, err
+	}
+
+	group, ok := file["Desktop Entry"]
+	if !ok {
+		return nil, fmt.Errorf("missing [Desktop Entry] group")
+	}
+
+	return &DesktopEntry{
+		Name:        group["Name"],
+		GenericName: group["GenericName"],
+		Icon:        group["Icon"],
+		Exec:        stripFieldCodes(group["Exec"]),
+		NoDisplay:   group["NoDisplay"] == "true",
+	}, nil
+}
+
+// stripFieldCodes removes Exec field codes such as %u and %F.
+// "%%" is kept as a literal percent sign.
+func stripFieldCodes(exec string) string {
+	if !strings.Contains(exec, "%") {
+		return exec
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(exec); i++ {
+		if exec[i] != '%' || i+1 >= len(exec) {
+			sb.WriteByte(exec[i])
+			continue
+		}
+		i++
+		if exec[i] == '%' {
+			sb.WriteByte('%')
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
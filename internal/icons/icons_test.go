@@ -0,0 +1,136 @@
+package icons
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func newTestResolver(themeName string) *Resolver {
+	return NewResolver(Options{
+		Theme:      themeName,
+		IconDirs:   []string{filepath.Join("testdata", "icons")},
+		PixmapDirs: []string{filepath.Join("testdata", "pixmaps")},
+		AppDirs:    []string{filepath.Join("testdata", "applications")},
+	})
+}
+
+func iconPath(parts ...string) string {
+	return filepath.Join(append([]string{"testdata", "icons"}, parts...)...)
+}
+
+func TestLookupIcon(t *testing.T) {
+	r := newTestResolver("hicolor")
+
+	tests := []struct {
+		name string
+		icon string
+		size int
+		want string
+	}{
+		{"fixed exact size", "firefox", 48, iconPath("hicolor", "48x48", "apps", "firefox.png")},
+		{"threshold match", "firefox", 17, iconPath("hicolor", "16x16", "apps", "firefox.png")},
+		{"closest size", "firefox", 64, iconPath("hicolor", "48x48", "apps", "firefox.png")},
+		{"scalable", "slack", 64, iconPath("hicolor", "scalable", "apps", "slack.svg")},
+		{"extension in name", "slack.svg", 64, iconPath("hicolor", "scalable", "apps", "slack.svg")},
+		{"pixmaps fallback", "legacy", 48, filepath.Join("testdata", "pixmaps", "legacy.xpm")},
+		{"missing", "nope", 48, ""},
+		{"empty", "", 48, ""},
+		{"relative path", "some/icon.png", 48, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.LookupIcon(tt.icon, tt.size))
+		})
+	}
+}
+
+func TestLookupIcon_Inheritance(t *testing.T) {
+	r := newTestResolver("Custom")
+
+	// Custom overrides hicolor
+	assert.Equal(t, iconPath("Custom", "32x32", "apps", "firefox.png"), r.LookupIcon("firefox", 32))
+	// Found in Custom at the closest size before falling back to hicolor
+	assert.Equal(t, iconPath("Custom", "32x32", "apps", "firefox.png"), r.LookupIcon("firefox", 48))
+	// Inherited from Base
+	assert.Equal(t, iconPath("Base", "24x24", "apps", "mail.png"), r.LookupIcon("mail", 24))
+	// Inherited from hicolor via Base
+	assert.Equal(t, iconPath("hicolor", "scalable", "apps", "slack.svg"), r.LookupIcon("slack", 24))
+}
+
+func TestLookupIcon_Paths(t *testing.T) {
+	r := newTestResolver("hicolor")
+
+	abs, err := filepath.Abs(iconPath("hicolor", "48x48", "apps", "firefox.png"))
+	require.NoError(t, err)
+
+	assert.Equal(t, abs, r.LookupIcon(abs, 48))
+	assert.Equal(t, abs, r.LookupIcon("file://"+abs, 48))
+	assert.Equal(t, "", r.LookupIcon("/does/not/exist.png", 48))
+}
+
+func TestLookupDesktopEntry(t *testing.T) {
+	r := newTestResolver("hicolor")
+
+	entry := r.LookupDesktopEntry("org.mozilla.firefox")
+	require.NotNil(t, entry)
+	assert.Equal(t, "org.mozilla.firefox", entry.ID)
+	assert.Equal(t, "Firefox Web Browser", entry.Name)
+	assert.Equal(t, "Web Browser", entry.GenericName)
+	assert.Equal(t, "firefox", entry.Icon)
+	assert.Equal(t, "firefox", entry.Exec)
+
+	// .desktop suffix, case-insensitive and last-segment fallbacks
+	assert.Same(t, entry, r.LookupDesktopEntry("org.mozilla.firefox.desktop"))
+	assert.Equal(t, "Firefox Web Browser", r.LookupDesktopEntry("firefox").Name)
+	assert.Equal(t, "Firefox Web Browser", r.LookupDesktopEntry("Org.Mozilla.Firefox").Name)
+
+	// Subdirectories map to '-' in the ID
+	konsole := r.LookupDesktopEntry("kde-konsole")
+	require.NotNil(t, konsole)
+	assert.Equal(t, "kde-konsole", konsole.ID)
+	assert.Equal(t, "Konsole", konsole.Name)
+
+	assert.Nil(t, r.LookupDesktopEntry("missing"))
+	assert.Nil(t, r.LookupDesktopEntry(""))
+}
+
+func TestParseDesktopEntry(t *testing.T) {
+	_, err := ParseDesktopEntry(strings.NewReader("[Other]\nName=x\n"))
+	assert.Error(t, err)
+
+	entry, err := ParseDesktopEntry(strings.NewReader(
+		"[Desktop Entry]\nName=Tool\nExec=tool --open %F --title=%c 100%%\nNoDisplay=true\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "tool --open  --title= 100%", entry.Exec)
+	assert.True(t, entry.NoDisplay)
+}
+
+func TestResolveNotification(t *testing.T) {
+	r := newTestResolver("hicolor")
+
+	// Icon name resolves directly
+	n := &model.Notification{AppName: "Slack", IconPath: "slack"}
+	assert.Equal(t, iconPath("hicolor", "scalable", "apps", "slack.svg"), r.NotificationIcon(n, 48))
+	assert.Equal(t, "Slack", r.NotificationAppName(n))
+
+	// Falls back to the desktop entry icon
+	n = &model.Notification{AppName: "ff", Extensions: &model.Extensions{DesktopEntry: "org.mozilla.firefox"}}
+	assert.Equal(t, iconPath("hicolor", "48x48", "apps", "firefox.png"), r.NotificationIcon(n, 48))
+	assert.Equal(t, "Firefox Web Browser", r.NotificationAppName(n))
+
+	// Falls back to an entry found by app name
+	n = &model.Notification{AppName: "Firefox"}
+	assert.Equal(t, iconPath("hicolor", "48x48", "apps", "firefox.png"), r.NotificationIcon(n, 48))
+
+	// Nothing matches
+	n = &model.Notification{AppName: "unknown", IconPath: "nope"}
+	assert.Equal(t, "", r.NotificationIcon(n, 48))
+	assert.Equal(t, "unknown", r.NotificationAppName(n))
+}
@@ -0,0 +1,56 @@
+package icons
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// iniFile holds the groups of a freedesktop key file (index.theme, .desktop).
+// Keys are stored verbatim, so localized keys such as "Name[de]" are
+// separate entries from "Name".
+type iniFile map[string]map[string]string
+
+// parseINI parses a freedesktop key file. Comments and blank lines are
+// skipped and later duplicate keys override earlier ones.
+func parseINI(r io.Reader) (iniFile, error) {
+	file := make(iniFile)
+	var group map[string]string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := line[1 : len(line)-1]
+			group = file[name]
+			if group == nil {
+				group = make(map[string]string)
+				file[name] = group
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || group == nil {
+			continue
+		}
+		group[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return file, scanner.Err()
+}
+
+// splitList splits a comma-separated key file list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
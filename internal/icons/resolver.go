@@ -0,0 +1,395 @@
+// Package icons resolves freedesktop icon names and .desktop entries.
+//
+// Icon names are looked up through the XDG icon theme specification
+// (index.theme inheritance, fixed/scalable/threshold directories, closest
+// size fallback, then pixmaps). Desktop entries are looked up by desktop
+// file ID, with a fallback on the last dotted segment so that "firefox"
+// finds org.mozilla.firefox.desktop. All lookups are cached.
+package icons
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// Options configures a Resolver. Empty fields use the XDG defaults.
+type Options struct {
+	Theme      string   // Icon theme name (GTK settings, then hicolor)
+	IconDirs   []string // Base directories containing icon themes
+	PixmapDirs []string // Fallback directories for unthemed icons
+	AppDirs    []string // Directories containing .desktop files
+}
+
+// Resolver resolves icon names and desktop entries. It is safe for
+// concurrent use.
+type Resolver struct {
+	themeName  string
+	iconDirs   []string
+	pixmapDirs []string
+	appDirs    []string
+
+	mu        sync.Mutex
+	chain     []*theme
+	chainDone bool
+	icons     map[string]string        // "name@size" -> path ("" = not found)
+	entries   map[string]*DesktopEntry // ID -> entry (nil = not found)
+	appIndex  map[string]string        // Lowercase ID or last ID segment -> path
+}
+
+var (
+	defaultResolver     *Resolver
+	defaultResolverOnce sync.Once
+)
+
+// Default returns a shared Resolver using the XDG defaults.
+func Default() *Resolver {
+	defaultResolverOnce.Do(func() {
+		defaultResolver = NewResolver(Options{})
+	})
+	return defaultResolver
+}
+
+// NewResolver creates a Resolver.
+func NewResolver(opts Options) *Resolver {
+	r := &Resolver{
+		themeName:  opts.Theme,
+		iconDirs:   opts.IconDirs,
+		pixmapDirs: opts.PixmapDirs,
+		appDirs:    opts.AppDirs,
+		icons:      make(map[string]string),
+		entries:    make(map[string]*DesktopEntry),
+	}
+
+	if r.themeName == "" {
+		r.themeName = detectThemeName()
+	}
+	if r.iconDirs == nil {
+		home, _ := os.UserHomeDir()
+		if home != "" {
+			r.iconDirs = append(r.iconDirs, filepath.Join(home, ".icons"))
+		}
+		for _, dir := range dataDirs() {
+			r.iconDirs = append(r.iconDirs, filepath.Join(dir, "icons"))
+		}
+	}
+	if r.pixmapDirs == nil {
+		r.pixmapDirs = []string{"/usr/share/pixmaps"}
+	}
+	if r.appDirs == nil {
+		for _, dir := range dataDirs() {
+			r.appDirs = append(r.appDirs, filepath.Join(dir, "applications"))
+		}
+	}
+
+	return r
+}
+
+// LookupIcon resolves an icon name to a file path at the given pixel size.
+// Absolute paths and file:// URIs are returned if the file exists.
+// Returns an empty string if the icon cannot be found.
+func (r *Resolver) LookupIcon(name string, size int) string {
+	if name == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(name, "file://") {
+		if u, err := url.Parse(name); err == nil {
+			name = u.Path
+		}
+	}
+	if filepath.IsAbs(name) {
+		if fileExists(name) {
+			return name
+		}
+		return ""
+	}
+	if strings.Contains(name, "/") {
+		return ""
+	}
+
+	// Some applications include the extension in the icon name
+	for _, ext := range iconExtensions {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := name + "@" + strconv.Itoa(size)
+	if path, ok := r.icons[key]; ok {
+		return path
+	}
+
+	path := r.lookupIconLocked(name, size)
+	r.icons[key] = path
+	return path
+}
+
+// lookupIconLocked searches the theme chain, then the pixmap directories.
+func (r *Resolver) lookupIconLocked(name string, size int) string {
+	for _, t := range r.themeChainLocked() {
+		if path := t.lookup(name, size, 1); path != "" {
+			return path
+		}
+	}
+
+	for _, dir := range r.pixmapDirs {
+		for _, ext := range iconExtensions {
+			path := filepath.Join(dir, name+ext)
+			if fileExists(path) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// themeChainLocked returns the configured theme followed by its ancestors
+// (depth first) and hicolor, loading them on first use.
+func (r *Resolver) themeChainLocked() []*theme {
+	if r.chainDone {
+		return r.chain
+	}
+	r.chainDone = true
+
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		t := loadTheme(name, r.iconDirs)
+		if t == nil {
+			return
+		}
+		r.chain = append(r.chain, t)
+		for _, parent := range t.inherits {
+			visit(parent)
+		}
+	}
+
+	visit(r.themeName)
+	visit("hicolor")
+	return r.chain
+}
+
+// LookupDesktopEntry returns the desktop entry for a desktop file ID such as
+// "org.mozilla.firefox" or "firefox.desktop". If no file has that exact ID,
+// the lookup is retried case-insensitively and against the last dotted
+// segment of each installed ID. Returns nil if nothing matches.
+func (r *Resolver) LookupDesktopEntry(id string) *DesktopEntry {
+	id = strings.TrimSuffix(id, ".desktop")
+	if id == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.entries[id]; ok {
+		return entry
+	}
+
+	var entry *DesktopEntry
+	if path := r.findDesktopFileLocked(id); path != "" {
+		entry = loadDesktopEntry(path)
+		if entry != nil {
+			entry.ID = desktopFileID(r.appDirs, path)
+		}
+	}
+	r.entries[id] = entry
+	return entry
+}
+
+// findDesktopFileLocked returns the path to the .desktop file for id.
+func (r *Resolver) findDesktopFileLocked(id string) string {
+	// Subdirectories map to '-' in desktop file IDs (kde-foo -> kde/foo.desktop)
+	candidates := []string{id + ".desktop", strings.Replace(id, "-", "/", 1) + ".desktop"}
+	for _, dir := range r.appDirs {
+		for _, candidate := range candidates {
+			path := filepath.Join(dir, candidate)
+			if fileExists(path) {
+				return path
+			}
+		}
+	}
+
+	if r.appIndex == nil {
+		r.appIndex = buildAppIndex(r.appDirs)
+	}
+	return r.appIndex[strings.ToLower(id)]
+}
+
+// buildAppIndex maps lowercase desktop file IDs and their last dotted
+// segment to file paths. Earlier directories take precedence.
+func buildAppIndex(dirs []string) map[string]string {
+	index := make(map[string]string)
+	add := func(key, path string) {
+		if _, exists := index[key]; !exists {
+			index[key] = path
+		}
+	}
+
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".desktop") {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return nil
+			}
+			id := strings.ToLower(relToID(rel))
+			add(id, path)
+			if i := strings.LastIndex(id, "."); i >= 0 {
+				add(id[i+1:], path)
+			}
+			return nil
+		})
+	}
+	return index
+}
+
+// loadDesktopEntry parses the .desktop file at path.
+func loadDesktopEntry(path string) *DesktopEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	entry, err := ParseDesktopEntry(f)
+	if err != nil {
+		return nil
+	}
+	entry.Path = path
+	return entry
+}
+
+// desktopFileID returns the desktop file ID for path relative to the first
+// application directory containing it.
+func desktopFileID(dirs []string, path string) string {
+	for _, dir := range dirs {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return relToID(rel)
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".desktop")
+}
+
+// relToID converts a path relative to an applications directory into a
+// desktop file ID (kde/konsole.desktop -> kde-konsole).
+func relToID(rel string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(rel, string(filepath.Separator), "-"), ".desktop")
+}
+
+// Resolve returns the best icon file for a notification's icon name,
+// desktop entry and application name. The icon name is tried first, then
+// the desktop entry's icon, then an entry found by application name.
+func (r *Resolver) Resolve(icon, desktopEntry, appName string, size int) string {
+	if path := r.LookupIcon(icon, size); path != "" {
+		return path
+	}
+	if entry := r.findEntry(desktopEntry, appName); entry != nil {
+		return r.LookupIcon(entry.Icon, size)
+	}
+	return ""
+}
+
+// AppName returns the desktop entry's Name for the application, or appName
+// if no entry is found.
+func (r *Resolver) AppName(desktopEntry, appName string) string {
+	if entry := r.findEntry(desktopEntry, appName); entry != nil && entry.Name != "" {
+		return entry.Name
+	}
+	return appName
+}
+
+// findEntry looks up a desktop entry by ID, then by lowercase app name.
+func (r *Resolver) findEntry(desktopEntry, appName string) *DesktopEntry {
+	if entry := r.LookupDesktopEntry(desktopEntry); entry != nil {
+		return entry
+	}
+	if appName != "" {
+		return r.LookupDesktopEntry(strings.ToLower(strings.ReplaceAll(appName, " ", "-")))
+	}
+	return nil
+}
+
+// NotificationIcon returns the best icon file for n, or an empty string.
+func (r *Resolver) NotificationIcon(n *model.Notification, size int) string {
+	return r.Resolve(n.IconPath, notificationDesktopEntry(n), n.AppName, size)
+}
+
+// NotificationAppName returns a human-friendly application name for n.
+func (r *Resolver) NotificationAppName(n *model.Notification) string {
+	return r.AppName(notificationDesktopEntry(n), n.AppName)
+}
+
+func notificationDesktopEntry(n *model.Notification) string {
+	if n.Extensions != nil {
+		return n.Extensions.DesktopEntry
+	}
+	return ""
+}
+
+// detectThemeName reads the icon theme from the GTK settings files.
+func detectThemeName() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "hicolor"
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+
+	for _, dir := range []string{"gtk-4.0", "gtk-3.0"} {
+		f, err := os.Open(filepath.Join(configHome, dir, "settings.ini"))
+		if err != nil {
+			continue
+		}
+		file, err := parseINI(f)
+		_ = f.Close()
+		if err != nil {
+			continue
+		}
+		if name := strings.Trim(file["Settings"]["gtk-icon-theme-name"], `"`); name != "" {
+			return name
+		}
+	}
+	return "hicolor"
+}
+
+// dataDirs returns $XDG_DATA_HOME followed by $XDG_DATA_DIRS.
+func dataDirs() []string {
+	var dirs []string
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	if dataHome != "" {
+		dirs = append(dirs, dataHome)
+	}
+
+	dataDirsEnv := os.Getenv("XDG_DATA_DIRS")
+	if dataDirsEnv == "" {
+		dataDirsEnv = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range filepath.SplitList(dataDirsEnv) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,196 @@
+package icons
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Directory types from the icon theme spec.
+const (
+	dirTypeFixed     = "Fixed"
+	dirTypeScalable  = "Scalable"
+	dirTypeThreshold = "Threshold"
+)
+
+// iconExtensions lists the supported icon file extensions in lookup order.
+var iconExtensions = []string{".png", ".svg", ".xpm"}
+
+// themeDir is a subdirectory of an icon theme (e.g. "48x48/apps").
+type themeDir struct {
+	path      string
+	size      int
+	scale     int
+	kind      string
+	minSize   int
+	maxSize   int
+	threshold int
+}
+
+// theme is a parsed index.theme.
+type theme struct {
+	name     string
+	inherits []string
+	dirs     []themeDir
+	bases    []string // Base directories containing this theme
+}
+
+// loadTheme reads the named theme's index.theme from the first base
+// directory that has it. Returns nil if the theme does not exist.
+func loadTheme(name string, baseDirs []string) *theme {
+	var t *theme
+	for _, base := range baseDirs {
+		root := filepath.Join(base, name)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			continue
+		}
+
+		if t == nil {
+			f, err := os.Open(filepath.Join(root, "index.theme"))
+			if err != nil {
+				continue
+			}
+			file, err := parseINI(f)
+			_ = f.Close()
+			if err != nil {
+				continue
+			}
+			t = parseTheme(name, file)
+		}
+		t.bases = append(t.bases, root)
+	}
+	return t
+}
+
+// parseTheme builds a theme from a parsed index.theme.
+func parseTheme(name string, file iniFile) *theme {
+	header := file["Icon Theme"]
+	t := &theme{
+		name:     name,
+		inherits: splitList(header["Inherits"]),
+	}
+
+	dirNames := splitList(header["Directories"])
+	dirNames = append(dirNames, splitList(header["ScaledDirectories"])...)
+	for _, dirName := range dirNames {
+		group, ok := file[dirName]
+		if !ok {
+			continue
+		}
+		size := atoiDefault(group["Size"], 0)
+		if size <= 0 {
+			continue
+		}
+		d := themeDir{
+			path:      dirName,
+			size:      size,
+			scale:     atoiDefault(group["Scale"], 1),
+			kind:      group["Type"],
+			minSize:   atoiDefault(group["MinSize"], size),
+			maxSize:   atoiDefault(group["MaxSize"], size),
+			threshold: atoiDefault(group["Threshold"], 2),
+		}
+		if d.kind == "" {
+			d.kind = dirTypeThreshold
+		}
+		t.dirs = append(t.dirs, d)
+	}
+	return t
+}
+
+// matchesSize implements DirectoryMatchesSize from the icon theme spec.
+func (d themeDir) matchesSize(size, scale int) bool {
+	if d.scale != scale {
+		return false
+	}
+	switch d.kind {
+	case dirTypeFixed:
+		return d.size == size
+	case dirTypeScalable:
+		return d.minSize <= size && size <= d.maxSize
+	default:
+		return d.size-d.threshold <= size && size <= d.size+d.threshold
+	}
+}
+
+// sizeDistance implements DirectorySizeDistance from the icon theme spec.
+func (d themeDir) sizeDistance(size, scale int) int {
+	want := size * scale
+	switch d.kind {
+	case dirTypeFixed:
+		return abs(d.size*d.scale - want)
+	case dirTypeScalable:
+		if want < d.minSize*d.scale {
+			return d.minSize*d.scale - want
+		}
+		if want > d.maxSize*d.scale {
+			return want - d.maxSize*d.scale
+		}
+		return 0
+	default:
+		if want < (d.size-d.threshold)*d.scale {
+			return d.minSize*d.scale - want
+		}
+		if want > (d.size+d.threshold)*d.scale {
+			return want - d.maxSize*d.scale
+		}
+		return 0
+	}
+}
+
+// lookup implements LookupIcon from the icon theme spec: an exact size
+// match wins, otherwise the closest size is returned.
+func (t *theme) lookup(name string, size, scale int) string {
+	for _, d := range t.dirs {
+		if !d.matchesSize(size, scale) {
+			continue
+		}
+		if path := t.findFile(d, name); path != "" {
+			return path
+		}
+	}
+
+	closest, minDistance := "", -1
+	for _, d := range t.dirs {
+		distance := d.sizeDistance(size, scale)
+		if minDistance >= 0 && distance >= minDistance {
+			continue
+		}
+		if path := t.findFile(d, name); path != "" {
+			closest, minDistance = path, distance
+		}
+	}
+	return closest
+}
+
+// findFile returns the first existing icon file for name in d.
+func (t *theme) findFile(d themeDir, name string) string {
+	for _, base := range t.bases {
+		for _, ext := range iconExtensions {
+			path := filepath.Join(base, d.path, name+ext)
+			if fileExists(path) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func atoiDefault(s string, def int) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return def
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,137 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// Conditional attributes. Both take a filter expression (same syntax as
+// `histui get --filter`) evaluated against the notification being shown.
+// Relative times like "timestamp>1h" are resolved at each evaluation.
+const (
+	AttrIf     = "if"     // Element is shown only if the expression matches
+	AttrUnless = "unless" // Element is hidden if the expression matches
+	AttrFormat = "format" // Go template rendered by <text>
+)
+
+// compile parses the element's conditional attributes and, for <text>,
+// its format template.
+func (e *LayoutElement) compile() error {
+	if expr, ok := e.Attributes[AttrIf]; ok {
+		parsed, err := core.ParseFilter(expr)
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", AttrIf, expr, err)
+		}
+		e.If = parsed
+	}
+
+	if expr, ok := e.Attributes[AttrUnless]; ok {
+		parsed, err := core.ParseFilter(expr)
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", AttrUnless, expr, err)
+		}
+		e.Unless = parsed
+	}
+
+	if e.Type == ElementTypeText {
+		format, ok := e.Attributes[AttrFormat]
+		if !ok {
+			return fmt.Errorf("<text> requires a %s attribute", AttrFormat)
+		}
+		tmpl, err := template.New("text").Funcs(textFuncs()).Parse(format)
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", AttrFormat, format, err)
+		}
+		e.Format = tmpl
+	}
+
+	return nil
+}
+
+// Visible reports whether the element's if/unless conditions allow it to
+// be shown for n. Elements without conditions are always visible.
+func (e LayoutElement) Visible(n *model.Notification) bool {
+	return e.VisibleAt(n, time.Now())
+}
+
+// VisibleAt is Visible with relative times resolved against now.
+func (e LayoutElement) VisibleAt(n *model.Notification, now time.Time) bool {
+	if cond := e.condition(AttrIf, e.If, now); cond != nil && !cond.Match(*n) {
+		return false
+	}
+	if cond := e.condition(AttrUnless, e.Unless, now); cond != nil && len(cond.Conditions) > 0 && cond.Match(*n) {
+		return false
+	}
+	return true
+}
+
+// condition returns the parsed attr condition, re-parsed against now if it
+// compares times so relative times don't drift from when it was compiled.
+func (e LayoutElement) condition(attr string, parsed *core.FilterExpr, now time.Time) *core.FilterExpr {
+	if parsed == nil || !parsed.HasTime() {
+		return parsed
+	}
+	current, err := core.ParseFilterAt(e.Attributes[attr], now)
+	if err != nil {
+		return parsed // Validated by compile, so only if attributes changed since
+	}
+	return current
+}
+
+// RenderText renders a <text> element's format template for n.
+func (e LayoutElement) RenderText(n *model.Notification) (string, error) {
+	if e.Format == nil {
+		return "", nil
+	}
+	var sb strings.Builder
+	if err := e.Format.Execute(&sb, n); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Evaluate returns the layout's elements for n, dropping elements (and
+// their children) whose conditions do not match.
+func (c *LayoutConfig) Evaluate(n *model.Notification) []LayoutElement {
+	return c.EvaluateAt(n, time.Now())
+}
+
+// EvaluateAt is Evaluate with relative times resolved against now.
+func (c *LayoutConfig) EvaluateAt(n *model.Notification, now time.Time) []LayoutElement {
+	return evaluateElements(c.Elements, n, now)
+}
+
+func evaluateElements(elements []LayoutElement, n *model.Notification, now time.Time) []LayoutElement {
+	result := make([]LayoutElement, 0, len(elements))
+	for _, elem := range elements {
+		if !elem.VisibleAt(n, now) {
+			continue
+		}
+		elem.Children = evaluateElements(elem.Children, n, now)
+		result = append(result, elem)
+	}
+	return result
+}
+
+// textFuncs returns the helper functions available to <text format="...">.
+func textFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"truncate": func(s string, maxLen int) string {
+			runes := []rune(s)
+			if maxLen <= 0 || len(runes) <= maxLen {
+				return s
+			}
+			if maxLen <= 3 {
+				return string(runes[:maxLen])
+			}
+			return string(runes[:maxLen-3]) + "..."
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestParseTemplate_Conditions(t *testing.T) {
+	config, err := ParseTemplateString(`<popup>
+		<header>
+			<icon unless="app=spotify" />
+			<summary />
+		</header>
+		<body if="urgency>=normal" />
+		<box if="app=slack">
+			<text format="{{.AppName | upper}}: {{.Summary}}" />
+		</box>
+	</popup>`)
+	require.NoError(t, err)
+
+	critical := &model.Notification{AppName: "mail", Summary: "Disk full", Urgency: model.UrgencyCritical}
+	elements := config.Evaluate(critical)
+	require.Len(t, elements, 2)
+	assert.Equal(t, ElementTypeHeader, elements[0].Type)
+	require.Len(t, elements[0].Children, 2)
+	assert.Equal(t, ElementTypeIcon, elements[0].Children[0].Type)
+	assert.Equal(t, ElementTypeBody, elements[1].Type)
+
+	spotify := &model.Notification{AppName: "spotify", Summary: "Now playing", Urgency: model.UrgencyLow}
+	elements = config.Evaluate(spotify)
+	require.Len(t, elements, 1)
+	require.Len(t, elements[0].Children, 1)
+	assert.Equal(t, ElementTypeSummary, elements[0].Children[0].Type)
+
+	slack := &model.Notification{AppName: "slack", Summary: "New message", Urgency: model.UrgencyNormal}
+	elements = config.Evaluate(slack)
+	require.Len(t, elements, 3)
+	box := elements[2]
+	require.Len(t, box.Children, 1)
+	text, err := box.Children[0].RenderText(slack)
+	require.NoError(t, err)
+	assert.Equal(t, "SLACK: New message", text)
+
+	// Evaluate doesn't modify the parsed layout
+	assert.Len(t, config.Elements, 3)
+}
+
+func TestParseTemplate_RelativeTimeConditions(t *testing.T) {
+	config, err := ParseTemplateString(`<popup>
+		<summary />
+		<body if="timestamp>1h" />
+	</popup>`)
+	require.NoError(t, err)
+
+	now := time.Now()
+	n := &model.Notification{Summary: "Build done", Timestamp: now.Add(-30 * time.Minute).Unix()}
+	assert.Len(t, config.EvaluateAt(n, now), 2)
+
+	// An hour later the notification is older than 1h, though the layout
+	// was parsed before then
+	assert.Len(t, config.EvaluateAt(n, now.Add(time.Hour)), 1)
+}
+
+func TestParseTemplate_InvalidConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown field", `<popup><body if="colour=red" /></popup>`},
+		{"bad urgency", `<popup><body unless="urgency=urgent" /></popup>`},
+		{"missing operator", `<popup><body if="app" /></popup>`},
+		{"text without format", `<popup><text /></popup>`},
+		{"bad format", `<popup><text format="{{.Summary" /></popup>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplateString(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoader_Cache(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewLoader(dir)
+
+	// Embedded templates are available by name
+	compact, err := loader.Load("compact")
+	require.NoError(t, err)
+
+	again, err := loader.Load("compact")
+	require.NoError(t, err)
+	assert.Same(t, compact, again)
+}
@@ -2,11 +2,17 @@ package layout
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/jmylchreest/histui/internal/core"
 )
 
 // Template represents a parsed notification layout template.
@@ -45,6 +51,7 @@ const (
 	ElementTypeClose      ElementType = "close"
 	ElementTypeImage      ElementType = "image"
 	ElementTypeBox        ElementType = "box"
+	ElementTypeText       ElementType = "text"
 )
 
 // ValidElements lists all recognized element types.
@@ -61,6 +68,7 @@ var ValidElements = map[string]ElementType{
 	"close":       ElementTypeClose,
 	"image":       ElementTypeImage,
 	"box":         ElementTypeBox,
+	"text":        ElementTypeText,
 }
 
 // LayoutConfig represents the parsed layout structure ready for UI building.
@@ -80,58 +88,78 @@ type LayoutElement struct {
 	Type       ElementType
 	Attributes map[string]string
 	Children   []LayoutElement
+
+	// Parsed from the if, unless and format attributes (nil if absent)
+	If     *core.FilterExpr
+	Unless *core.FilterExpr
+	Format *template.Template
 }
 
 // ParseTemplate parses an XML layout template from a reader.
+// Unknown elements, unknown attributes and invalid attribute values are
+// rejected; the returned TemplateErrors lists each one with its line and
+// column.
 func ParseTemplate(r io.Reader) (*LayoutConfig, error) {
-	decoder := xml.NewDecoder(r)
+	p := &parser{decoder: xml.NewDecoder(r)}
 
 	// Find the root <popup> element
 	var config LayoutConfig
 	for {
-		tok, err := decoder.Token()
+		line, col := p.decoder.InputPos()
+		tok, err := p.decoder.Token()
 		if err == io.EOF {
+			p.errorf(line, col, "missing <popup> root element")
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to read template: %w", err)
+			p.syntaxError(err)
+			break
 		}
 
-		if se, ok := tok.(xml.StartElement); ok {
-			if se.Name.Local == "popup" {
-				// Parse popup attributes for sizing
-				for _, attr := range se.Attr {
-					switch attr.Name.Local {
-					case "min-width":
-						if v, err := parsePixelValue(attr.Value); err == nil {
-							config.MinWidth = v
-						}
-					case "max-width":
-						if v, err := parsePixelValue(attr.Value); err == nil {
-							config.MaxWidth = v
-						}
-					case "min-height":
-						if v, err := parsePixelValue(attr.Value); err == nil {
-							config.MinHeight = v
-						}
-					case "max-height":
-						if v, err := parsePixelValue(attr.Value); err == nil {
-							config.MaxHeight = v
-						}
-					}
-				}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local != "popup" {
+			p.errorf(line, col, "root element must be <popup>, got <%s>", se.Name.Local)
+			break
+		}
 
-				// Parse children of popup
-				elements, err := parseElements(decoder)
-				if err != nil {
-					return nil, err
-				}
-				config.Elements = elements
-				break
+		// Parse popup attributes for sizing
+		for _, attr := range se.Attr {
+			if msg := checkAttribute("popup", popupAttributes, attr); msg != "" {
+				p.errorf(line, col, "%s", msg)
+				continue
+			}
+			var target *int
+			switch attr.Name.Local {
+			case "min-width":
+				target = &config.MinWidth
+			case "max-width":
+				target = &config.MaxWidth
+			case "min-height":
+				target = &config.MinHeight
+			case "max-height":
+				target = &config.MaxHeight
+			default:
+				continue
 			}
+			v, err := parsePixelValue(attr.Value)
+			if err != nil {
+				p.errorf(line, col, "invalid %s=%q on <popup>: expected a pixel value", attr.Name.Local, attr.Value)
+				continue
+			}
+			*target = v
 		}
+
+		// Parse children of popup
+		config.Elements = p.parseElements()
+		break
 	}
 
+	if len(p.errs) > 0 {
+		return nil, p.errs
+	}
 	return &config, nil
 }
 
@@ -139,22 +167,57 @@ func ParseTemplate(r io.Reader) (*LayoutConfig, error) {
 func parsePixelValue(s string) (int, error) {
 	s = strings.TrimSpace(s)
 	s = strings.TrimSuffix(s, "px")
-	var v int
-	_, err := fmt.Sscanf(s, "%d", &v)
-	return v, err
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("negative pixel value: %d", v)
+	}
+	return v, nil
 }
 
-// parseElements recursively parses child elements.
-func parseElements(decoder *xml.Decoder) ([]LayoutElement, error) {
+// parser accumulates positioned errors while walking a template.
+type parser struct {
+	decoder *xml.Decoder
+	errs    TemplateErrors
+	fatal   bool // Set on XML syntax errors; parsing stops
+}
+
+func (p *parser) errorf(line, col int, format string, args ...any) {
+	p.errs = append(p.errs, &TemplateError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)})
+}
+
+// syntaxError records an XML decoding error and stops parsing.
+func (p *parser) syntaxError(err error) {
+	line, col := p.decoder.InputPos()
+	var se *xml.SyntaxError
+	if errors.As(err, &se) {
+		line, col = se.Line, 0
+		err = errors.New(se.Msg)
+	}
+	if col == 0 {
+		p.errs = append(p.errs, &TemplateError{Line: line, Column: 1, Msg: err.Error()})
+	} else {
+		p.errorf(line, col, "%s", err.Error())
+	}
+	p.fatal = true
+}
+
+// parseElements recursively parses child elements until the parent's end
+// tag.
+func (p *parser) parseElements() []LayoutElement {
 	var elements []LayoutElement
 
-	for {
-		tok, err := decoder.Token()
+	for !p.fatal {
+		line, col := p.decoder.InputPos()
+		tok, err := p.decoder.Token()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to read element: %w", err)
+			p.syntaxError(err)
+			break
 		}
 
 		switch t := tok.(type) {
@@ -162,7 +225,11 @@ func parseElements(decoder *xml.Decoder) ([]LayoutElement, error) {
 			elemName := strings.ToLower(t.Name.Local)
 			elemType, ok := ValidElements[elemName]
 			if !ok {
-				return nil, fmt.Errorf("unknown element type: %s", elemName)
+				p.errorf(line, col, "unknown element <%s> (valid: %s)", t.Name.Local, validElementNames())
+				if err := p.decoder.Skip(); err != nil {
+					p.syntaxError(err)
+				}
+				continue
 			}
 
 			elem := LayoutElement{
@@ -172,25 +239,28 @@ func parseElements(decoder *xml.Decoder) ([]LayoutElement, error) {
 
 			// Parse attributes
 			for _, attr := range t.Attr {
+				if msg := checkAttribute(elemName, elementAttributes[elemType], attr); msg != "" {
+					p.errorf(line, col, "%s", msg)
+					continue
+				}
 				elem.Attributes[attr.Name.Local] = attr.Value
 			}
+			if err := elem.compile(); err != nil {
+				p.errorf(line, col, "<%s>: %v", elemName, err)
+			}
 
 			// Parse children
-			children, err := parseElements(decoder)
-			if err != nil {
-				return nil, err
-			}
-			elem.Children = children
+			elem.Children = p.parseElements()
 
 			elements = append(elements, elem)
 
 		case xml.EndElement:
 			// End of parent element
-			return elements, nil
+			return elements
 		}
 	}
 
-	return elements, nil
+	return elements
 }
 
 // ParseTemplateString parses a template from a string.
@@ -205,22 +275,105 @@ func LoadTemplate(path string) (*LayoutConfig, error) {
 		return nil, fmt.Errorf("failed to open template: %w", err)
 	}
 	defer func() { _ = f.Close() }()
-	return ParseTemplate(f)
+
+	cfg, err := ParseTemplate(f)
+	if err != nil {
+		return nil, withFile(err, path)
+	}
+	return cfg, nil
+}
+
+// LayoutsDir returns the path to the user's layout templates directory.
+func LayoutsDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "histui", "layouts"), nil
 }
 
 // Loader handles loading layout templates from various sources.
+// Parsed templates are cached by name.
 type Loader struct {
 	templatesDir string
+
+	mu    sync.Mutex
+	cache map[string]*LayoutConfig
 }
 
 // NewLoader creates a new template loader.
 func NewLoader(templatesDir string) *Loader {
-	return &Loader{templatesDir: templatesDir}
+	return &Loader{
+		templatesDir: templatesDir,
+		cache:        make(map[string]*LayoutConfig),
+	}
 }
 
 // Load loads a layout template by name.
-// Checks user directory first, then falls back to embedded default.
+// Checks user directory first, then falls back to the embedded templates.
 func (l *Loader) Load(name string) (*LayoutConfig, error) {
+	if name == "" {
+		name = "default"
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cfg, ok := l.cache[name]; ok {
+		return cfg, nil
+	}
+
+	cfg, err := l.load(name)
+	if err != nil {
+		return nil, err
+	}
+	l.cache[name] = cfg
+	return cfg, nil
+}
+
+// Dir returns the user templates directory watched for changes.
+func (l *Loader) Dir() string {
+	return l.templatesDir
+}
+
+// Invalidate drops the named templates from the cache, or every cached
+// template if no names are given. Later Load calls re-read them.
+func (l *Loader) Invalidate(names ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(names) == 0 {
+		l.cache = make(map[string]*LayoutConfig)
+		return
+	}
+	for _, name := range names {
+		delete(l.cache, name)
+	}
+}
+
+// Validate parses every template in the user directory and returns the
+// errors found, joined. Templates that parse are cached.
+func (l *Loader) Validate() error {
+	if l.templatesDir == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(l.templatesDir, "*.xml"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".xml")
+		if _, err := l.Load(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load reads a template without consulting the cache.
+func (l *Loader) load(name string) (*LayoutConfig, error) {
 	// Check user directory first
 	if l.templatesDir != "" {
 		templatePath := filepath.Join(l.templatesDir, name+".xml")
@@ -229,8 +382,11 @@ func (l *Loader) Load(name string) (*LayoutConfig, error) {
 		}
 	}
 
-	// Fall back to embedded default
-	if name == "default" || name == "" {
+	// Fall back to embedded templates
+	if cfg, found := GetEmbeddedTemplate(name); found {
+		return cfg, nil
+	}
+	if name == "default" {
 		return DefaultLayout(), nil
 	}
 
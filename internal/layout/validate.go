@@ -0,0 +1,108 @@
+package layout
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TemplateError describes a problem at a position in a layout template.
+type TemplateError struct {
+	File   string // Template path (empty when parsed from a reader)
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *TemplateError) Error() string {
+	pos := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.File != "" {
+		pos = e.File + ":" + pos
+	}
+	return pos + ": " + e.Msg
+}
+
+// TemplateErrors collects every problem found in a template.
+type TemplateErrors []*TemplateError
+
+func (e TemplateErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// withFile sets the file name on every error in err, if it is a
+// TemplateErrors.
+func withFile(err error, file string) error {
+	var errs TemplateErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			e.File = file
+		}
+	}
+	return err
+}
+
+// commonAttributes are accepted on every element.
+var commonAttributes = []string{AttrIf, AttrUnless}
+
+// popupAttributes are accepted on the root <popup> element.
+var popupAttributes = []string{"min-width", "max-width", "min-height", "max-height"}
+
+// elementAttributes lists the element-specific attributes.
+var elementAttributes = map[ElementType][]string{
+	ElementTypeBox:  {"orientation"},
+	ElementTypeText: {AttrFormat, "class"},
+}
+
+// attributeValues restricts attributes to a fixed set of values.
+var attributeValues = map[string][]string{
+	"orientation": {"horizontal", "vertical"},
+}
+
+// allowedAttribute reports whether name is valid on an element.
+func allowedAttribute(allowed []string, name string) bool {
+	for _, a := range commonAttributes {
+		if a == name {
+			return true
+		}
+	}
+	for _, a := range allowed {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAttribute validates an attribute's name and value.
+func checkAttribute(element string, allowed []string, attr xml.Attr) string {
+	name := attr.Name.Local
+	if !allowedAttribute(allowed, name) {
+		return fmt.Sprintf("unknown attribute %q on <%s>", name, element)
+	}
+	if values, ok := attributeValues[name]; ok {
+		for _, v := range values {
+			if attr.Value == v {
+				return ""
+			}
+		}
+		return fmt.Sprintf("invalid %s=%q on <%s> (expected %s)",
+			name, attr.Value, element, strings.Join(values, " or "))
+	}
+	return ""
+}
+
+// validElementNames returns the sorted list of element names for messages.
+func validElementNames() string {
+	names := make([]string, 0, len(ValidElements))
+	for name := range ValidElements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
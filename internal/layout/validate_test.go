@@ -0,0 +1,167 @@
+package layout
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplate_Positions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "unknown element",
+			input: "<popup>\n  <body />\n  <footer />\n</popup>",
+			want:  []string{"3:3: unknown element <footer>"},
+		},
+		{
+			name:  "unknown attribute",
+			input: "<popup>\n  <body colour=\"red\" />\n</popup>",
+			want:  []string{`2:3: unknown attribute "colour" on <body>`},
+		},
+		{
+			name:  "invalid pixel value",
+			input: `<popup max-width="wide"><body /></popup>`,
+			want:  []string{`1:1: invalid max-width="wide" on <popup>`},
+		},
+		{
+			name:  "invalid orientation",
+			input: "<popup>\n<box orientation=\"diagonal\" />\n</popup>",
+			want:  []string{`2:1: invalid orientation="diagonal" on <box>`},
+		},
+		{
+			name:  "multiple errors",
+			input: "<popup>\n  <foo />\n  <body bar=\"1\" />\n</popup>",
+			want:  []string{"2:3: unknown element <foo>", `3:3: unknown attribute "bar" on <body>`},
+		},
+		{
+			name:  "wrong root",
+			input: `<layout><body /></layout>`,
+			want:  []string{"1:1: root element must be <popup>"},
+		},
+		{
+			name:  "syntax error",
+			input: "<popup>\n  <body>\n</popup>",
+			want:  []string{"3:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplateString(tt.input)
+			require.Error(t, err)
+
+			var errs TemplateErrors
+			require.ErrorAs(t, err, &errs)
+			require.Len(t, errs, len(tt.want))
+			for i, want := range tt.want {
+				assert.True(t, strings.HasPrefix(errs[i].Error(), want), "got %q, want prefix %q", errs[i].Error(), want)
+			}
+		})
+	}
+}
+
+func TestParseTemplate_PixelValues(t *testing.T) {
+	cfg, err := ParseTemplateString(`<popup min-width="200px" max-width="400" />`)
+	require.NoError(t, err)
+	assert.Equal(t, 200, cfg.MinWidth)
+	assert.Equal(t, 400, cfg.MaxWidth)
+
+	_, err = ParseTemplateString(`<popup min-width="-5" />`)
+	assert.Error(t, err)
+}
+
+func TestEmbeddedTemplatesValid(t *testing.T) {
+	for _, name := range ListEmbeddedTemplates() {
+		data, err := EmbeddedTemplates.ReadFile("templates/" + name + ".xml")
+		require.NoError(t, err)
+		_, err = ParseTemplateString(string(data))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestLoader_ValidateAndInvalidate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mine.xml")
+	require.NoError(t, os.WriteFile(path, []byte("<popup><body /></popup>"), 0o644))
+
+	loader := NewLoader(dir)
+	require.NoError(t, loader.Validate())
+	first, err := loader.Load("mine")
+	require.NoError(t, err)
+	require.Len(t, first.Elements, 1)
+
+	// Cached until invalidated
+	require.NoError(t, os.WriteFile(path, []byte("<popup>\n<body />\n<summary />\n</popup>"), 0o644))
+	cached, err := loader.Load("mine")
+	require.NoError(t, err)
+	assert.Same(t, first, cached)
+
+	loader.Invalidate("mine")
+	reloaded, err := loader.Load("mine")
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Elements, 2)
+
+	// Errors carry the file name and position
+	require.NoError(t, os.WriteFile(path, []byte("<popup>\n<nope />\n</popup>"), 0o644))
+	loader.Invalidate()
+	err = loader.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path+":2:1: unknown element <nope>")
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.xml"), []byte("<popup />"), 0o644))
+
+	var mu sync.Mutex
+	var changes [][]string
+	w := NewWatcher(dir, nil)
+	w.SetPollInterval(10 * time.Millisecond)
+	w.SetChangeCallback(func(names []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, names)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, w.Start(ctx))
+	defer w.Stop()
+
+	// Existing files are not reported on start
+	time.Sleep(30 * time.Millisecond)
+	mu.Lock()
+	assert.Empty(t, changes)
+	mu.Unlock()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.xml"), []byte("<popup />"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "a.xml")))
+
+	// Changes may span several polls; notes.txt is never reported
+	seen := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		var names []string
+		for _, c := range changes {
+			names = append(names, c...)
+		}
+		sort.Strings(names)
+		return names
+	}
+	require.Eventually(t, func() bool { return len(seen()) >= 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, []string{"a", "b"}, seen())
+}
@@ -0,0 +1,193 @@
+package layout
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watcher polls a layouts directory and reports templates that were
+// added, modified or removed.
+type Watcher struct {
+	mu     sync.RWMutex
+	logger *slog.Logger
+
+	// Directory being watched
+	dir string
+
+	// Last seen modification time per template name
+	modTimes map[string]time.Time
+
+	// Polling interval
+	pollInterval time.Duration
+
+	// Callback for changes
+	onChangeCallback func(names []string)
+
+	// Control channels
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	running bool
+}
+
+// NewWatcher creates a new layouts directory watcher.
+func NewWatcher(dir string, logger *slog.Logger) *Watcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Watcher{
+		logger:       logger,
+		dir:          dir,
+		modTimes:     make(map[string]time.Time),
+		pollInterval: 1 * time.Second, // Check every second
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// SetPollInterval sets the polling interval for file changes.
+func (w *Watcher) SetPollInterval(interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pollInterval = interval
+}
+
+// SetChangeCallback sets the callback to invoke when templates change.
+// The callback receives the sorted names (without .xml) of the changed
+// templates.
+func (w *Watcher) SetChangeCallback(callback func(names []string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChangeCallback = callback
+}
+
+// Start begins watching the directory for changes.
+func (w *Watcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	if w.dir == "" {
+		w.mu.Unlock()
+		w.logger.Debug("not watching layouts (no directory)")
+		return nil
+	}
+
+	w.modTimes = w.scan()
+	w.running = true
+	w.stopCh = make(chan struct{})
+	w.doneCh = make(chan struct{})
+	w.mu.Unlock()
+
+	go w.watchLoop(ctx)
+
+	w.logger.Debug("layout watcher started", "dir", w.dir, "interval", w.pollInterval)
+	return nil
+}
+
+// Stop stops watching the directory.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	close(w.stopCh)
+	w.mu.Unlock()
+
+	// Wait for goroutine to finish
+	<-w.doneCh
+	w.logger.Debug("layout watcher stopped")
+}
+
+// watchLoop is the main polling loop.
+func (w *Watcher) watchLoop(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.checkForChanges()
+		}
+	}
+}
+
+// checkForChanges compares the directory against the last scan.
+func (w *Watcher) checkForChanges() {
+	current := w.scan()
+
+	w.mu.Lock()
+	var changed []string
+	for name, modTime := range current {
+		if prev, ok := w.modTimes[name]; !ok || !prev.Equal(modTime) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range w.modTimes {
+		if _, ok := current[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	w.modTimes = current
+	callback := w.onChangeCallback
+	w.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+
+	w.logger.Info("layout templates changed, reloading", "dir", w.dir, "templates", changed)
+	if callback != nil {
+		callback(changed)
+	}
+}
+
+// scan returns the modification time of each template in the directory.
+// A missing directory has no templates.
+func (w *Watcher) scan() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			w.logger.Debug("failed to read layouts directory", "dir", w.dir, "error", err)
+		}
+		return modTimes
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".xml" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		modTimes[strings.TrimSuffix(entry.Name(), ".xml")] = info.ModTime()
+	}
+	return modTimes
+}
+
+// IsRunning returns whether the watcher is currently running.
+func (w *Watcher) IsRunning() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.running
+}
@@ -0,0 +1,305 @@
+// Package markup parses the notification body markup defined by the
+// freedesktop notification spec (<b>, <i>, <u>, <a href>, <img> and XML
+// entities) and renders it for GTK, terminals and plain text. The daemon's
+// sanitizer and the CLI and TUI renderers share one tokenizer, so they agree
+// on what is markup and what is literal text.
+package markup
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Mode selects how bodies are rendered for output.
+type Mode string
+
+// Body rendering modes.
+const (
+	ModeRaw   Mode = "raw"   // Markup as received
+	ModeStrip Mode = "strip" // Plain text
+	ModeANSI  Mode = "ansi"  // ANSI styling with OSC 8 hyperlinks
+)
+
+// ParseMode parses a --markup value.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case ModeRaw, ModeStrip, ModeANSI:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid markup mode %q (valid: raw, strip, ansi)", s)
+	}
+}
+
+// Render renders s in the mode. The empty mode is raw.
+func (m Mode) Render(s string) string {
+	switch m {
+	case ModeStrip:
+		return Strip(s)
+	case ModeANSI:
+		return ANSI(s)
+	default:
+		return s
+	}
+}
+
+// TokenType is the kind of a markup token.
+type TokenType int
+
+// Markup token types.
+const (
+	TextToken        TokenType = iota // Text, with entities decoded
+	StartTagToken                     // <b>
+	EndTagToken                       // </b>
+	SelfClosingToken                  // <img src="..."/>
+)
+
+// Token is a piece of markup: text or a tag.
+type Token struct {
+	Type  TokenType
+	Data  string            // Decoded text, or the lower-case tag name
+	Attrs map[string]string // Tag attributes, decoded
+}
+
+var (
+	// Attributes need values, as in XML, so "a<b and c>d" is not a tag
+	tagPattern  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[a-zA-Z_:][-a-zA-Z0-9_:.]*\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'<>/=]+))*)\s*(/?)>`)
+	attrPattern = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'<>/=]+))`)
+)
+
+// Tokenize splits s into text and tags. A "<" that does not start a
+// well-formed tag is text, so plain bodies such as "a < b" survive intact.
+func Tokenize(s string) []Token {
+	var tokens []Token
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, Token{Type: TextToken, Data: html.UnescapeString(text.String())})
+			text.Reset()
+		}
+	}
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			text.WriteString(s)
+			break
+		}
+		text.WriteString(s[:i])
+		s = s[i:]
+
+		match := tagPattern.FindStringSubmatch(s)
+		if match == nil {
+			text.WriteByte('<')
+			s = s[1:]
+			continue
+		}
+		flush()
+
+		tok := Token{Type: StartTagToken, Data: strings.ToLower(match[2])}
+		switch {
+		case match[1] == "/":
+			tok.Type = EndTagToken
+		case match[4] == "/":
+			tok.Type = SelfClosingToken
+		}
+		if attrs := attrPattern.FindAllStringSubmatch(match[3], -1); len(attrs) > 0 {
+			tok.Attrs = make(map[string]string, len(attrs))
+			for _, a := range attrs {
+				tok.Attrs[strings.ToLower(a[1])] = html.UnescapeString(a[2] + a[3] + a[4])
+			}
+		}
+		tokens = append(tokens, tok)
+		s = s[len(match[0]):]
+	}
+	flush()
+	return tokens
+}
+
+// HasMarkup reports whether s contains tags or entities.
+func HasMarkup(s string) bool {
+	for _, tok := range Tokenize(s) {
+		if tok.Type != TextToken {
+			return true
+		}
+	}
+	return strings.Contains(s, "&") && html.UnescapeString(s) != s
+}
+
+// Strip returns the text of s without markup. Images are replaced by their
+// alt text and <br> by a newline.
+func Strip(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+	var sb strings.Builder
+	for _, tok := range Tokenize(s) {
+		switch {
+		case tok.Type == TextToken:
+			sb.WriteString(tok.Data)
+		case tok.Data == "br" && tok.Type != EndTagToken:
+			sb.WriteByte('\n')
+		case tok.Data == "img" && tok.Type != EndTagToken:
+			sb.WriteString(tok.Attrs["alt"])
+		}
+	}
+	return sb.String()
+}
+
+// ANSI SGR codes turning each style on and off.
+var ansiStyles = map[string][2]string{
+	"b": {"\x1b[1m", "\x1b[22m"},
+	"i": {"\x1b[3m", "\x1b[23m"},
+	"u": {"\x1b[4m", "\x1b[24m"},
+	"s": {"\x1b[9m", "\x1b[29m"},
+}
+
+// ANSI renders s with ANSI styles for <b>, <i>, <u> and <s>, and OSC 8
+// hyperlinks for <a href>. Other tags are dropped, keeping their text.
+func ANSI(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+
+	var sb strings.Builder
+	depth := make(map[string]int) // Open count per style, so nesting is harmless
+	link := false
+	for _, tok := range Tokenize(s) {
+		if tok.Type == TextToken {
+			sb.WriteString(tok.Data)
+			continue
+		}
+
+		if codes, ok := ansiStyles[tok.Data]; ok {
+			switch tok.Type {
+			case StartTagToken:
+				if depth[tok.Data]++; depth[tok.Data] == 1 {
+					sb.WriteString(codes[0])
+				}
+			case EndTagToken:
+				if depth[tok.Data] > 0 {
+					if depth[tok.Data]--; depth[tok.Data] == 0 {
+						sb.WriteString(codes[1])
+					}
+				}
+			}
+			continue
+		}
+
+		switch tok.Data {
+		case "a":
+			if link {
+				sb.WriteString("\x1b]8;;\x1b\\")
+				link = false
+			}
+			if href := tok.Attrs["href"]; tok.Type == StartTagToken && href != "" {
+				sb.WriteString("\x1b]8;;" + stripControl(href) + "\x1b\\")
+				link = true
+			}
+		case "br":
+			if tok.Type != EndTagToken {
+				sb.WriteByte('\n')
+			}
+		case "img":
+			if tok.Type != EndTagToken {
+				sb.WriteString(tok.Attrs["alt"])
+			}
+		}
+	}
+
+	// Close anything left open so styles do not leak past the body
+	if link {
+		sb.WriteString("\x1b]8;;\x1b\\")
+	}
+	for tag, n := range depth {
+		if n > 0 {
+			sb.WriteString(ansiStyles[tag][1])
+		}
+	}
+	return sb.String()
+}
+
+// stripControl removes control characters, which could end an escape
+// sequence early.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// pangoTags are the tags GTK labels render; others are dropped.
+var pangoTags = map[string]bool{
+	"b": true, "i": true, "u": true, "s": true, "tt": true,
+	"big": true, "small": true, "sub": true, "sup": true, "a": true,
+}
+
+// Sanitize returns s as Pango markup that GTK labels accept: supported tags
+// are kept (links with only their href), images become their alt text, text
+// is escaped and tags are balanced. Without this, a stray "<" or "&" makes
+// GTK reject the whole body.
+func Sanitize(s string) string {
+	var sb strings.Builder
+	var open []string
+	for _, tok := range Tokenize(s) {
+		switch {
+		case tok.Type == TextToken:
+			sb.WriteString(escape(tok.Data))
+
+		case tok.Data == "br" && tok.Type != EndTagToken:
+			sb.WriteByte('\n')
+
+		case tok.Data == "img" && tok.Type != EndTagToken:
+			sb.WriteString(escape(tok.Attrs["alt"]))
+
+		case !pangoTags[tok.Data] || tok.Type == SelfClosingToken:
+			// Dropped; any text inside is kept
+
+		case tok.Type == StartTagToken:
+			if tok.Data == "a" {
+				if href := tok.Attrs["href"]; href != "" {
+					sb.WriteString(`<a href="` + escape(href) + `">`)
+				} else {
+					// An anchor without a target is just text
+					continue
+				}
+			} else {
+				sb.WriteString("<" + tok.Data + ">")
+			}
+			open = append(open, tok.Data)
+
+		case tok.Type == EndTagToken:
+			i := lastIndex(open, tok.Data)
+			if i < 0 {
+				continue
+			}
+			// Close tags opened inside this one first
+			for j := len(open) - 1; j >= i; j-- {
+				sb.WriteString("</" + open[j] + ">")
+			}
+			open = open[:i]
+		}
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		sb.WriteString("</" + open[j] + ">")
+	}
+	return sb.String()
+}
+
+// escape escapes text for markup.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+// lastIndex returns the index of the last occurrence of s in ss, or -1.
+func lastIndex(ss []string, s string) int {
+	for i := len(ss) - 1; i >= 0; i-- {
+		if ss[i] == s {
+			return i
+		}
+	}
+	return -1
+}
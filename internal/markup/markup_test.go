@@ -0,0 +1,81 @@
+package markup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize(`Hi <B>there</b> &amp; <a href='https://x.test/?a=1&amp;b=2'>link</a><img src="i.png" alt="pic"/> a < b`)
+	require.Len(t, tokens, 10)
+	assert.Equal(t, Token{Type: TextToken, Data: "Hi "}, tokens[0])
+	assert.Equal(t, Token{Type: StartTagToken, Data: "b"}, tokens[1])
+	assert.Equal(t, Token{Type: EndTagToken, Data: "b"}, tokens[3])
+	assert.Equal(t, " & ", tokens[4].Data)
+	assert.Equal(t, "https://x.test/?a=1&b=2", tokens[5].Attrs["href"])
+	assert.Equal(t, SelfClosingToken, tokens[8].Type)
+	assert.Equal(t, "pic", tokens[8].Attrs["alt"])
+	assert.Equal(t, " a < b", tokens[9].Data, "a stray < is text")
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"plain text", "plain text"},
+		{"<b>Alice</b>: see <a href=\"https://x.test\">this</a>", "Alice: see this"},
+		{"Tom &amp; Jerry &lt;3 &#x1F600;", "Tom & Jerry <3 😀"},
+		{"line<br/>next <img src=\"a.png\" alt=\"[image]\"/>", "line\nnext [image]"},
+		{"if a<b and c>d", "if a<b and c>d"},
+		{"AT&T", "AT&T"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Strip(tt.input), tt.input)
+	}
+}
+
+func TestANSI(t *testing.T) {
+	assert.Equal(t, "\x1b[1mbold\x1b[22m and \x1b[3mit\x1b[23m", ANSI("<b>bold</b> and <i>it</i>"))
+	assert.Equal(t, "\x1b]8;;https://x.test\x1b\\site\x1b]8;;\x1b\\", ANSI(`<a href="https://x.test">site</a>`))
+
+	// Nesting and unclosed tags do not leak styles
+	assert.Equal(t, "\x1b[1ma b\x1b[22m", ANSI("<b>a <b>b</b></b>"))
+	assert.Equal(t, "\x1b[4mopen\x1b[24m", ANSI("<u>open"))
+
+	// Control characters in links cannot end the sequence early
+	assert.Equal(t, "\x1b]8;;https://x.test/]8;;\x1b\\x\x1b]8;;\x1b\\", ANSI("<a href=\"https://x.test/\x1b]8;;\">x</a>"))
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"a < b & c", "a &lt; b &amp; c"},
+		{"<b>x</b> <span foo=\"1\">y</span>", "<b>x</b> y"},
+		{"<b><i>x</b>y</i>", "<b><i>x</i></b>y"},
+		{"<u>unclosed", "<u>unclosed</u>"},
+		{`<a href="https://x.test/?a=1&amp;b=2" onclick="x">l</a>`, `<a href="https://x.test/?a=1&amp;b=2">l</a>`},
+		{`<img src="a.png" alt="<pic>"/>`, "&lt;pic&gt;"},
+		{"</b>stray", "stray"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Sanitize(tt.input), tt.input)
+	}
+}
+
+func TestMode(t *testing.T) {
+	mode, err := ParseMode("STRIP")
+	require.NoError(t, err)
+	assert.Equal(t, "x", mode.Render("<b>x</b>"))
+	assert.Equal(t, "<b>x</b>", ModeRaw.Render("<b>x</b>"))
+	assert.Equal(t, "<b>x</b>", Mode("").Render("<b>x</b>"))
+
+	_, err = ParseMode("html")
+	assert.Error(t, err)
+
+	assert.True(t, HasMarkup("<i>x</i>"))
+	assert.True(t, HasMarkup("a &amp; b"))
+	assert.False(t, HasMarkup("a < b & c"))
+}
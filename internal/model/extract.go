@@ -0,0 +1,134 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jmylchreest/histui/internal/markup"
+)
+
+var (
+	// urlPattern matches http(s) URLs in text.
+	urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+
+	// emailPattern matches email addresses in text.
+	emailPattern = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)
+)
+
+// codePatterns match one-time codes next to words that announce them, e.g.
+// "Your verification code is 123456" or "482 913 is your login code". The
+// first group is the code.
+var codePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:code|otp|passcode|pin|2fa|mfa|verification|verify|one[- ]time|security|login)\b[^0-9\n]{0,30}?\b(\d{3}[- ]\d{3}|\d{4,8})\b`),
+	regexp.MustCompile(`(?i)\b(\d{3}[- ]\d{3}|\d{4,8})\b[^0-9\n]{0,20}?\b(?:is your|code|otp|passcode)\b`),
+}
+
+// CodeIndexes returns the start and end of each one-time code in s, in the
+// order the patterns find them. Codes may be grouped, as in "482-913".
+func CodeIndexes(s string) [][2]int {
+	var indexes [][2]int
+	for _, re := range codePatterns {
+		for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+			indexes = append(indexes, [2]int{m[2], m[3]})
+		}
+	}
+	return indexes
+}
+
+// URLs returns the unique http(s) links in the notification: the dunst
+// urls field, link targets in the body markup and URLs written in the
+// summary or body.
+func (n *Notification) URLs() []string {
+	var urls []string
+	if n.Extensions != nil {
+		urls = append(urls, findURLs(n.Extensions.URLs)...)
+	}
+	for _, href := range hrefs(n.Body) {
+		if lower := strings.ToLower(href); strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+			urls = append(urls, href)
+		}
+	}
+	urls = append(urls, findURLs(n.Summary)...)
+	urls = append(urls, findURLs(markup.Strip(n.Body))...)
+	return unique(urls)
+}
+
+// Emails returns the unique email addresses in the notification's summary,
+// body and mailto: links. Addresses inside URLs are ignored.
+func (n *Notification) Emails() []string {
+	var emails []string
+	for _, href := range hrefs(n.Body) {
+		if addr, ok := strings.CutPrefix(href, "mailto:"); ok {
+			addr, _, _ = strings.Cut(addr, "?")
+			if emailPattern.MatchString(addr) {
+				emails = append(emails, addr)
+			}
+		}
+	}
+	for _, text := range []string{n.Summary, markup.Strip(n.Body)} {
+		text = urlPattern.ReplaceAllString(text, " ")
+		emails = append(emails, emailPattern.FindAllString(text, -1)...)
+	}
+	return unique(emails)
+}
+
+// Codes returns the unique one-time codes in the notification's summary and
+// body, with grouping removed so "482-913" becomes "482913".
+func (n *Notification) Codes() []string {
+	var codes []string
+	for _, text := range []string{n.Summary, markup.Strip(n.Body)} {
+		for _, ix := range CodeIndexes(text) {
+			code := strings.Map(func(r rune) rune {
+				if r < '0' || r > '9' {
+					return -1
+				}
+				return r
+			}, text[ix[0]:ix[1]])
+			codes = append(codes, code)
+		}
+	}
+	return unique(codes)
+}
+
+// findURLs returns the URLs in s, without trailing punctuation that is more
+// likely to end the sentence than the URL.
+func findURLs(s string) []string {
+	urls := urlPattern.FindAllString(s, -1)
+	for i, u := range urls {
+		u = strings.TrimRight(u, ".,;:!?")
+		// Keep a closing bracket only if the URL opened one
+		for strings.HasSuffix(u, ")") && strings.Count(u, "(") < strings.Count(u, ")") ||
+			strings.HasSuffix(u, "]") && strings.Count(u, "[") < strings.Count(u, "]") {
+			u = strings.TrimRight(u[:len(u)-1], ".,;:!?")
+		}
+		urls[i] = u
+	}
+	return urls
+}
+
+// hrefs returns the targets of the links in body markup.
+func hrefs(body string) []string {
+	if !strings.Contains(body, "<") {
+		return nil
+	}
+	var links []string
+	for _, tok := range markup.Tokenize(body) {
+		if tok.Type == markup.StartTagToken && tok.Data == "a" && tok.Attrs["href"] != "" {
+			links = append(links, strings.TrimSpace(tok.Attrs["href"]))
+		}
+	}
+	return links
+}
+
+// unique returns ss without duplicates, keeping the first of each.
+func unique(ss []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_URLs(t *testing.T) {
+	n := Notification{
+		Summary: "Build failed: https://ci.example.com/run/42.",
+		Body: `See <a href="https://example.com/a?x=1&amp;y=2">the log</a> ` +
+			`(mirror: https://example.com/wiki/Foo_(bar)) or https://ci.example.com/run/42`,
+		Extensions: &Extensions{URLs: "[docs] https://docs.example.com"},
+	}
+	assert.Equal(t, []string{
+		"https://docs.example.com",
+		"https://example.com/a?x=1&y=2",
+		"https://ci.example.com/run/42",
+		"https://example.com/wiki/Foo_(bar)",
+	}, n.URLs())
+
+	assert.Empty(t, (&Notification{Body: "no links here"}).URLs())
+}
+
+func TestNotification_Emails(t *testing.T) {
+	n := Notification{
+		Summary: "From alice@example.com",
+		Body:    `<a href="mailto:bob@example.org?subject=hi">Bob</a>, https://user@example.net/x, alice@example.com.`,
+	}
+	assert.Equal(t, []string{"bob@example.org", "alice@example.com"}, n.Emails())
+}
+
+func TestNotification_Codes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Notification
+		want []string
+	}{
+		{"after_keyword", Notification{Body: "Your verification code is 482913"}, []string{"482913"}},
+		{"before_keyword", Notification{Summary: "482913 is your Acme login code"}, []string{"482913"}},
+		{"grouped", Notification{Body: "Security code: 482-913"}, []string{"482913"}},
+		{"markup", Notification{Body: "Your code: <b>7731</b>"}, []string{"7731"}},
+		{"no_keyword", Notification{Body: "Order 123456 shipped"}, nil},
+		{"phone_like", Notification{Body: "Call 0123456789 about your code"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.n.Codes())
+		})
+	}
+}
@@ -31,13 +31,24 @@ var UrgencyNames = map[int]string{
 // This is the normalized format stored in the history and used by all adapters.
 type Notification struct {
 	// histui metadata (added by histui)
-	HistuiID          string `json:"histui_id"`
-	HistuiSource      string `json:"histui_source"`
-	HistuiImportedAt  int64  `json:"histui_imported_at"`
-	HistuiSeenAt      int64  `json:"histui_seen_at,omitempty"`      // When viewed in TUI
-	HistuiActedAt     int64  `json:"histui_acted_at,omitempty"`     // When user acted (copy)
-	HistuiDismissedAt int64  `json:"histui_dismissed_at,omitempty"` // When user dismissed (soft delete)
-	ContentHash       string `json:"content_hash,omitempty"`        // SHA256 hash for deduplication
+	HistuiID           string `json:"histui_id"`
+	HistuiSource       string `json:"histui_source"`
+	HistuiImportedAt   int64  `json:"histui_imported_at"`
+	HistuiSeenAt       int64  `json:"histui_seen_at,omitempty"`       // When viewed in TUI
+	HistuiActedAt      int64  `json:"histui_acted_at,omitempty"`      // When user acted (copy)
+	HistuiDismissedAt  int64  `json:"histui_dismissed_at,omitempty"`  // When user dismissed (soft delete)
+	HistuiSnoozedUntil int64  `json:"histui_snoozed_until,omitempty"` // When histuid should show it again
+	HistuiSnoozedAt    int64  `json:"histui_snoozed_at,omitempty"`    // When the pending snooze was set
+	ContentHash        string `json:"content_hash,omitempty"`         // SHA256 hash for deduplication
+
+	// Redaction metadata (see package redact)
+	HistuiRedacted []string `json:"histui_redacted,omitempty"`  // Redaction rules that matched
+	HistuiRedactAt int64    `json:"histui_redact_at,omitempty"` // When values kept for a TTL are redacted
+
+	// User annotations
+	HistuiTags    []string `json:"histui_tags,omitempty"`    // Lowercase user tags
+	HistuiStarred bool     `json:"histui_starred,omitempty"` // Marked for follow-up
+	HistuiNote    string   `json:"histui_note,omitempty"`    // Free-text note
 
 	// Freedesktop standard fields
 	ID            int    `json:"id"`
@@ -93,6 +104,7 @@ var (
 	ErrEmptySummary      = errors.New("summary cannot be empty")
 	ErrInvalidUrgency    = errors.New("urgency must be 0, 1, or 2")
 	ErrInvalidTimestamp  = errors.New("timestamp must be greater than 0")
+	ErrInvalidTag        = errors.New("tags must be non-empty and cannot contain commas or whitespace")
 )
 
 // NewNotification creates a new Notification with generated ULID and metadata.
@@ -225,6 +237,12 @@ func (n *Notification) ImportedAtTime() time.Time {
 // Clone creates a deep copy of the notification.
 func (n *Notification) Clone() *Notification {
 	clone := *n
+	if n.HistuiTags != nil {
+		clone.HistuiTags = append([]string(nil), n.HistuiTags...)
+	}
+	if n.HistuiRedacted != nil {
+		clone.HistuiRedacted = append([]string(nil), n.HistuiRedacted...)
+	}
 	if n.Extensions != nil {
 		extClone := *n.Extensions
 		clone.Extensions = &extClone
@@ -276,3 +294,89 @@ func (n *Notification) MarkDismissed() {
 func (n *Notification) Undismiss() {
 	n.HistuiDismissedAt = 0
 }
+
+// IsSnoozed returns true if the notification is hidden until a later time.
+func (n *Notification) IsSnoozed() bool {
+	return n.HistuiSnoozedUntil > time.Now().Unix()
+}
+
+// HasPendingSnooze returns true if histuid has yet to show the notification
+// again, including snoozes that expired while the daemon was not running.
+func (n *Notification) HasPendingSnooze() bool {
+	return n.HistuiSnoozedUntil > 0
+}
+
+// SnoozedUntilTime returns the snooze expiry as time.Time.
+func (n *Notification) SnoozedUntilTime() time.Time {
+	return time.Unix(n.HistuiSnoozedUntil, 0)
+}
+
+// Snooze hides the notification until the given time. Snoozing again
+// keeps the time the pending snooze was first set.
+func (n *Notification) Snooze(until time.Time) {
+	if n.HistuiSnoozedUntil == 0 {
+		n.HistuiSnoozedAt = time.Now().Unix()
+	}
+	n.HistuiSnoozedUntil = until.Unix()
+}
+
+// Unsnooze clears a pending snooze and restores the notification,
+// undoing the dismissal that accompanies snoozing a popup. Dismissals from
+// before the snooze are kept.
+func (n *Notification) Unsnooze() {
+	if n.HistuiSnoozedAt > 0 && n.HistuiDismissedAt >= n.HistuiSnoozedAt {
+		n.HistuiDismissedAt = 0
+	}
+	n.HistuiSnoozedUntil = 0
+	n.HistuiSnoozedAt = 0
+}
+
+// NormalizeTag lowercases and validates a user tag.
+// Tags cannot contain commas, which separate filter conditions, or whitespace.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || strings.ContainsAny(tag, ", \t\n") {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// HasTag returns true if the notification carries the given tag.
+func (n *Notification) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range n.HistuiTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds a tag if not already present.
+// The tag slice is reallocated so copies of the notification are unaffected.
+func (n *Notification) AddTag(tag string) {
+	if n.HasTag(tag) {
+		return
+	}
+	tags := make([]string, len(n.HistuiTags), len(n.HistuiTags)+1)
+	copy(tags, n.HistuiTags)
+	n.HistuiTags = append(tags, strings.ToLower(strings.TrimSpace(tag)))
+}
+
+// RemoveTag removes a tag if present.
+// The tag slice is reallocated so copies of the notification are unaffected.
+func (n *Notification) RemoveTag(tag string) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	var kept []string
+	for _, t := range n.HistuiTags {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	n.HistuiTags = kept
+}
+
+// IsStarred returns true if the notification is starred for follow-up.
+func (n *Notification) IsStarred() bool {
+	return n.HistuiStarred
+}
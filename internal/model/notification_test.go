@@ -192,6 +192,10 @@ func TestNotification_DedupeKey(t *testing.T) {
 
 	assert.Equal(t, n1.DedupeKey(), n2.DedupeKey())
 	assert.NotEqual(t, n1.DedupeKey(), n3.DedupeKey())
+
+	// Snooze state does not change the key
+	n2.Snooze(time.Unix(1703584800, 0))
+	assert.Equal(t, n1.DedupeKey(), n2.DedupeKey())
 }
 
 func TestNotification_TimestampTime(t *testing.T) {
@@ -231,6 +235,62 @@ func TestNotification_Clone_NilExtensions(t *testing.T) {
 	assert.Nil(t, clone.Extensions)
 }
 
+func TestNotification_Snooze(t *testing.T) {
+	n := validNotification()
+	assert.False(t, n.IsSnoozed())
+	assert.False(t, n.HasPendingSnooze())
+
+	until := time.Now().Add(30 * time.Minute)
+	n.MarkDismissed()
+	n.Snooze(until)
+	assert.True(t, n.IsSnoozed())
+	assert.True(t, n.HasPendingSnooze())
+	assert.Equal(t, until.Unix(), n.SnoozedUntilTime().Unix())
+
+	// An expired snooze no longer hides the notification but is still
+	// pending until histuid shows it again
+	n.Snooze(time.Now().Add(-time.Minute))
+	assert.False(t, n.IsSnoozed())
+	assert.True(t, n.HasPendingSnooze())
+
+	n.Unsnooze()
+	assert.False(t, n.HasPendingSnooze())
+	assert.False(t, n.IsDismissed())
+
+	// A dismissal from before the snooze outlives it
+	n.HistuiDismissedAt = time.Now().Add(-time.Hour).Unix()
+	n.Snooze(until)
+	n.Unsnooze()
+	assert.True(t, n.IsDismissed())
+}
+
+func TestNotification_Tags(t *testing.T) {
+	tag, err := NormalizeTag("  Work ")
+	require.NoError(t, err)
+	assert.Equal(t, "work", tag)
+
+	for _, bad := range []string{"", "  ", "a,b", "two words"} {
+		_, err := NormalizeTag(bad)
+		assert.ErrorIs(t, err, ErrInvalidTag, "tag %q", bad)
+	}
+
+	n := validNotification()
+	n.AddTag("work")
+	n.AddTag("Work")
+	n.AddTag("urgent")
+	assert.Equal(t, []string{"work", "urgent"}, n.HistuiTags)
+	assert.True(t, n.HasTag("WORK"))
+
+	clone := n.Clone()
+	clone.AddTag("later")
+	assert.Len(t, n.HistuiTags, 2, "clone must not share the tag slice")
+
+	n.RemoveTag("work")
+	n.RemoveTag("urgent")
+	assert.Nil(t, n.HistuiTags)
+	assert.False(t, n.HasTag("work"))
+}
+
 func TestULIDFormat(t *testing.T) {
 	// Verify ULIDs are valid 26-character strings
 	n, err := NewNotification("test")
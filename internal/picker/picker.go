@@ -0,0 +1,198 @@
+// Package picker runs dmenu-style launchers (rofi, fuzzel, wofi, dmenu) and
+// maps the user's selection back to the entry that produced it.
+package picker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrCancelled is returned when the launcher exits without a selection.
+var ErrCancelled = errors.New("selection cancelled")
+
+// Entry is a single line in the launcher menu.
+type Entry struct {
+	Label string // Text shown to the user
+	Icon  string // Icon name or path (only used by launchers that support icons)
+}
+
+// Launcher describes how to run a dmenu-style launcher.
+type Launcher struct {
+	Name       string   // rofi, fuzzel, wofi, dmenu or custom
+	Command    string   // Executable to run
+	Args       []string // Arguments placed before the prompt
+	PromptFlag string   // Flag used to pass the prompt (empty = no prompt)
+	Icons      bool     // Accepts "label\0icon\x1f<icon>" rows
+	Index      bool     // Prints the selected 0-based index instead of the label
+}
+
+// knownLaunchers lists the supported launchers in auto-detection order.
+var knownLaunchers = []Launcher{
+	{
+		Name:       "rofi",
+		Command:    "rofi",
+		Args:       []string{"-dmenu", "-i", "-format", "i", "-show-icons"},
+		PromptFlag: "-p",
+		Icons:      true,
+		Index:      true,
+	},
+	{
+		Name:       "fuzzel",
+		Command:    "fuzzel",
+		Args:       []string{"--dmenu", "--index"},
+		PromptFlag: "--prompt",
+		Icons:      true,
+		Index:      true,
+	},
+	{
+		Name:       "wofi",
+		Command:    "wofi",
+		Args:       []string{"--dmenu", "--insensitive"},
+		PromptFlag: "--prompt",
+	},
+	{
+		Name:       "dmenu",
+		Command:    "dmenu",
+		Args:       []string{"-i"},
+		PromptFlag: "-p",
+	},
+}
+
+// Names returns the names of the supported launchers in detection order.
+func Names() []string {
+	names := make([]string, 0, len(knownLaunchers))
+	for _, l := range knownLaunchers {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// Detect returns the name of the first supported launcher found on PATH.
+// Returns an empty string if none is installed.
+func Detect() string {
+	for _, l := range knownLaunchers {
+		if _, err := exec.LookPath(l.Command); err == nil {
+			return l.Name
+		}
+	}
+	return ""
+}
+
+// New returns the launcher with the given name.
+// If name is empty the launcher is auto-detected. If command is set it
+// replaces the launcher's executable; with name "custom" it is split on
+// whitespace and run as-is, and its output is matched against the labels.
+func New(name, command string) (*Launcher, error) {
+	if name == "custom" {
+		parts := strings.Fields(command)
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("custom launcher requires a command")
+		}
+		return &Launcher{Name: "custom", Command: parts[0], Args: parts[1:]}, nil
+	}
+
+	if name == "" {
+		name = Detect()
+		if name == "" {
+			return nil, fmt.Errorf("no launcher found (install one of: %s)", strings.Join(Names(), ", "))
+		}
+	}
+
+	for _, l := range knownLaunchers {
+		if l.Name != name {
+			continue
+		}
+		launcher := l
+		launcher.Args = append([]string(nil), l.Args...)
+		if command != "" {
+			launcher.Command = command
+		}
+		return &launcher, nil
+	}
+
+	return nil, fmt.Errorf("unknown launcher %q (valid: %s, custom)", name, strings.Join(Names(), ", "))
+}
+
+// Choose shows entries in the launcher and returns the index of the selected
+// entry. Returns ErrCancelled if the user closed the launcher.
+func (l *Launcher) Choose(ctx context.Context, prompt string, entries []Entry) (int, error) {
+	if len(entries) == 0 {
+		return -1, fmt.Errorf("nothing to choose from")
+	}
+
+	args := append([]string(nil), l.Args...)
+	if prompt != "" && l.PromptFlag != "" {
+		args = append(args, l.PromptFlag, prompt)
+	}
+
+	labels := make([]string, len(entries))
+	var input bytes.Buffer
+	for i, e := range entries {
+		labels[i] = cleanLabel(e.Label)
+		input.WriteString(labels[i])
+		if l.Icons && e.Icon != "" {
+			input.WriteString("\x00icon\x1f" + cleanLabel(e.Icon))
+		}
+		input.WriteByte('\n')
+	}
+
+	cmd := exec.CommandContext(ctx, l.Command, args...)
+	cmd.Stdin = &input
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	runErr := cmd.Run()
+	selection := strings.TrimRight(stdout.String(), "\r\n")
+	if selection == "" {
+		var exitErr *exec.ExitError
+		if runErr == nil || errors.As(runErr, &exitErr) {
+			return -1, ErrCancelled
+		}
+		return -1, fmt.Errorf("failed to run %s: %w", l.Name, runErr)
+	}
+	if runErr != nil {
+		return -1, fmt.Errorf("%s exited with error: %w", l.Name, runErr)
+	}
+
+	return l.parseSelection(selection, labels)
+}
+
+// parseSelection maps launcher output back to an entry index.
+func (l *Launcher) parseSelection(selection string, labels []string) (int, error) {
+	if l.Index {
+		idx, err := strconv.Atoi(strings.TrimSpace(selection))
+		if err != nil {
+			return -1, fmt.Errorf("%s returned a non-index selection %q", l.Name, selection)
+		}
+		if idx < 0 || idx >= len(labels) {
+			// rofi and fuzzel print -1 for custom input
+			return -1, ErrCancelled
+		}
+		return idx, nil
+	}
+
+	for i, label := range labels {
+		if label == selection {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("selection %q does not match any entry", selection)
+}
+
+// cleanLabel removes characters that would break the line-based protocol.
+func cleanLabel(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\n', '\r', '\t':
+			return ' '
+		case 0, 0x1f:
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,108 @@
+package picker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLauncher writes a shell script that saves its stdin and arguments to
+// files in dir, prints output and exits with code.
+func fakeLauncher(t *testing.T, output string, code int) (script, dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	script = filepath.Join(dir, "launcher.sh")
+	content := fmt.Sprintf("#!/bin/sh\ncat > %q\necho \"$@\" > %q\nprintf '%%s' '%s'\nexit %d\n",
+		filepath.Join(dir, "stdin"), filepath.Join(dir, "args"), output, code)
+	require.NoError(t, os.WriteFile(script, []byte(content), 0755))
+	return script, dir
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+var testEntries = []Entry{
+	{Label: "firefox | Download complete", Icon: "firefox"},
+	{Label: "slack | New\nmessage", Icon: "/usr/share/icons/slack.png"},
+	{Label: "mail | Inbox"},
+}
+
+func TestChoose_Index(t *testing.T) {
+	script, dir := fakeLauncher(t, "1\n", 0)
+	l, err := New("rofi", script)
+	require.NoError(t, err)
+
+	idx, err := l.Choose(context.Background(), "histui", testEntries)
+	require.NoError(t, err)
+	assert.Equal(t, 1, idx)
+
+	assert.Equal(t,
+		"firefox | Download complete\x00icon\x1ffirefox\n"+
+			"slack | New message\x00icon\x1f/usr/share/icons/slack.png\n"+
+			"mail | Inbox\n",
+		readFile(t, filepath.Join(dir, "stdin")))
+	assert.Equal(t, "-dmenu -i -format i -show-icons -p histui\n", readFile(t, filepath.Join(dir, "args")))
+}
+
+func TestChoose_Text(t *testing.T) {
+	script, dir := fakeLauncher(t, "mail | Inbox\n", 0)
+	l, err := New("dmenu", script)
+	require.NoError(t, err)
+
+	idx, err := l.Choose(context.Background(), "", testEntries)
+	require.NoError(t, err)
+	assert.Equal(t, 2, idx)
+
+	// No icon metadata for launchers that don't support it
+	assert.NotContains(t, readFile(t, filepath.Join(dir, "stdin")), "\x00")
+}
+
+func TestChoose_Cancelled(t *testing.T) {
+	script, _ := fakeLauncher(t, "", 1)
+	l, err := New("fuzzel", script)
+	require.NoError(t, err)
+
+	_, err = l.Choose(context.Background(), "histui", testEntries)
+	assert.ErrorIs(t, err, ErrCancelled)
+}
+
+func TestChoose_Errors(t *testing.T) {
+	script, _ := fakeLauncher(t, "not in the list\n", 0)
+	l, err := New("wofi", script)
+	require.NoError(t, err)
+	_, err = l.Choose(context.Background(), "", testEntries)
+	assert.Error(t, err)
+
+	script, _ = fakeLauncher(t, "7\n", 0)
+	l, err = New("rofi", script)
+	require.NoError(t, err)
+	_, err = l.Choose(context.Background(), "", testEntries)
+	assert.ErrorIs(t, err, ErrCancelled)
+
+	_, err = l.Choose(context.Background(), "", nil)
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("nope", "")
+	assert.Error(t, err)
+
+	_, err = New("custom", "")
+	assert.Error(t, err)
+
+	l, err := New("custom", "my-menu --flag")
+	require.NoError(t, err)
+	assert.Equal(t, "my-menu", l.Command)
+	assert.Equal(t, []string{"--flag"}, l.Args)
+	assert.False(t, l.Index)
+	assert.False(t, l.Icons)
+}
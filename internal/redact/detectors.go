@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// Built-in detector names.
+const (
+	DetectorOTP        = "otp"
+	DetectorTokenURL   = "token_url"
+	DetectorCreditCard = "credit_card"
+)
+
+// BuiltinNames returns the names of the built-in detectors.
+func BuiltinNames() []string {
+	return []string{DetectorOTP, DetectorTokenURL, DetectorCreditCard}
+}
+
+// Builtin returns a rule for a built-in detector.
+func Builtin(name string, action Action, ttl time.Duration, replacement string) (Rule, error) {
+	rule := Rule{Name: name, Action: action, TTL: ttl, Replacement: replacement}
+	switch name {
+	case DetectorOTP:
+		rule.find = findOTP
+	case DetectorTokenURL:
+		rule.find = findURLTokens
+	case DetectorCreditCard:
+		rule.find = findCardNumbers
+	default:
+		return Rule{}, fmt.Errorf("unknown detector %q (available: %s)", name, strings.Join(BuiltinNames(), ", "))
+	}
+	return rule, nil
+}
+
+// findOTP finds one-time codes next to words that announce them, e.g.
+// "Your verification code is 123456". The patterns are shared with code
+// extraction in the model, so what is offered for copying is what is redacted.
+func findOTP(s string) []span {
+	var spans []span
+	for _, ix := range model.CodeIndexes(s) {
+		spans = append(spans, span(ix))
+	}
+	return spans
+}
+
+var (
+	urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s"'<>]+`)
+
+	// urlParamPattern matches query or fragment parameters within a URL.
+	urlParamPattern = regexp.MustCompile(`[?&#;]([^=&#;\s]+)=([^&#;\s]*)`)
+)
+
+// sensitiveParams are substrings of parameter names whose values are secrets.
+var sensitiveParams = []string{
+	"token", "code", "key", "secret", "signature", "sig", "auth",
+	"session", "password", "passwd", "pwd", "otp", "reset", "nonce", "ticket",
+}
+
+// findURLTokens finds the values of secret-looking parameters in URLs,
+// e.g. the token in https://example.com/reset?token=abc123.
+func findURLTokens(s string) []span {
+	var spans []span
+	for _, u := range urlPattern.FindAllStringIndex(s, -1) {
+		url := s[u[0]:u[1]]
+		for _, m := range urlParamPattern.FindAllStringSubmatchIndex(url, -1) {
+			param := strings.ToLower(url[m[2]:m[3]])
+			if m[4] == m[5] {
+				continue // Empty value
+			}
+			for _, p := range sensitiveParams {
+				if strings.Contains(param, p) {
+					spans = append(spans, span{u[0] + m[4], u[0] + m[5]})
+					break
+				}
+			}
+		}
+	}
+	return spans
+}
+
+// cardPattern matches 13-19 digits, optionally grouped by spaces or dashes.
+var cardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+// findCardNumbers finds payment card numbers that pass the Luhn check.
+func findCardNumbers(s string) []span {
+	var spans []span
+	for _, m := range cardPattern.FindAllStringIndex(s, -1) {
+		if luhnValid(s[m[0]:m[1]]) {
+			spans = append(spans, span{m[0], m[1]})
+		}
+	}
+	return spans
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
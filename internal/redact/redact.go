@@ -0,0 +1,263 @@
+// Package redact removes secrets such as one-time codes, tokens in URLs and
+// card numbers from notifications before they are written to the history.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// Action is what happens to a redacted value.
+type Action string
+
+const (
+	// ActionReplace substitutes the value with the rule's replacement text.
+	ActionReplace Action = "replace"
+	// ActionDrop removes the value.
+	ActionDrop Action = "drop"
+	// ActionTTL keeps the value for the rule's TTL, then replaces it.
+	ActionTTL Action = "ttl"
+)
+
+// ParseAction parses an action name.
+func ParseAction(s string) (Action, error) {
+	switch a := Action(strings.ToLower(strings.TrimSpace(s))); a {
+	case ActionReplace, ActionDrop, ActionTTL:
+		return a, nil
+	default:
+		return "", fmt.Errorf("invalid redaction action %q (must be replace, drop or ttl)", s)
+	}
+}
+
+// span is a byte range [start, end) to redact.
+type span [2]int
+
+// Rule finds one kind of secret and decides what happens to it.
+type Rule struct {
+	Name        string
+	Action      Action
+	TTL         time.Duration // How long ActionTTL keeps the value
+	Replacement string        // Text substituted by ActionReplace; "{name}" is the rule name
+
+	find func(s string) []span
+}
+
+// NewPatternRule creates a rule from a regular expression. If the pattern
+// has capture groups, only the groups are redacted.
+func NewPatternRule(name, pattern string, action Action, ttl time.Duration, replacement string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return Rule{
+		Name:        name,
+		Action:      action,
+		TTL:         ttl,
+		Replacement: replacement,
+		find:        regexSpans(re),
+	}, nil
+}
+
+// replacement returns the text substituted for a value.
+func (r Rule) replacement() string {
+	if r.Action == ActionDrop {
+		return ""
+	}
+	return strings.ReplaceAll(r.Replacement, "{name}", r.Name)
+}
+
+// Redactor applies redaction rules to notifications.
+// It implements store.Redactor.
+type Redactor struct {
+	rules []Rule
+	now   func() time.Time
+}
+
+// New creates a redactor. Rules are applied in order; where matches
+// overlap, the earlier rule wins.
+func New(rules ...Rule) *Redactor {
+	return &Redactor{rules: rules, now: time.Now}
+}
+
+// Rules returns the redactor's rules.
+func (r *Redactor) Rules() []Rule {
+	return r.rules
+}
+
+// Redact removes secrets from a notification before it is stored.
+// Values matched by TTL rules are kept and the notification is marked to
+// be redacted once the shortest matching TTL passes (see Expire). Matched
+// rule names are recorded in HistuiRedacted. The content hash is computed
+// from the fully redacted content, so re-imports of the same notification
+// still deduplicate and the history never holds a hash of the secret.
+func (r *Redactor) Redact(n *model.Notification) {
+	full := n.Clone()
+	if len(r.apply(full, true)) == 0 {
+		return
+	}
+	full.ContentHash = ""
+	n.ContentHash = full.ComputeContentHash()
+
+	matched := r.apply(n, false)
+	for _, name := range matched {
+		if !slices.Contains(n.HistuiRedacted, name) {
+			n.HistuiRedacted = append(n.HistuiRedacted, name)
+		}
+	}
+
+	var ttl time.Duration
+	for _, rule := range r.rules {
+		if rule.Action == ActionTTL && slices.Contains(matched, rule.Name) && (ttl == 0 || rule.TTL < ttl) {
+			ttl = rule.TTL
+		}
+	}
+	if ttl > 0 {
+		n.HistuiRedactAt = r.now().Add(ttl).Unix()
+	}
+}
+
+// Expire redacts values kept by TTL rules once their time has passed.
+// Returns true if the notification was changed.
+func (r *Redactor) Expire(n *model.Notification, now time.Time) bool {
+	if n.HistuiRedactAt == 0 || n.HistuiRedactAt > now.Unix() {
+		return false
+	}
+	r.apply(n, true)
+	n.HistuiRedactAt = 0
+	return true
+}
+
+// String redacts a single string, treating TTL rules as already expired.
+func (r *Redactor) String(s string) string {
+	out, _ := r.redactString(s, true)
+	return out
+}
+
+// apply redacts the notification's text fields and returns the names of
+// the rules that matched. TTL rules only change the text when expired is set.
+func (r *Redactor) apply(n *model.Notification, expired bool) []string {
+	var matched []string
+	field := func(s *string) {
+		out, names := r.redactString(*s, expired)
+		*s = out
+		for _, name := range names {
+			if !slices.Contains(matched, name) {
+				matched = append(matched, name)
+			}
+		}
+	}
+
+	field(&n.Summary)
+	field(&n.Body)
+	if n.Extensions != nil {
+		field(&n.Extensions.Message)
+		field(&n.Extensions.URLs)
+	}
+	return matched
+}
+
+// redactString applies every rule to s and returns the result with the
+// names of the rules that matched.
+func (r *Redactor) redactString(s string, expired bool) (string, []string) {
+	if s == "" {
+		return s, nil
+	}
+
+	type hit struct {
+		span
+		rule int
+	}
+	var hits []hit
+	for i, rule := range r.rules {
+		for _, sp := range rule.find(s) {
+			overlaps := false
+			for _, h := range hits {
+				if sp[0] < h.span[1] && h.span[0] < sp[1] {
+					overlaps = true
+					break
+				}
+			}
+			if !overlaps {
+				hits = append(hits, hit{span: sp, rule: i})
+			}
+		}
+	}
+	if len(hits) == 0 {
+		return s, nil
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].span[0] < hits[j].span[0] })
+
+	var b strings.Builder
+	var names []string
+	last := 0
+	for _, h := range hits {
+		rule := r.rules[h.rule]
+		if !slices.Contains(names, rule.Name) {
+			names = append(names, rule.Name)
+		}
+		if rule.Action == ActionTTL && !expired {
+			continue
+		}
+		b.WriteString(s[last:h.span[0]])
+		b.WriteString(rule.replacement())
+		last = h.span[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), names
+}
+
+// regexSpans returns a finder for a regular expression, redacting its
+// capture groups if it has any and the whole match otherwise.
+func regexSpans(re *regexp.Regexp) func(string) []span {
+	return func(s string) []span {
+		var spans []span
+		for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+			if len(m) == 2 {
+				spans = append(spans, span{m[0], m[1]})
+				continue
+			}
+			for g := 2; g+1 < len(m); g += 2 {
+				if m[g] >= 0 && m[g] < m[g+1] {
+					spans = append(spans, span{m[g], m[g+1]})
+				}
+			}
+		}
+		return spans
+	}
+}
+
+// ExpireDue redacts every notification in the store whose TTL has passed,
+// persisting the changes in one write. The store then compacts its log, so
+// the unredacted originals do not remain on disk. Returns the number changed.
+func (r *Redactor) ExpireDue(s *store.Store, now time.Time) (int, error) {
+	var due []string
+	for _, n := range s.All() {
+		if n.HistuiRedactAt > 0 && n.HistuiRedactAt <= now.Unix() {
+			due = append(due, n.HistuiID)
+		}
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	expired := 0
+	err := s.Batch(func(tx *store.Tx) error {
+		for _, id := range due {
+			n := tx.Get(id)
+			if n != nil && r.Expire(n, now) {
+				tx.Update(*n)
+				expired++
+			}
+		}
+		return nil
+	})
+	return expired, err
+}
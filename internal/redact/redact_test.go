@@ -0,0 +1,146 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func builtins(t *testing.T, action Action, ttl time.Duration) []Rule {
+	t.Helper()
+	var rules []Rule
+	for _, name := range BuiltinNames() {
+		rule, err := Builtin(name, action, ttl, "[{name}]")
+		require.NoError(t, err)
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func TestDetectors(t *testing.T) {
+	r := New(builtins(t, ActionReplace, 0)...)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"otp_after_keyword", "Your verification code is 482913", "Your verification code is [otp]"},
+		{"otp_before_keyword", "482913 is your Acme login code", "[otp] is your Acme login code"},
+		{"otp_grouped", "Security code: 482-913", "Security code: [otp]"},
+		{"plain_number", "Build 482913 finished", "Build 482913 finished"},
+		{"url_token", "Reset: https://acme.test/reset?user=bob&token=s3cr3t#top", "Reset: https://acme.test/reset?user=bob&token=[token_url]#top"},
+		{"url_plain", "See https://acme.test/docs?page=2", "See https://acme.test/docs?page=2"},
+		{"card", "Card 4111 1111 1111 1111 charged", "Card [credit_card] charged"},
+		{"card_bad_luhn", "Order 4111 1111 1111 1112 shipped", "Order 4111 1111 1111 1112 shipped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.String(tt.in))
+		})
+	}
+
+	_, err := Builtin("nope", ActionReplace, 0, "")
+	assert.Error(t, err)
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	ticket, err := NewPatternRule("ticket", `TICKET-(\d+)`, ActionDrop, 0, "")
+	require.NoError(t, err)
+	r := New(append(builtins(t, ActionReplace, 0), ticket)...)
+
+	n := model.Notification{
+		AppName:   "Acme",
+		Summary:   "Sign-in code",
+		Body:      "Your code is 123456 (TICKET-42)",
+		Timestamp: 1700000000,
+	}
+	original := n
+	r.Redact(&n)
+
+	assert.Equal(t, "Your code is [otp] (TICKET-)", n.Body)
+	assert.Equal(t, []string{"otp", "ticket"}, n.HistuiRedacted)
+	assert.Zero(t, n.HistuiRedactAt)
+	assert.Equal(t, n.ComputeContentHash(), n.ContentHash)
+	assert.NotEqual(t, original.ComputeContentHash(), n.ContentHash, "hash must not be of the secret")
+
+	// Redacting again is a no-op, so re-imports deduplicate
+	again := original
+	r.Redact(&again)
+	assert.Equal(t, n.ContentHash, again.ContentHash)
+
+	clean := model.Notification{AppName: "Acme", Summary: "Hello", Body: "No secrets"}
+	r.Redact(&clean)
+	assert.Empty(t, clean.HistuiRedacted)
+	assert.Empty(t, clean.ContentHash)
+}
+
+func TestRedactor_TTL(t *testing.T) {
+	now := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	r := New(builtins(t, ActionTTL, 10*time.Minute)...)
+	r.now = func() time.Time { return now }
+
+	n := model.Notification{AppName: "Bank", Summary: "OTP", Body: "Your one-time code: 987654", Timestamp: now.Unix()}
+	r.Redact(&n)
+
+	// The code is kept until the TTL passes, but the hash is already redacted
+	assert.Equal(t, "Your one-time code: 987654", n.Body)
+	assert.Equal(t, []string{"otp"}, n.HistuiRedacted)
+	assert.Equal(t, now.Add(10*time.Minute).Unix(), n.HistuiRedactAt)
+	hash := n.ContentHash
+
+	assert.False(t, r.Expire(&n, now.Add(5*time.Minute)))
+	assert.True(t, r.Expire(&n, now.Add(10*time.Minute)))
+	assert.Equal(t, "Your one-time code: [otp]", n.Body)
+	assert.Zero(t, n.HistuiRedactAt)
+	assert.Equal(t, hash, n.ContentHash)
+	assert.Equal(t, hash, n.ComputeContentHash())
+}
+
+func TestParseAction(t *testing.T) {
+	a, err := ParseAction(" TTL ")
+	require.NoError(t, err)
+	assert.Equal(t, ActionTTL, a)
+
+	_, err = ParseAction("shred")
+	assert.Error(t, err)
+}
+
+func TestRedactor_ExpireDue(t *testing.T) {
+	now := time.Now()
+	r := New(builtins(t, ActionTTL, time.Minute)...)
+	r.now = func() time.Time { return now }
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	p, err := store.NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s := store.NewStore(p)
+	defer s.Close()
+	s.SetRedactor(r)
+
+	n := model.Notification{HistuiID: "otp", HistuiSource: "test", AppName: "Bank", Summary: "Sign in", Body: "Code: 246810", Timestamp: now.Unix()}
+	require.NoError(t, s.Add(n))
+	assert.Equal(t, "Code: 246810", s.GetByID("otp").Body)
+
+	count, err := r.ExpireDue(s, now)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	count, err = r.ExpireDue(s, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Code: [otp]", s.GetByID("otp").Body)
+
+	// The original is gone from disk, not just superseded
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "246810")
+	assert.Contains(t, string(data), "Code: [otp]")
+}
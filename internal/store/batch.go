@@ -0,0 +1,222 @@
+package store
+
+import (
+	"time"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// Tx stages mutations for Store.Batch.
+// Changes are only visible to the store once the batch function returns nil.
+type Tx struct {
+	s *Store
+
+	updated    map[string]model.Notification // histui_id -> staged notification
+	deleted    map[string]bool               // histui_id -> true
+	tombstoned map[string]bool               // histui_id -> true (subset of deleted)
+	ops        []Op                          // Mutations to log, in order
+	scrub      bool                          // Content deleted or replaced; compact once logged
+}
+
+// Get returns the staged state of a notification by its ULID.
+// Returns nil if it does not exist or has been deleted in this batch.
+func (tx *Tx) Get(id string) *model.Notification {
+	if tx.deleted[id] {
+		return nil
+	}
+	if n, ok := tx.updated[id]; ok {
+		return &n
+	}
+	if idx, exists := tx.s.index[id]; exists {
+		n := tx.s.notifications[idx]
+		return &n
+	}
+	return nil
+}
+
+// Update stages a replacement for an existing notification.
+// Returns false if the notification was not found.
+func (tx *Tx) Update(n model.Notification) bool {
+	old := tx.Get(n.HistuiID)
+	if old == nil {
+		return false
+	}
+	tx.scrub = tx.scrub || replacesContent(old, &n)
+	tx.updated[n.HistuiID] = n
+	tx.ops = append(tx.ops, Op{Type: OpUpdate, ID: n.HistuiID, Notification: &n})
+	return true
+}
+
+// Dismiss marks a notification as dismissed.
+// Returns false if the notification was not found.
+func (tx *Tx) Dismiss(id string) bool {
+	return tx.modify(id, Op{Type: OpDismiss}, (*model.Notification).MarkDismissed)
+}
+
+// Undismiss clears the dismissed state of a notification.
+// Returns false if the notification was not found.
+func (tx *Tx) Undismiss(id string) bool {
+	return tx.modify(id, Op{Type: OpUndismiss}, (*model.Notification).Undismiss)
+}
+
+// MarkSeen marks a notification as seen.
+// Returns false if the notification was not found.
+func (tx *Tx) MarkSeen(id string) bool {
+	return tx.modify(id, Op{Type: OpSeen}, (*model.Notification).MarkSeen)
+}
+
+// Snooze hides a notification until the given time.
+// Returns false if the notification was not found.
+func (tx *Tx) Snooze(id string, until time.Time) bool {
+	return tx.modify(id, Op{Type: OpUpdate}, func(n *model.Notification) { n.Snooze(until) })
+}
+
+// Unsnooze clears a pending snooze and restores the notification.
+// Returns false if the notification was not found.
+func (tx *Tx) Unsnooze(id string) bool {
+	return tx.modify(id, Op{Type: OpUpdate}, (*model.Notification).Unsnooze)
+}
+
+// Tag adds a user tag to a notification. The tag must already be normalized.
+// Returns false if the notification was not found.
+func (tx *Tx) Tag(id, tag string) bool {
+	return tx.modify(id, Op{Type: OpTag, Tag: tag}, func(n *model.Notification) { n.AddTag(tag) })
+}
+
+// Untag removes a user tag from a notification.
+// Returns false if the notification was not found.
+func (tx *Tx) Untag(id, tag string) bool {
+	return tx.modify(id, Op{Type: OpUntag, Tag: tag}, func(n *model.Notification) { n.RemoveTag(tag) })
+}
+
+// SetStarred stars or unstars a notification.
+// Returns false if the notification was not found.
+func (tx *Tx) SetStarred(id string, starred bool) bool {
+	return tx.modify(id, Op{Type: OpUpdate}, func(n *model.Notification) { n.HistuiStarred = starred })
+}
+
+// SetNote replaces the free-text note on a notification. An empty note clears it.
+// Returns false if the notification was not found.
+func (tx *Tx) SetNote(id, note string) bool {
+	return tx.modify(id, Op{Type: OpUpdate}, func(n *model.Notification) { n.HistuiNote = note })
+}
+
+// Delete removes a notification.
+// Returns false if the notification was not found.
+func (tx *Tx) Delete(id string) bool {
+	if tx.Get(id) == nil {
+		return false
+	}
+	delete(tx.updated, id)
+	tx.deleted[id] = true
+	tx.scrub = true
+	tx.ops = append(tx.ops, Op{Type: OpDelete, ID: id})
+	return true
+}
+
+// DeleteWithTombstone removes a notification and remembers its hash to prevent reimport.
+// Returns false if the notification was not found.
+func (tx *Tx) DeleteWithTombstone(id string) bool {
+	if !tx.Delete(id) {
+		return false
+	}
+	tx.tombstoned[id] = true
+	return true
+}
+
+// modify applies fn to the staged copy of a notification and logs op for it.
+// The op's ID is filled in, along with the time of dismiss and seen ops and
+// the resulting notification of update ops.
+func (tx *Tx) modify(id string, op Op, fn func(*model.Notification)) bool {
+	n := tx.Get(id)
+	if n == nil {
+		return false
+	}
+	old := *n
+	fn(n)
+	tx.scrub = tx.scrub || replacesContent(&old, n)
+	tx.updated[id] = *n
+
+	op.ID = id
+	switch op.Type {
+	case OpDismiss:
+		op.At = n.HistuiDismissedAt
+	case OpSeen:
+		op.At = n.HistuiSeenAt
+	case OpUpdate:
+		op.Notification = n
+	}
+	tx.ops = append(tx.ops, op)
+	return true
+}
+
+// Batch applies many mutations under a single lock.
+// fn stages changes through the Tx; if it returns an error nothing is applied.
+// Otherwise the changes are logged with one append, committed and announced
+// with a single ChangeTypeBatch event; if logging fails the store is left as
+// it was. Batches that delete or replace content then compact the log, so it
+// does not remain on disk.
+//
+// fn runs with the store locked, so it must not call other Store methods
+// such as GetByID or All, which would deadlock; it reads through tx.Get.
+func (s *Store) Batch(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	tx := &Tx{
+		s:          s,
+		updated:    make(map[string]model.Notification),
+		deleted:    make(map[string]bool),
+		tombstoned: make(map[string]bool),
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	count := len(tx.updated) + len(tx.deleted)
+	if count == 0 {
+		return nil
+	}
+
+	// Persist once for the whole batch, before memory changes
+	if s.persistence != nil {
+		if err := s.persistence.AppendOps(tx.ops); err != nil {
+			return err
+		}
+	}
+
+	for id, n := range tx.updated {
+		s.notifications[s.index[id]] = n
+	}
+
+	if len(tx.deleted) > 0 {
+		kept := s.notifications[:0]
+		for _, n := range s.notifications {
+			if !tx.deleted[n.HistuiID] {
+				kept = append(kept, n)
+				continue
+			}
+			if tx.tombstoned[n.HistuiID] {
+				n.EnsureContentHash()
+				s.tombstones[n.ContentHash] = true
+			}
+		}
+		s.notifications = kept
+		s.rebuildIndices()
+	}
+
+	s.notifyChange(ChangeEvent{
+		Type:  ChangeTypeBatch,
+		Count: count,
+	})
+
+	if tx.scrub && s.persistence != nil {
+		return s.persistence.Compact()
+	}
+	return nil
+}
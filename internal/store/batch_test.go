@@ -0,0 +1,207 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// countingPersistence records logged ops and discards everything else.
+type countingPersistence struct {
+	appends  int   // AppendOps calls
+	ops      []Op  // Ops logged
+	compacts int   // Compact calls
+	err      error // Returned by AppendOps instead of logging
+}
+
+func (p *countingPersistence) Load() ([]model.Notification, error)       { return nil, nil }
+func (p *countingPersistence) Append(model.Notification) error           { return nil }
+func (p *countingPersistence) AppendBatch(ns []model.Notification) error { return nil }
+func (p *countingPersistence) AppendOps(ops []Op) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.appends++
+	p.ops = append(p.ops, ops...)
+	return nil
+}
+func (p *countingPersistence) Compact() error                        { p.compacts++; return nil }
+func (p *countingPersistence) LoadTail() ([]LogEntry, bool, error)   { return nil, false, nil }
+func (p *countingPersistence) Rewrite(ns []model.Notification) error { return nil }
+func (p *countingPersistence) Clear() error                          { return nil }
+func (p *countingPersistence) Close() error                          { return nil }
+
+func TestStore_Batch(t *testing.T) {
+	p := &countingPersistence{}
+	s := NewStore(p)
+	defer s.Close()
+
+	for _, id := range []string{"b1", "b2", "b3", "b4"} {
+		require.NoError(t, s.Add(testNotification(id)))
+	}
+
+	ch := s.Subscribe()
+
+	err := s.Batch(func(tx *Tx) error {
+		assert.True(t, tx.Dismiss("b1"))
+		assert.True(t, tx.MarkSeen("b2"))
+		assert.True(t, tx.Delete("b3"))
+		assert.False(t, tx.Dismiss("missing"))
+
+		// Deleted items are no longer visible within the batch
+		assert.Nil(t, tx.Get("b3"))
+		assert.False(t, tx.Dismiss("b3"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, p.appends, "batch should persist once")
+	require.Len(t, p.ops, 3)
+	assert.Equal(t, OpDismiss, p.ops[0].Type)
+	assert.Equal(t, s.GetByID("b1").HistuiDismissedAt, p.ops[0].At)
+	assert.Equal(t, Op{Type: OpSeen, ID: "b2", At: s.GetByID("b2").HistuiSeenAt}, p.ops[1])
+	assert.Equal(t, Op{Type: OpDelete, ID: "b3"}, p.ops[2])
+	assert.Equal(t, 3, s.Count())
+	assert.True(t, s.GetByID("b1").IsDismissed())
+	assert.True(t, s.GetByID("b2").IsSeen())
+	assert.Nil(t, s.GetByID("b3"))
+	assert.NotNil(t, s.GetByID("b4"))
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, ChangeTypeBatch, event.Type)
+		assert.Equal(t, 3, event.Count)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected extra event: %+v", event)
+	default:
+	}
+}
+
+func TestStore_BatchRollback(t *testing.T) {
+	p := &countingPersistence{}
+	s := NewStore(p)
+	defer s.Close()
+
+	require.NoError(t, s.Add(testNotification("r1")))
+
+	errAbort := errors.New("abort")
+	err := s.Batch(func(tx *Tx) error {
+		tx.Dismiss("r1")
+		tx.Delete("r1")
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	assert.Equal(t, 0, p.appends)
+	require.NotNil(t, s.GetByID("r1"))
+	assert.False(t, s.GetByID("r1").IsDismissed())
+}
+
+func TestStore_BatchWriteFailure(t *testing.T) {
+	errWrite := errors.New("disk full")
+	p := &countingPersistence{}
+	s := NewStore(p)
+	defer s.Close()
+
+	n := testNotification("w1")
+	require.NoError(t, s.Add(n))
+	require.NoError(t, s.Add(testNotification("w2")))
+
+	p.err = errWrite
+	err := s.Batch(func(tx *Tx) error {
+		tx.Dismiss("w1")
+		tx.DeleteWithTombstone("w2")
+		return nil
+	})
+	assert.ErrorIs(t, err, errWrite)
+
+	// Memory still matches what is on disk
+	assert.Equal(t, 2, s.Count())
+	assert.False(t, s.GetByID("w1").IsDismissed())
+	assert.NotNil(t, s.GetByID("w2"))
+	assert.Empty(t, s.GetTombstones())
+	assert.Zero(t, p.compacts)
+}
+
+func TestStore_BatchTombstone(t *testing.T) {
+	s := NewStore(nil)
+	defer s.Close()
+
+	n := testNotification("t1")
+	require.NoError(t, s.Add(n))
+
+	err := s.Batch(func(tx *Tx) error {
+		tx.DeleteWithTombstone("t1")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.Count())
+
+	// Re-adding deleted content is blocked by the tombstone
+	require.NoError(t, s.Add(n))
+	assert.Equal(t, 0, s.Count())
+}
+
+func TestStore_BatchNoop(t *testing.T) {
+	p := &countingPersistence{}
+	s := NewStore(p)
+	defer s.Close()
+
+	err := s.Batch(func(tx *Tx) error {
+		tx.Dismiss("missing")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, p.appends)
+}
+
+func TestStore_BatchAnnotations(t *testing.T) {
+	s := NewStore(nil)
+	defer s.Close()
+
+	require.NoError(t, s.Add(testNotification("a1")))
+
+	err := s.Batch(func(tx *Tx) error {
+		assert.True(t, tx.Tag("a1", "work"))
+		assert.True(t, tx.Tag("a1", "followup"))
+		assert.True(t, tx.SetStarred("a1", true))
+		assert.True(t, tx.SetNote("a1", "call back"))
+		assert.False(t, tx.Tag("missing", "work"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	n := s.GetByID("a1")
+	assert.Equal(t, []string{"work", "followup"}, n.HistuiTags)
+	assert.True(t, n.IsStarred())
+	assert.Equal(t, "call back", n.HistuiNote)
+
+	// A rolled-back batch must not leak tag changes into the store
+	_ = s.Batch(func(tx *Tx) error {
+		tx.Untag("a1", "work")
+		tx.Tag("a1", "other")
+		return errors.New("abort")
+	})
+	assert.Equal(t, []string{"work", "followup"}, s.GetByID("a1").HistuiTags)
+
+	err = s.Batch(func(tx *Tx) error {
+		tx.Untag("a1", "work")
+		tx.SetStarred("a1", false)
+		tx.SetNote("a1", "")
+		return nil
+	})
+	require.NoError(t, err)
+	n = s.GetByID("a1")
+	assert.Equal(t, []string{"followup"}, n.HistuiTags)
+	assert.False(t, n.IsStarred())
+	assert.Empty(t, n.HistuiNote)
+}
@@ -0,0 +1,313 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Encryption errors.
+var (
+	ErrHistoryEncrypted = errors.New("history file is encrypted and no key is configured")
+	ErrWrongHistoryKey  = errors.New("history key does not match the key the history file was encrypted with")
+	ErrHistoryTampered  = errors.New("encrypted history records are out of sequence: some were removed, reordered or replayed")
+)
+
+// Encryption schemes recorded in the history file header.
+const (
+	SchemeX25519     = "x25519"
+	SchemePassphrase = "pbkdf2-sha256"
+)
+
+// identityPrefix marks an X25519 identity in a key file or variable.
+// Anything else is treated as a passphrase.
+const identityPrefix = "HISTUI-X25519-KEY-"
+
+// passphraseIterations is the PBKDF2 work factor for new passphrase-encrypted
+// files. Existing files record theirs in the header.
+var passphraseIterations = 600_000
+
+// keyCheck is sealed into the header so a wrong key is detected up front
+// instead of every record failing to decrypt.
+var keyCheck = []byte("histui")
+
+// keyCheckSeq is the sequence number keyCheck is sealed with, so it cannot
+// stand in for a record.
+const keyCheckSeq = ^uint64(0)
+
+// HistoryKey is the secret the history file is encrypted with: an X25519
+// identity or a passphrase.
+type HistoryKey struct {
+	identity   *ecdh.PrivateKey
+	passphrase []byte
+
+	mu      sync.Mutex
+	derived map[string][]byte // File keys by header salt
+}
+
+// ParseHistoryKey parses a key as read from a key file, environment variable
+// or keyring command. Keys starting with HISTUI-X25519-KEY- are X25519
+// identities; anything else is a passphrase.
+func ParseHistoryKey(data []byte) (*HistoryKey, error) {
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return nil, errors.New("history key is empty")
+	}
+
+	if encoded, ok := strings.CutPrefix(s, identityPrefix); ok {
+		raw, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid X25519 identity: %w", err)
+		}
+		identity, err := ecdh.X25519().NewPrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid X25519 identity: %w", err)
+		}
+		return &HistoryKey{identity: identity}, nil
+	}
+
+	return &HistoryKey{passphrase: []byte(s)}, nil
+}
+
+// GenerateHistoryKey creates a new X25519 identity.
+func GenerateHistoryKey() (*HistoryKey, error) {
+	identity, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryKey{identity: identity}, nil
+}
+
+// String encodes an X25519 identity for a key file. Passphrases are not
+// encoded, so their keys return an empty string.
+func (k *HistoryKey) String() string {
+	if k.identity == nil {
+		return ""
+	}
+	return identityPrefix + base64.RawURLEncoding.EncodeToString(k.identity.Bytes())
+}
+
+// Scheme returns the encryption scheme the key uses for new files.
+func (k *HistoryKey) Scheme() string {
+	if k.identity != nil {
+		return SchemeX25519
+	}
+	return SchemePassphrase
+}
+
+// encryptionHeader describes how a history file is encrypted. It is stored
+// in the schema header; records are then base64-encoded AES-256-GCM
+// ciphertexts, one per line, so corruption stays confined to single records.
+// Each is sealed with its line number after the header, authenticated
+// together with the salt, so records cannot be moved between lines or files.
+// Records cut off the end of the file are not detected.
+type encryptionHeader struct {
+	Scheme     string `json:"scheme"`
+	Salt       string `json:"salt"`
+	Ephemeral  string `json:"ephemeral,omitempty"`  // X25519 ephemeral public key
+	Iterations int    `json:"iterations,omitempty"` // PBKDF2 work factor
+	Check      string `json:"check"`                // keyCheck sealed with the file key
+}
+
+// newEncryptionHeader creates the header and record cipher for a new file.
+func (k *HistoryKey) newEncryptionHeader() (*encryptionHeader, *recordCipher, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+
+	h := &encryptionHeader{
+		Scheme: k.Scheme(),
+		Salt:   base64.StdEncoding.EncodeToString(salt),
+	}
+	if k.identity != nil {
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		h.Ephemeral = base64.StdEncoding.EncodeToString(ephemeral.PublicKey().Bytes())
+	} else {
+		h.Iterations = passphraseIterations
+	}
+
+	c, err := k.fileCipher(h)
+	if err != nil {
+		return nil, nil, err
+	}
+	check, err := c.seal(keyCheck, keyCheckSeq)
+	if err != nil {
+		return nil, nil, err
+	}
+	h.Check = string(check)
+	return h, c, nil
+}
+
+// openEncryptionHeader returns the record cipher for an existing file,
+// verifying that the key matches.
+func (k *HistoryKey) openEncryptionHeader(h *encryptionHeader) (*recordCipher, error) {
+	if k == nil {
+		return nil, ErrHistoryEncrypted
+	}
+	if h.Scheme != k.Scheme() {
+		return nil, fmt.Errorf("%w (file uses %s, key is %s)", ErrWrongHistoryKey, h.Scheme, k.Scheme())
+	}
+
+	c, err := k.fileCipher(h)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := c.open([]byte(h.Check), keyCheckSeq); err != nil || !bytes.Equal(check, keyCheck) {
+		return nil, ErrWrongHistoryKey
+	}
+	return c, nil
+}
+
+// fileCipher derives the file key for a header. Derived keys are cached,
+// since passphrase derivation is deliberately slow.
+func (k *HistoryKey) fileCipher(h *encryptionHeader) (*recordCipher, error) {
+	salt, err := base64.StdEncoding.DecodeString(h.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption header: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	cacheKey := h.Salt + h.Ephemeral
+	fileKey, ok := k.derived[cacheKey]
+	if !ok {
+		switch h.Scheme {
+		case SchemeX25519:
+			raw, err := base64.StdEncoding.DecodeString(h.Ephemeral)
+			if err != nil {
+				return nil, fmt.Errorf("invalid encryption header: %w", err)
+			}
+			ephemeral, err := ecdh.X25519().NewPublicKey(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid encryption header: %w", err)
+			}
+			shared, err := k.identity.ECDH(ephemeral)
+			if err != nil {
+				return nil, err
+			}
+			fileKey, err = hkdf.Key(sha256.New, shared, append(salt, raw...), "histui history", 32)
+			if err != nil {
+				return nil, err
+			}
+		case SchemePassphrase:
+			if h.Iterations <= 0 {
+				return nil, errors.New("invalid encryption header: missing iterations")
+			}
+			fileKey, err = pbkdf2.Key(sha256.New, string(k.passphrase), salt, h.Iterations, 32)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported encryption scheme %q", h.Scheme)
+		}
+		if k.derived == nil {
+			k.derived = make(map[string][]byte)
+		}
+		k.derived[cacheKey] = fileKey
+	}
+
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &recordCipher{aead: aead, salt: salt}, nil
+}
+
+// recordCipher encrypts individual history records.
+type recordCipher struct {
+	aead cipher.AEAD
+	salt []byte // Header salt, authenticated with every record
+}
+
+// seqSize is the size of the sequence number sealed lines start with.
+const seqSize = 8
+
+// additionalData returns the data authenticated with the record numbered seq.
+func (c *recordCipher) additionalData(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(bytes.Clone(c.salt), seq)
+}
+
+// seal encrypts the record numbered seq into a single base64 line (without
+// the newline). The line holds seq in clear, authenticated with the salt, so
+// open can tell a moved record from a corrupt one.
+func (c *recordCipher) seal(plaintext []byte, seq uint64) ([]byte, error) {
+	prefix := c.aead.NonceSize() + seqSize
+	sealed := make([]byte, prefix, prefix+len(plaintext)+c.aead.Overhead())
+	nonce := sealed[:c.aead.NonceSize()]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint64(sealed[c.aead.NonceSize():], seq)
+	sealed = c.aead.Seal(sealed, nonce, plaintext, c.additionalData(seq))
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(out, sealed)
+	return out, nil
+}
+
+// open decrypts a line produced by seal. It returns ErrHistoryTampered if the
+// line is authentic but was sealed as a record other than seq.
+func (c *recordCipher) open(line []byte, seq uint64) ([]byte, error) {
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(sealed, line)
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[:n]
+	prefix := c.aead.NonceSize() + seqSize
+	if len(sealed) < prefix {
+		return nil, errors.New("record too short")
+	}
+	nonce := sealed[:c.aead.NonceSize()]
+	sealedSeq := binary.BigEndian.Uint64(sealed[c.aead.NonceSize():prefix])
+	plaintext, err := c.aead.Open(nil, nonce, sealed[prefix:], c.additionalData(sealedSeq))
+	if err != nil {
+		return nil, err
+	}
+	if sealedSeq != seq {
+		return nil, ErrHistoryTampered
+	}
+	return plaintext, nil
+}
+
+// FileEncryption returns the encryption scheme of the history file at path,
+// or an empty string if it is plaintext. If key is not nil it must be able
+// to decrypt the file.
+func FileEncryption(path string, key *HistoryKey) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	header := readHeader(file)
+	if header == nil || header.Encryption == nil {
+		return "", nil
+	}
+	if key != nil {
+		if _, err := key.openEncryptionHeader(header.Encryption); err != nil {
+			return header.Encryption.Scheme, err
+		}
+	}
+	return header.Encryption.Scheme, nil
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func init() {
+	// Keep passphrase tests fast
+	passphraseIterations = 1000
+}
+
+func TestParseHistoryKey(t *testing.T) {
+	key, err := GenerateHistoryKey()
+	require.NoError(t, err)
+	assert.Equal(t, SchemeX25519, key.Scheme())
+	assert.True(t, strings.HasPrefix(key.String(), identityPrefix))
+
+	parsed, err := ParseHistoryKey([]byte(key.String() + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, key.String(), parsed.String())
+
+	passphrase, err := ParseHistoryKey([]byte("correct horse battery staple\n"))
+	require.NoError(t, err)
+	assert.Equal(t, SchemePassphrase, passphrase.Scheme())
+	assert.Empty(t, passphrase.String())
+
+	_, err = ParseHistoryKey([]byte("  \n"))
+	assert.Error(t, err)
+	_, err = ParseHistoryKey([]byte(identityPrefix + "not-a-key"))
+	assert.Error(t, err)
+}
+
+func TestEncryptedPersistence(t *testing.T) {
+	identity, err := GenerateHistoryKey()
+	require.NoError(t, err)
+	passphrase, err := ParseHistoryKey([]byte("hunter2"))
+	require.NoError(t, err)
+
+	for _, key := range []*HistoryKey{identity, passphrase} {
+		t.Run(key.Scheme(), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.jsonl")
+
+			p, err := NewEncryptedJSONLPersistence(path, key)
+			require.NoError(t, err)
+			assert.True(t, p.Encrypted())
+			require.NoError(t, p.Append(persistTestNotification("enc1")))
+			require.NoError(t, p.AppendBatch(testNotifications("enc2", "enc3")))
+			require.NoError(t, p.Close())
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "Test Summary")
+			assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 4, "one line per record")
+
+			// Reading back with the key
+			p, err = NewEncryptedJSONLPersistence(path, key)
+			require.NoError(t, err)
+			ns, err := p.Load()
+			require.NoError(t, err)
+			assert.Len(t, ns, 3)
+
+			// Rewrite keeps the file encrypted
+			require.NoError(t, p.Rewrite(ns[:2]))
+			require.NoError(t, p.Append(persistTestNotification("enc4")))
+			ns, err = p.Load()
+			require.NoError(t, err)
+			assert.Len(t, ns, 3)
+
+			// So are logged ops
+			require.NoError(t, p.AppendOps([]Op{{Type: OpDelete, ID: "enc1"}}))
+			ns, err = p.Load()
+			require.NoError(t, err)
+			assert.Len(t, ns, 2)
+			require.NoError(t, p.Close())
+			content, err = os.ReadFile(path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "Test Summary")
+			assert.NotContains(t, string(content), `"op"`)
+
+			// Without a key nothing is read or written
+			p, err = NewJSONLPersistence(path)
+			require.NoError(t, err)
+			defer p.Close()
+			_, err = p.Load()
+			assert.ErrorIs(t, err, ErrHistoryEncrypted)
+			assert.ErrorIs(t, p.Append(persistTestNotification("plain")), ErrHistoryEncrypted)
+			assert.ErrorIs(t, p.Rewrite(nil), ErrHistoryEncrypted)
+
+			// Nor with the wrong key
+			other, err := ParseHistoryKey([]byte("wrong"))
+			require.NoError(t, err)
+			wrong, err := NewEncryptedJSONLPersistence(path, other)
+			require.NoError(t, err)
+			defer wrong.Close()
+			_, err = wrong.Load()
+			assert.ErrorIs(t, err, ErrWrongHistoryKey)
+		})
+	}
+}
+
+func TestEncryptedPersistence_RecordSequence(t *testing.T) {
+	key, err := GenerateHistoryKey()
+	require.NoError(t, err)
+
+	// Writers number their lines after those other processes appended
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	p1, err := NewEncryptedJSONLPersistence(path, key)
+	require.NoError(t, err)
+	defer p1.Close()
+	p2, err := NewEncryptedJSONLPersistence(path, key)
+	require.NoError(t, err)
+	defer p2.Close()
+	require.NoError(t, p1.AppendBatch(testNotifications("s1", "s2")))
+	_, err = p2.Load()
+	require.NoError(t, err)
+	require.NoError(t, p1.Append(persistTestNotification("s3")))
+	require.NoError(t, p2.AppendOps([]Op{{Type: OpDismiss, ID: "s1", At: 1}}))
+	require.NoError(t, p1.Append(persistTestNotification("s4")))
+	entries, reset, err := p2.LoadTail()
+	require.NoError(t, err)
+	assert.False(t, reset)
+	assert.Len(t, entries, 3)
+	ns, err := p1.Load()
+	require.NoError(t, err)
+	assert.Len(t, ns, 4)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	original := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, original, 6)
+
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+	}{
+		{"deleted_middle", func(lines []string) []string { return append(lines[:2], lines[3:]...) }},
+		{"reordered", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}},
+		{"replayed", func(lines []string) []string { return append(lines, lines[4]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.jsonl")
+			lines := tt.tamper(slices.Clone(original))
+			require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+			p, err := NewEncryptedJSONLPersistence(path, key)
+			require.NoError(t, err)
+			defer p.Close()
+			_, err = p.Load()
+			assert.ErrorIs(t, err, ErrHistoryTampered)
+		})
+	}
+
+	// Corrupt records are still skipped one by one
+	t.Run("corrupt", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history.jsonl")
+		lines := slices.Clone(original)
+		lines[2] = lines[2][:len(lines[2])/2]
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+		p, err := NewEncryptedJSONLPersistence(path, key)
+		require.NoError(t, err)
+		defer p.Close()
+		ns, err := p.Load()
+		require.NoError(t, err)
+		assert.Len(t, ns, 3)
+	})
+}
+
+func TestMigrateHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	key, err := GenerateHistoryKey()
+	require.NoError(t, err)
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendBatch(testNotifications("m1", "m2")))
+	require.NoError(t, p.Close())
+
+	// A key alone does not encrypt an existing plaintext file
+	p, err = NewEncryptedJSONLPersistence(path, key)
+	require.NoError(t, err)
+	assert.False(t, p.Encrypted())
+	require.NoError(t, p.Append(persistTestNotification("m3")))
+
+	count, err := MigrateHistory(path, nil, key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// Open persistence follows the migrated file
+	require.NoError(t, p.Append(persistTestNotification("m4")))
+	assert.True(t, p.Encrypted())
+	ns, err := p.Load()
+	require.NoError(t, err)
+	assert.Len(t, ns, 4)
+	require.NoError(t, p.Close())
+
+	count, err = MigrateHistory(path, key, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Test Summary m4")
+
+	_, err = MigrateHistory(path, nil, key)
+	require.NoError(t, err)
+	_, err = MigrateHistory(path, nil, nil)
+	assert.ErrorIs(t, err, ErrHistoryEncrypted)
+}
+
+func TestRecoverFromCorruption_Encrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	key, err := GenerateHistoryKey()
+	require.NoError(t, err)
+
+	p, err := NewEncryptedJSONLPersistence(path, key)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendBatch(testNotifications("r1", "r2", "r3")))
+	require.NoError(t, p.Close())
+
+	// Damage the second record
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(string(content), "\n")
+	lines[2] = lines[2][:len(lines[2])/2]
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+
+	_, err = RecoverFromCorruption(path, nil, true)
+	assert.ErrorIs(t, err, ErrHistoryEncrypted)
+
+	report, err := RecoverFromCorruption(path, key, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, []int{3}, report.CorruptLines)
+
+	p, err = NewEncryptedJSONLPersistence(path, key)
+	require.NoError(t, err)
+	defer p.Close()
+	assert.True(t, p.Encrypted())
+	ns, err := p.Load()
+	require.NoError(t, err)
+	assert.Len(t, ns, 2)
+}
+
+func testNotifications(ids ...string) []model.Notification {
+	ns := make([]model.Notification, len(ids))
+	for i, id := range ids {
+		ns[i] = persistTestNotification(id)
+	}
+	return ns
+}
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// OpType is the kind of mutation recorded in the history log.
+type OpType string
+
+// Mutations recorded in the history log.
+const (
+	OpDismiss   OpType = "dismiss"   // Dismissed at At (and seen, if it was not)
+	OpUndismiss OpType = "undismiss" // Dismissal cleared
+	OpSeen      OpType = "seen"      // Seen at At, if it was not already
+	OpTag       OpType = "tag"       // Tag added
+	OpUntag     OpType = "untag"     // Tag removed
+	OpUpdate    OpType = "update"    // Replaced by Notification
+	OpDelete    OpType = "delete"    // Removed
+)
+
+// Op is a mutation of a notification already in the history log. Ops are
+// appended after the records they change instead of rewriting the file, and
+// folded into the records when the log is compacted.
+//
+// Op lines have no histui_id, so releases that predate the log would skip
+// them as malformed records; a file holding ops is headed with
+// SchemaVersion, which they refuse to load.
+type Op struct {
+	Type         OpType              `json:"op"`
+	ID           string              `json:"id"`
+	At           int64               `json:"at,omitempty"`
+	Tag          string              `json:"tag,omitempty"`
+	Notification *model.Notification `json:"n,omitempty"`
+}
+
+// Apply applies the op to n. Delete ops are applied by removing n instead.
+func (op Op) Apply(n *model.Notification) {
+	switch op.Type {
+	case OpDismiss:
+		n.HistuiDismissedAt = op.At
+		if n.HistuiSeenAt == 0 {
+			n.HistuiSeenAt = op.At
+		}
+	case OpUndismiss:
+		n.Undismiss()
+	case OpSeen:
+		if n.HistuiSeenAt == 0 {
+			n.HistuiSeenAt = op.At
+		}
+	case OpTag:
+		n.AddTag(op.Tag)
+	case OpUntag:
+		n.RemoveTag(op.Tag)
+	case OpUpdate:
+		if op.Notification != nil {
+			*n = *op.Notification
+		}
+	}
+}
+
+// LogEntry is one line of the history log: either a notification record or
+// an op on an earlier one.
+type LogEntry struct {
+	Record *model.Notification
+	Op     *Op
+}
+
+// logReplay folds log entries into the notifications they describe.
+type logReplay struct {
+	notifications []model.Notification
+	index         map[string]int // histui_id -> position in notifications
+	records       int            // Record lines replayed
+	ops           int            // Op lines replayed
+}
+
+func newLogReplay() *logReplay {
+	return &logReplay{index: make(map[string]int)}
+}
+
+// apply replays one entry. The first record with an ID wins, as in
+// Store.Hydrate; a deleted notification may be recorded again.
+func (r *logReplay) apply(e LogEntry) {
+	if e.Record != nil {
+		r.records++
+		if _, exists := r.index[e.Record.HistuiID]; exists {
+			return
+		}
+		r.index[e.Record.HistuiID] = len(r.notifications)
+		r.notifications = append(r.notifications, *e.Record)
+		return
+	}
+
+	r.ops++
+	idx, exists := r.index[e.Op.ID]
+	if !exists {
+		return
+	}
+	if e.Op.Type == OpDelete {
+		// Cleared IDs are dropped by result
+		r.notifications[idx].HistuiID = ""
+		delete(r.index, e.Op.ID)
+		return
+	}
+	e.Op.Apply(&r.notifications[idx])
+}
+
+// result returns the replayed notifications in record order.
+func (r *logReplay) result() []model.Notification {
+	ns := make([]model.Notification, 0, len(r.index))
+	for _, n := range r.notifications {
+		if n.HistuiID != "" {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
+
+// encodeOp encodes an op as line seq after the header, encrypting it if c
+// is set.
+func encodeOp(c *recordCipher, seq int, op Op) ([]byte, error) {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		if data, err = c.seal(data, uint64(seq)); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeEntry decodes a record or op from line seq after the header.
+func decodeEntry(c *recordCipher, seq int, line []byte) (LogEntry, error) {
+	if c != nil {
+		data, err := c.open(line, uint64(seq))
+		if err != nil {
+			return LogEntry{}, err
+		}
+		line = data
+	}
+
+	var probe struct {
+		Op OpType `json:"op"`
+	}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return LogEntry{}, err
+	}
+	if probe.Op != "" {
+		var op Op
+		if err := json.Unmarshal(line, &op); err != nil {
+			return LogEntry{}, err
+		}
+		if op.ID == "" {
+			return LogEntry{}, errors.New("op missing id")
+		}
+		if op.Type == OpUpdate && (op.Notification == nil || op.Notification.HistuiID != op.ID) {
+			return LogEntry{}, errors.New("update op missing notification")
+		}
+		return LogEntry{Op: &op}, nil
+	}
+
+	n, err := decodeRecord(nil, 0, line)
+	if err != nil {
+		return LogEntry{}, err
+	}
+	return LogEntry{Record: &n}, nil
+}
+
+// lockHistory takes an exclusive lock on the history file's lock file,
+// serializing writers across processes so compaction never drops an append.
+// Returns the function releasing it.
+func lockHistory(path string) (func(), error) {
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() { _ = file.Close() }, nil
+}
@@ -0,0 +1,363 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+func TestStore_MutationsAppendOps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s := NewStore(p)
+	require.NoError(t, s.AddBatch(testNotifications("o1", "o2", "o3")))
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Dismiss("o1"))
+	require.NoError(t, s.Batch(func(tx *Tx) error {
+		tx.Tag("o2", "work")
+		tx.SetStarred("o2", true)
+		tx.MarkSeen("o2")
+		return nil
+	}))
+	require.NoError(t, s.Close())
+
+	// Existing records are untouched, under a header older releases
+	// refuse; one op per mutation follows them
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, records, _ := strings.Cut(string(before), "\n")
+	header, rest, _ := strings.Cut(string(after), "\n")
+	assert.Contains(t, header, `"histui_schema_version":2`)
+	require.True(t, strings.HasPrefix(rest, records))
+	ops := strings.Split(strings.TrimSpace(strings.TrimPrefix(rest, records)), "\n")
+	assert.Len(t, ops, 4)
+	assert.Contains(t, ops[0], `"op":"dismiss"`)
+	assert.Contains(t, ops[3], `"op":"seen"`)
+
+	// Replaying the log restores the state
+	p, err = NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s = NewStore(p)
+	defer s.Close()
+	require.NoError(t, s.Hydrate())
+
+	assert.Equal(t, 3, s.Count())
+	assert.True(t, s.GetByID("o1").IsDismissed())
+	assert.True(t, s.GetByID("o1").IsSeen())
+	o2 := s.GetByID("o2")
+	assert.Equal(t, []string{"work"}, o2.HistuiTags)
+	assert.True(t, o2.IsStarred())
+	assert.True(t, o2.IsSeen())
+}
+
+func TestStore_DeletesLeaveNoContentOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s := NewStore(p)
+	defer s.Close()
+	require.NoError(t, s.AddBatch(testNotifications("d1", "d2", "d3", "d4", "d5")))
+	require.NoError(t, s.Batch(func(tx *Tx) error {
+		tx.SetNote("d5", "call the bank")
+		return nil
+	}))
+
+	content := func() string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// Annotations only append ops
+	require.NoError(t, s.Batch(func(tx *Tx) error {
+		tx.SetStarred("d1", true)
+		return nil
+	}))
+	assert.Contains(t, content(), `"op":"update"`)
+
+	require.NoError(t, s.Delete("d1"))
+	assert.NotContains(t, content(), "Test Summary d1")
+	assert.NotContains(t, content(), `"op"`, "the log is compacted")
+
+	require.NoError(t, s.DeleteWithTombstone("d2"))
+	assert.NotContains(t, content(), "Test Summary d2")
+
+	require.NoError(t, s.Batch(func(tx *Tx) error {
+		tx.Delete("d3")
+		n := tx.Get("d4")
+		n.Summary = "[redacted]"
+		tx.Update(*n)
+		tx.SetNote("d5", "")
+		return nil
+	}))
+	data := content()
+	assert.NotContains(t, data, "Test Summary d3")
+	assert.NotContains(t, data, "Test Summary d4")
+	assert.NotContains(t, data, "call the bank")
+
+	ns, err := p.Load()
+	require.NoError(t, err)
+	assert.Len(t, ns, 2)
+}
+
+func TestJSONLPersistence_LoadTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	writer, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	defer writer.Close()
+	reader, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// The reader has not loaded the file yet
+	_, reset, err := reader.LoadTail()
+	require.NoError(t, err)
+	assert.True(t, reset)
+	_, err = reader.Load()
+	require.NoError(t, err)
+
+	// The first op replaces the file with one older releases refuse
+	require.NoError(t, writer.AppendOps([]Op{{Type: OpSeen, ID: "t0", At: 50}}))
+	_, reset, err = reader.LoadTail()
+	require.NoError(t, err)
+	assert.True(t, reset)
+	_, err = reader.Load()
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Append(persistTestNotification("t1")))
+	require.NoError(t, writer.AppendOps([]Op{{Type: OpDismiss, ID: "t1", At: 100}}))
+
+	entries, reset, err := reader.LoadTail()
+	require.NoError(t, err)
+	assert.False(t, reset)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "t1", entries[0].Record.HistuiID)
+	assert.Equal(t, Op{Type: OpDismiss, ID: "t1", At: 100}, *entries[1].Op)
+
+	// Nothing new, and a process's own writes are not read back
+	entries, _, err = reader.LoadTail()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	entries, reset, err = writer.LoadTail()
+	require.NoError(t, err)
+	assert.False(t, reset)
+	assert.Empty(t, entries)
+
+	// A line still being written is left for the next read
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(`{"op":"seen","id":"t1",`)
+	require.NoError(t, err)
+	entries, _, err = reader.LoadTail()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	_, err = file.WriteString(`"at":200}` + "\n")
+	require.NoError(t, err)
+	entries, _, err = reader.LoadTail()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, OpSeen, entries[0].Op.Type)
+
+	// Replacing the file forces a full load
+	require.NoError(t, writer.Rewrite(nil))
+	_, reset, err = reader.LoadTail()
+	require.NoError(t, err)
+	assert.True(t, reset)
+}
+
+func TestStore_ReloadAppliesTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p1, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s1 := NewStore(p1)
+	defer s1.Close()
+	require.NoError(t, s1.AddBatch(testNotifications("a", "b")))
+
+	p2, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s2 := NewStore(p2)
+	defer s2.Close()
+	require.NoError(t, s2.Hydrate())
+	require.NoError(t, s2.Add(persistTestNotification("c")))
+	require.NoError(t, s2.Batch(func(tx *Tx) error {
+		tx.Dismiss("a")
+		tx.Tag("c", "work")
+		tx.Delete("b")
+		return nil
+	}))
+
+	require.NoError(t, s1.Reload())
+	assert.Equal(t, 2, s1.Count())
+	assert.True(t, s1.GetByID("a").IsDismissed())
+	assert.Nil(t, s1.GetByID("b"))
+	assert.Equal(t, []string{"work"}, s1.GetByID("c").HistuiTags)
+
+	// Nothing new: no event
+	ch := s1.Subscribe()
+	require.NoError(t, s1.Reload())
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected event: %+v", event)
+	default:
+	}
+}
+
+func TestJSONLPersistence_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s := NewStore(p)
+	defer s.Close()
+	require.NoError(t, s.AddBatch(testNotifications("c1", "c2")))
+
+	follower, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	defer follower.Close()
+	_, err = follower.Load()
+	require.NoError(t, err)
+
+	for i := range compactMinOps - 1 {
+		require.NoError(t, s.Batch(func(tx *Tx) error {
+			tx.Tag("c1", fmt.Sprintf("tag%d", i))
+			return nil
+		}))
+	}
+	lines := func() int {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return len(strings.Split(strings.TrimSpace(string(content)), "\n"))
+	}
+	assert.Equal(t, 1+2+compactMinOps-1, lines())
+
+	// The next op compacts the log into a snapshot
+	require.NoError(t, s.Delete("c2"))
+	assert.Equal(t, 2, lines(), "header and one record")
+
+	// The writer keeps appending to the compacted file
+	require.NoError(t, s.Dismiss("c1"))
+	assert.Equal(t, 3, lines())
+
+	// Followers reload in full
+	_, reset, err := follower.LoadTail()
+	require.NoError(t, err)
+	assert.True(t, reset)
+	ns, err := follower.Load()
+	require.NoError(t, err)
+	require.Len(t, ns, 1)
+	assert.Len(t, ns[0].HistuiTags, compactMinOps-1)
+	assert.True(t, ns[0].IsDismissed())
+}
+
+func TestOpLog_BackwardCompatible(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	require.NoError(t, p.AppendBatch(testNotifications("old1", "old2")))
+
+	// Records alone stay readable by older releases
+	ns, err := loadV1(path)
+	require.NoError(t, err)
+	assert.Len(t, ns, 2)
+
+	// Ops would be skipped, bringing back what they deleted or dismissed,
+	// so older releases refuse the file instead
+	require.NoError(t, p.AppendOps([]Op{
+		{Type: OpDismiss, ID: "old1", At: 100},
+		{Type: OpDelete, ID: "old2"},
+	}))
+	_, err = loadV1(path)
+	assert.ErrorContains(t, err, "unsupported schema version 2")
+
+	ns, err = p.Load()
+	require.NoError(t, err)
+	require.Len(t, ns, 1)
+	assert.True(t, ns[0].IsDismissed())
+
+	// Compaction folds the ops in, and older releases can read it again
+	n := ns[0]
+	require.NoError(t, p.Rewrite(ns))
+	require.NoError(t, p.Close())
+	ns, err = loadV1(path)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Notification{n}, ns)
+}
+
+// loadV1 reads a history file the way releases before the op log did:
+// refusing newer schema versions and skipping lines without a histui_id.
+func loadV1(path string) ([]model.Notification, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ns []model.Notification
+	for i, line := range strings.Split(string(content), "\n") {
+		if line == "" {
+			continue
+		}
+		if i == 0 {
+			var header schemaHeader
+			if json.Unmarshal([]byte(line), &header) == nil {
+				if header.HistuiSchemaVersion > snapshotSchemaVersion {
+					return nil, fmt.Errorf("unsupported schema version %d (max: %d)",
+						header.HistuiSchemaVersion, snapshotSchemaVersion)
+				}
+				continue
+			}
+		}
+		var n model.Notification
+		if json.Unmarshal([]byte(line), &n) == nil && n.HistuiID != "" {
+			ns = append(ns, n)
+		}
+	}
+	return ns, nil
+}
+
+func TestOp_Apply(t *testing.T) {
+	n := model.Notification{HistuiID: "x"}
+
+	Op{Type: OpSeen, At: 50}.Apply(&n)
+	Op{Type: OpDismiss, At: 100}.Apply(&n)
+	assert.Equal(t, int64(50), n.HistuiSeenAt, "dismissal keeps the earlier seen time")
+	assert.Equal(t, int64(100), n.HistuiDismissedAt)
+
+	Op{Type: OpUndismiss}.Apply(&n)
+	assert.False(t, n.IsDismissed())
+
+	Op{Type: OpTag, Tag: "a"}.Apply(&n)
+	Op{Type: OpTag, Tag: "a"}.Apply(&n)
+	Op{Type: OpTag, Tag: "b"}.Apply(&n)
+	Op{Type: OpUntag, Tag: "a"}.Apply(&n)
+	assert.Equal(t, []string{"b"}, n.HistuiTags)
+
+	snoozed := n
+	snoozed.HistuiSnoozedUntil = time.Now().Add(time.Hour).Unix()
+	Op{Type: OpUpdate, Notification: &snoozed}.Apply(&n)
+	assert.True(t, n.IsSnoozed())
+}
+
+func mustOpen(t *testing.T, path string) *os.File {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+	return file
+}
@@ -2,20 +2,28 @@ package store
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/jmylchreest/histui/internal/model"
 )
 
-// SchemaVersion is the current persistence schema version.
-const SchemaVersion = 1
+// SchemaVersion is the current persistence schema version. Version 2
+// files are encrypted or hold ops, which older releases would misread;
+// plaintext snapshots are still written as version 1 so they can read them.
+const SchemaVersion = 2
+
+// snapshotSchemaVersion is the schema version of unencrypted files holding
+// only notification records.
+const snapshotSchemaVersion = 1
 
 // Persistence defines the interface for history storage.
 type Persistence interface {
@@ -28,7 +36,18 @@ type Persistence interface {
 	// AppendBatch adds multiple notifications efficiently.
 	AppendBatch(ns []model.Notification) error
 
-	// Rewrite replaces the entire storage file (used after prune).
+	// AppendOps records mutations of stored notifications.
+	AppendOps(ops []Op) error
+
+	// Compact rewrites storage as a snapshot of its notifications, so
+	// content that ops deleted or replaced no longer remains on disk.
+	Compact() error
+
+	// LoadTail returns the entries other processes appended since the last
+	// Load or LoadTail. reset is true if storage must be loaded in full.
+	LoadTail() (entries []LogEntry, reset bool, err error)
+
+	// Rewrite replaces the entire storage file with a snapshot.
 	Rewrite(ns []model.Notification) error
 
 	// Clear removes all stored notifications.
@@ -40,24 +59,67 @@ type Persistence interface {
 
 // schemaHeader is the first line of the JSONL file.
 type schemaHeader struct {
-	HistuiSchemaVersion int   `json:"histui_schema_version"`
-	CreatedAt           int64 `json:"created_at"`
+	HistuiSchemaVersion int               `json:"histui_schema_version"`
+	CreatedAt           int64             `json:"created_at"`
+	Encryption          *encryptionHeader `json:"encryption,omitempty"`
+}
+
+// newSchemaHeader creates the header for a snapshot written with the given
+// encryption (nil for plaintext).
+func newSchemaHeader(encryption *encryptionHeader) schemaHeader {
+	version := snapshotSchemaVersion
+	if encryption != nil {
+		version = SchemaVersion
+	}
+	return schemaHeader{
+		HistuiSchemaVersion: version,
+		CreatedAt:           time.Now().Unix(),
+		Encryption:          encryption,
+	}
 }
 
 // JSONLPersistence implements Persistence using JSONL files.
+//
+// The file is an append-only log: notification records, then ops that
+// mutate them. Once ops outnumber records the log is compacted into a fresh
+// snapshot, as it is by Compact when the store deletes or replaces content. Writers serialize on an exclusive lock on <path>.lock; readers
+// follow the log by offset and reload in full when the file is replaced.
+//
+// With a key, new files are encrypted and encrypted files can be read.
+// An existing file keeps its format until it is migrated with
+// MigrateHistory, so a key alone never rewrites a plaintext history.
 type JSONLPersistence struct {
 	mu     sync.RWMutex
 	path   string
 	file   *os.File
 	closed bool
+
+	key        *HistoryKey
+	version    int               // Current file's schema version; 0 if it has no header
+	encryption *encryptionHeader // Current file's encryption; nil if plaintext
+	cipher     *recordCipher     // Record cipher for an encrypted file
+	cipherErr  error             // Why an encrypted file cannot be read or written
+
+	offset  int64 // End of the log as last read; -1 if the file must be read in full
+	lines   int   // Lines after the header up to offset, which number encrypted records
+	records int   // Record lines up to offset
+	ops     int   // Op lines up to offset
 }
 
 // NewJSONLPersistence creates a new JSONLPersistence.
 // Creates the file if it doesn't exist.
 func NewJSONLPersistence(path string) (*JSONLPersistence, error) {
+	return NewEncryptedJSONLPersistence(path, nil)
+}
+
+// NewEncryptedJSONLPersistence creates a JSONLPersistence that encrypts new
+// files with key and reads files encrypted with it. A nil key behaves like
+// NewJSONLPersistence. A key mismatch is reported by Load and writes rather
+// than here, so commands that repair the configuration still work.
+func NewEncryptedJSONLPersistence(path string, key *HistoryKey) (*JSONLPersistence, error) {
 	// Ensure parent directory exists
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(dir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
@@ -68,8 +130,10 @@ func NewJSONLPersistence(path string) (*JSONLPersistence, error) {
 	}
 
 	p := &JSONLPersistence{
-		path: path,
-		file: file,
+		path:   path,
+		file:   file,
+		key:    key,
+		offset: -1,
 	}
 
 	// Check if file is empty and write header
@@ -79,36 +143,160 @@ func NewJSONLPersistence(path string) (*JSONLPersistence, error) {
 		return nil, err
 	}
 
+	// History written by older releases may be readable by other users
+	if info.Mode().Perm()&0077 != 0 {
+		_ = file.Chmod(0600)
+	}
+
 	if info.Size() == 0 {
 		if err := p.writeHeader(); err != nil {
 			_ = file.Close()
 			return nil, err
 		}
+	} else {
+		p.useHeader(readHeader(file))
 	}
 
 	return p, nil
 }
 
-// writeHeader writes the schema version header to the file.
+// readHeader reads the schema header from the start of a file without
+// moving its offset. Returns nil if the file has no header.
+func readHeader(file *os.File) *schemaHeader {
+	line, err := bufio.NewReader(io.NewSectionReader(file, 0, 1<<20)).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil
+	}
+	var header schemaHeader
+	if json.Unmarshal(line, &header) != nil || header.HistuiSchemaVersion == 0 {
+		return nil
+	}
+	return &header
+}
+
+// useHeader sets up record encoding for the file described by header.
+// Caller must hold the lock.
+func (p *JSONLPersistence) useHeader(header *schemaHeader) {
+	p.version, p.encryption, p.cipher, p.cipherErr = 0, nil, nil, nil
+	if header == nil {
+		return
+	}
+	p.version = header.HistuiSchemaVersion
+	if header.Encryption == nil {
+		return
+	}
+	p.encryption = header.Encryption
+	p.cipher, p.cipherErr = p.key.openEncryptionHeader(header.Encryption)
+}
+
+// Encrypted reports whether the current history file is encrypted.
+func (p *JSONLPersistence) Encrypted() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.encryption != nil
+}
+
+// writable returns an error if records cannot be encoded for the current
+// file. Caller must hold the lock.
+func (p *JSONLPersistence) writable() error {
+	if p.encryption != nil && p.cipher == nil {
+		return p.cipherErr
+	}
+	return nil
+}
+
+// encodeRecord encodes a notification as line seq after the header,
+// encrypting it if c is set.
+func encodeRecord(c *recordCipher, seq int, n model.Notification) ([]byte, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		if data, err = c.seal(data, uint64(seq)); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeRecord decodes line seq after the header, written by encodeRecord.
+func decodeRecord(c *recordCipher, seq int, line []byte) (model.Notification, error) {
+	var n model.Notification
+	if c != nil {
+		data, err := c.open(line, uint64(seq))
+		if err != nil {
+			return n, err
+		}
+		line = data
+	}
+	if err := json.Unmarshal(line, &n); err != nil {
+		return n, err
+	}
+	if n.HistuiID == "" {
+		return n, errors.New("missing histui_id")
+	}
+	return n, nil
+}
+
+// reopenIfReplaced reopens the file when another process has replaced it,
+// as Rewrite does, so reads and appends reach the current file instead of
+// the unlinked one. Caller must hold the lock.
+func (p *JSONLPersistence) reopenIfReplaced() error {
+	current, err := os.Stat(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if open, err := p.file.Stat(); err == nil && os.SameFile(current, open) {
+		return nil
+	}
+
+	file, err := os.OpenFile(p.path, os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen file %s: %w", p.path, err)
+	}
+	_ = p.file.Close()
+	p.file = file
+	p.useHeader(readHeader(file))
+	p.offset = -1
+	return nil
+}
+
+// writeHeader writes the schema version header to a new file, encrypting
+// the file if a key is configured. Caller must hold the lock.
 func (p *JSONLPersistence) writeHeader() error {
-	header := schemaHeader{
-		HistuiSchemaVersion: SchemaVersion,
-		CreatedAt:           time.Now().Unix(),
+	var encryption *encryptionHeader
+	var c *recordCipher
+	if p.key != nil {
+		var err error
+		if encryption, c, err = p.key.newEncryptionHeader(); err != nil {
+			return fmt.Errorf("failed to set up encryption: %w", err)
+		}
 	}
 
+	header := newSchemaHeader(encryption)
 	data, err := json.Marshal(header)
 	if err != nil {
 		return err
 	}
 
-	_, err = p.file.Write(append(data, '\n'))
-	return err
+	if _, err := p.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	p.version = header.HistuiSchemaVersion
+	p.encryption, p.cipher, p.cipherErr = encryption, c, nil
+	p.offset, p.lines, p.records, p.ops = int64(len(data)+1), 0, 0, 0
+	return nil
 }
 
 // ErrPersistenceClosed is returned when operations are attempted on a closed persistence.
 var ErrPersistenceClosed = errors.New("persistence is closed")
 
-// Load reads all notifications from storage.
+// Load reads all notifications from storage, replaying the ops logged
+// after them.
 func (p *JSONLPersistence) Load() ([]model.Notification, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -117,115 +305,376 @@ func (p *JSONLPersistence) Load() ([]model.Notification, error) {
 		return nil, ErrPersistenceClosed
 	}
 
-	// Seek to beginning
-	if _, err := p.file.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("seek %s: %w", p.path, err)
+	if err := p.reopenIfReplaced(); err != nil {
+		return nil, err
 	}
 
-	var notifications []model.Notification
-	scanner := bufio.NewScanner(p.file)
+	entries, end, lines, err := p.readLog(0, 0, true)
+	if err != nil {
+		return nil, err
+	}
 
-	// Increase buffer size for potentially long lines
-	const maxLineSize = 1024 * 1024 // 1MB
-	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	replay := newLogReplay()
+	for _, e := range entries {
+		replay.apply(e)
+	}
+	p.offset, p.lines, p.records, p.ops = end, lines, replay.records, replay.ops
 
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Bytes()
+	return replay.result(), nil
+}
 
-		if len(line) == 0 {
-			continue
+// LoadTail returns the entries appended by other processes since the last
+// Load or LoadTail. reset is true if the file was replaced since, e.g. by
+// compaction, and must be loaded again in full.
+func (p *JSONLPersistence) LoadTail() (entries []LogEntry, reset bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || p.file == nil {
+		return nil, false, ErrPersistenceClosed
+	}
+
+	if err := p.reopenIfReplaced(); err != nil {
+		return nil, false, err
+	}
+	if p.offset <= 0 {
+		return nil, true, nil
+	}
+	info, err := p.file.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if info.Size() < p.offset {
+		// Truncated in place
+		return nil, true, nil
+	}
+	if err := p.writable(); err != nil {
+		return nil, false, err
+	}
+
+	entries, end, lines, err := p.readLog(p.offset, p.lines, false)
+	p.offset, p.lines = end, lines
+	for _, e := range entries {
+		if e.Record != nil {
+			p.records++
+		} else {
+			p.ops++
 		}
+	}
+	return entries, false, err
+}
 
-		// First line is the header
-		if lineNum == 1 {
+// readLog reads the entries from offset from, which starts line seq after
+// the header, to the end of the file. Only complete lines are read unless
+// partial is set, so a line another process is still writing is left for
+// the next read; end is the offset after the last line read and next the
+// number of the line there. Reading from the start also reads the header.
+// Caller must hold the lock.
+func (p *JSONLPersistence) readLog(from int64, seq int, partial bool) (entries []LogEntry, end int64, next int, err error) {
+	info, err := p.file.Stat()
+	if err != nil {
+		return nil, from, seq, err
+	}
+	reader := bufio.NewReader(io.NewSectionReader(p.file, from, info.Size()-from))
+
+	end, next = from, seq
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return entries, end, next, fmt.Errorf("error reading file: %w", err)
+		}
+		complete := err == nil
+		if !complete && (!partial || len(line) == 0) {
+			break
+		}
+
+		lineStart := end
+		if complete {
+			end += int64(len(line))
+		}
+		line = bytes.TrimSpace(line)
+		numbered := true
+
+		// First line is the header, unless the file predates it
+		if lineStart == 0 && len(line) > 0 {
 			var header schemaHeader
-			if err := json.Unmarshal(line, &header); err != nil {
-				// Not a valid header, might be legacy format
-				// Try parsing as notification
-				var n model.Notification
-				if err := json.Unmarshal(line, &n); err == nil && n.HistuiID != "" {
-					notifications = append(notifications, n)
+			if json.Unmarshal(line, &header) == nil && header.HistuiSchemaVersion > 0 {
+				if header.HistuiSchemaVersion > SchemaVersion {
+					return nil, from, seq, fmt.Errorf("unsupported schema version %d (max: %d)",
+						header.HistuiSchemaVersion, SchemaVersion)
+				}
+				p.useHeader(&header)
+				if err := p.writable(); err != nil {
+					return nil, from, seq, err
 				}
-				continue
+				line, numbered = nil, false
+			} else {
+				p.useHeader(nil)
 			}
+		}
 
-			if header.HistuiSchemaVersion > SchemaVersion {
-				return nil, fmt.Errorf("unsupported schema version %d (max: %d)",
-					header.HistuiSchemaVersion, SchemaVersion)
+		// Skip malformed lines, but not records moved from other lines
+		if len(line) > 0 {
+			entry, err := decodeEntry(p.cipher, next, line)
+			if errors.Is(err, ErrHistoryTampered) {
+				// Encrypted files always have a header
+				return nil, from, seq, fmt.Errorf("%w at line %d", err, next+2)
+			}
+			if err == nil {
+				entries = append(entries, entry)
 			}
-			continue
 		}
 
-		// Parse notification
-		var n model.Notification
-		if err := json.Unmarshal(line, &n); err != nil {
-			// Log and skip malformed lines
-			continue
+		if !complete {
+			break
 		}
-
-		if n.HistuiID != "" {
-			notifications = append(notifications, n)
+		if numbered {
+			next++
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return notifications, fmt.Errorf("error reading file: %w", err)
-	}
-
-	// Seek back to end for appending
-	if _, err := p.file.Seek(0, io.SeekEnd); err != nil {
-		return notifications, err
-	}
-
-	return notifications, nil
+	return entries, end, next, nil
 }
 
 // Append adds a notification to storage.
 func (p *JSONLPersistence) Append(n model.Notification) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	return p.write([]model.Notification{n}, nil)
+}
+
+// AppendBatch adds multiple notifications efficiently.
+func (p *JSONLPersistence) AppendBatch(ns []model.Notification) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.write(ns, nil)
+}
+
+// AppendOps records mutations of stored notifications. The log is compacted
+// once it holds at least compactMinOps ops and more ops than records.
+func (p *JSONLPersistence) AppendOps(ops []Op) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.write(nil, ops)
+}
+
+// Compact replaces the log with a snapshot of its replayed records,
+// dropping the ops and whatever they deleted or replaced.
+func (p *JSONLPersistence) Compact() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
 	if p.closed || p.file == nil {
 		return ErrPersistenceClosed
 	}
 
-	data, err := json.Marshal(n)
+	unlock, err := lockHistory(p.path)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	_, err = p.file.Write(append(data, '\n'))
-	if err != nil {
+	if err := p.reopenIfReplaced(); err != nil {
 		return err
 	}
-
-	return p.file.Sync()
+	if err := p.writable(); err != nil {
+		return err
+	}
+	return p.compact()
 }
 
-// AppendBatch adds multiple notifications efficiently.
-func (p *JSONLPersistence) AppendBatch(ns []model.Notification) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// compactMinOps is the number of logged ops below which the log is never
+// compacted, so small histories are not rewritten every few changes.
+const compactMinOps = 256
 
+// write appends records and ops under the history lock.
+// Caller must hold the lock.
+func (p *JSONLPersistence) write(ns []model.Notification, ops []Op) error {
 	if p.closed || p.file == nil {
 		return ErrPersistenceClosed
 	}
 
-	for _, n := range ns {
-		data, err := json.Marshal(n)
+	unlock, err := lockHistory(p.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := p.reopenIfReplaced(); err != nil {
+		return err
+	}
+	if err := p.writable(); err != nil {
+		return err
+	}
+
+	info, err := p.file.Stat()
+	if err != nil {
+		return err
+	}
+	seq, err := p.nextLine(info.Size())
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for i, n := range ns {
+		data, err := encodeRecord(p.cipher, seq+i, n)
 		if err != nil {
 			return err
 		}
-		if _, err := p.file.Write(append(data, '\n')); err != nil {
+		buf.Write(data)
+	}
+	for i, op := range ops {
+		data, err := encodeOp(p.cipher, seq+len(ns)+i, op)
+		if err != nil {
 			return err
 		}
+		buf.Write(data)
 	}
-	return p.file.Sync()
+
+	end := info.Size() + int64(buf.Len())
+	upgrade := len(ops) > 0 && p.version < SchemaVersion
+	if upgrade {
+		if end, err = p.upgradeForOps(buf.Bytes()); err != nil {
+			return err
+		}
+	} else {
+		if _, err := p.file.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		if err := p.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	// Our own lines need not be read back, unless lines from another
+	// process precede them
+	if p.offset == info.Size() {
+		p.offset = end
+		p.lines += len(ns) + len(ops)
+		p.records += len(ns)
+		p.ops += len(ops)
+	} else if upgrade {
+		// Lines moved with the new header
+		p.offset = -1
+	}
+
+	if p.ops >= compactMinOps && p.ops >= p.records {
+		return p.compact()
+	}
+	return nil
+}
+
+// nextLine returns the number of the next line appended to a file of size
+// bytes, counting the complete lines after the header. Only encrypted
+// records are numbered, so plaintext files are not read. Caller must hold
+// the lock and the history lock.
+func (p *JSONLPersistence) nextLine(size int64) (int, error) {
+	if p.cipher == nil || p.offset == size {
+		return p.lines, nil
+	}
+
+	from, seq := p.offset, p.lines
+	if from <= 0 {
+		// Encrypted files always have a header
+		from, seq = 0, -1
+	}
+	data, err := io.ReadAll(io.NewSectionReader(p.file, from, size-from))
+	if err != nil {
+		return 0, fmt.Errorf("error reading file: %w", err)
+	}
+	return seq + bytes.Count(data, []byte{'\n'}), nil
+}
+
+// upgradeForOps replaces a version 1 file with a copy headed with the
+// current version, followed by lines, before the file's first ops. Older
+// releases skip op lines as malformed records, so they must refuse the file
+// rather than bring back what the ops deleted or dismissed. Compaction
+// writes version 1 again. Returns the new file's size. Caller must hold the
+// lock and the history lock.
+func (p *JSONLPersistence) upgradeForOps(lines []byte) (int64, error) {
+	data, err := io.ReadAll(io.NewSectionReader(p.file, 0, 1<<62))
+	if err != nil {
+		return 0, fmt.Errorf("error reading file: %w", err)
+	}
+	if readHeader(p.file) != nil {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		} else {
+			data = nil
+		}
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+
+	header := newSchemaHeader(p.encryption)
+	header.HistuiSchemaVersion = SchemaVersion
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return 0, err
+	}
+
+	tmpPath := p.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create new file: %w", err)
+	}
+	content := slices.Concat(headerData, []byte{'\n'}, data, lines)
+	if _, err := file.Write(content); err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, p.path)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to replace %s: %w", p.path, err)
+	}
+
+	_ = p.file.Close()
+	p.file = file
+	p.version = SchemaVersion
+	return int64(len(content)), nil
 }
 
-// Rewrite replaces the entire storage file (used after prune/delete).
+// compact replaces the log with its replayed records, dropping the ops and
+// the records they deleted. Caller must hold the lock and the history lock.
+func (p *JSONLPersistence) compact() error {
+	entries, end, _, err := p.readLog(0, 0, false)
+	if err != nil {
+		return err
+	}
+	replay := newLogReplay()
+	for _, e := range entries {
+		replay.apply(e)
+	}
+	ns := replay.result()
+
+	header := newSchemaHeader(p.encryption)
+	file, err := replaceFile(p.path, header, p.cipher, ns)
+	if err != nil {
+		return err
+	}
+	_ = p.file.Close()
+	p.file = file
+	p.version = header.HistuiSchemaVersion
+
+	// Lines this process had not read yet are now folded into the
+	// snapshot, so it can only pick up where it left off if there were none
+	caughtUp := p.offset == end
+	p.offset = -1
+	if caughtUp {
+		if info, err := file.Stat(); err == nil {
+			p.offset = info.Size()
+		}
+	}
+	p.lines, p.records, p.ops = len(ns), len(ns), 0
+	return nil
+}
+
+// Rewrite replaces the entire storage file with a snapshot of ns.
 func (p *JSONLPersistence) Rewrite(ns []model.Notification) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -234,53 +683,93 @@ func (p *JSONLPersistence) Rewrite(ns []model.Notification) error {
 		return ErrPersistenceClosed
 	}
 
-	// Close current file
+	unlock, err := lockHistory(p.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Keep the format of the file on disk, even if another process
+	// has encrypted or decrypted it since it was opened
 	if p.file != nil {
-		if err := p.file.Close(); err != nil {
+		if err := p.reopenIfReplaced(); err != nil {
 			return err
 		}
-		p.file = nil
 	}
-
-	// Create backup
-	backupPath := p.path + ".bak"
-	if err := os.Rename(p.path, backupPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to create backup: %w", err)
+	if err := p.writable(); err != nil {
+		return err
 	}
 
-	// Create new file
-	file, err := os.OpenFile(p.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	header := newSchemaHeader(p.encryption)
+	file, err := replaceFile(p.path, header, p.cipher, ns)
 	if err != nil {
-		// Try to restore backup
-		_ = os.Rename(backupPath, p.path)
-		return fmt.Errorf("failed to create new file: %w", err)
+		return err
+	}
+
+	if p.file != nil {
+		_ = p.file.Close()
 	}
 	p.file = file
+	p.version = header.HistuiSchemaVersion
 
-	// Write header
-	if err := p.writeHeader(); err != nil {
+	p.offset = -1
+	if info, err := file.Stat(); err == nil {
+		p.offset = info.Size()
+	}
+	p.lines, p.records, p.ops = len(ns), len(ns), 0
+
+	return nil
+}
+
+// replaceFile writes a header and the notifications to a temporary file and
+// renames it over path, so other processes reloading the history never see
+// a partial file. Returns the new file, open for appending.
+func replaceFile(path string, header schemaHeader, c *recordCipher, ns []model.Notification) (*os.File, error) {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new file: %w", err)
+	}
+
+	if err := writeNotifications(file, header, c, ns); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// writeNotifications writes a header and the notifications to a new file.
+func writeNotifications(file *os.File, header schemaHeader, c *recordCipher, ns []model.Notification) error {
+	data, err := json.Marshal(header)
+	if err != nil {
 		return err
 	}
 
-	// Write all notifications
-	for _, n := range ns {
-		data, err := json.Marshal(n)
+	w := bufio.NewWriter(file)
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	for i, n := range ns {
+		data, err := encodeRecord(c, i, n)
 		if err != nil {
 			return err
 		}
-		if _, err := p.file.Write(append(data, '\n')); err != nil {
+		if _, err := w.Write(data); err != nil {
 			return err
 		}
 	}
-
-	if err := p.file.Sync(); err != nil {
+	if err := w.Flush(); err != nil {
 		return err
 	}
 
-	// Remove backup on success
-	_ = os.Remove(backupPath)
-
-	return nil
+	return file.Sync()
 }
 
 // Clear removes all stored notifications.
@@ -292,6 +781,12 @@ func (p *JSONLPersistence) Clear() error {
 		return ErrPersistenceClosed
 	}
 
+	unlock, err := lockHistory(p.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Create backup
 	backupPath := p.path + ".bak"
 	if p.file != nil {
@@ -338,51 +833,149 @@ func (p *JSONLPersistence) Close() error {
 	return nil
 }
 
+// RecoveryReport describes the result of scanning a history file.
+type RecoveryReport struct {
+	Valid        int    // Notifications recovered, after replaying ops
+	Corrupt      int    // Lines that could not be parsed
+	CorruptLines []int  // Line numbers of the first corrupt lines
+	BackupPath   string // Backup of the original file (empty on dry run)
+}
+
+// maxReportedCorruptLines limits RecoveryReport.CorruptLines.
+const maxReportedCorruptLines = 10
+
 // RecoverFromCorruption attempts to recover from a corrupted file.
-// It creates a backup and rewrites only valid notifications.
-func RecoverFromCorruption(path string) error {
+// It creates a backup and rewrites only valid notifications, with the ops
+// that parsed applied. With dryRun,
+// the file is only scanned and the report describes what would be kept.
+// Encrypted files are recovered record by record and stay encrypted; key
+// must be the key they were encrypted with.
+func RecoverFromCorruption(path string, key *HistoryKey, dryRun bool) (*RecoveryReport, error) {
+	if !dryRun {
+		unlock, err := lockHistory(path)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
 	// Read file and collect valid notifications
 	file, err := os.Open(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var valid []model.Notification
+	header := readHeader(file)
+	first := 2 // Line number of the first record
+	if header == nil {
+		h := newSchemaHeader(nil)
+		header, first = &h, 1
+	}
+	var c *recordCipher
+	if header.Encryption != nil {
+		if c, err = key.openEncryptionHeader(header.Encryption); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+
+	report := &RecoveryReport{}
+	replay := newLogReplay()
 	scanner := bufio.NewScanner(file)
 	const maxLineSize = 1024 * 1024
 	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
 
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
 		line := scanner.Bytes()
 		if len(line) == 0 {
 			continue
 		}
 
 		// Skip header lines
-		var header schemaHeader
-		if json.Unmarshal(line, &header) == nil && header.HistuiSchemaVersion > 0 {
+		var h schemaHeader
+		if json.Unmarshal(line, &h) == nil && h.HistuiSchemaVersion > 0 {
 			continue
 		}
 
-		var n model.Notification
-		if err := json.Unmarshal(line, &n); err == nil && n.HistuiID != "" {
-			valid = append(valid, n)
+		if entry, err := decodeEntry(c, lineNum-first, line); err == nil {
+			replay.apply(entry)
+			continue
+		}
+		report.Corrupt++
+		if len(report.CorruptLines) < maxReportedCorruptLines {
+			report.CorruptLines = append(report.CorruptLines, lineNum)
 		}
 	}
+	scanErr := scanner.Err()
 	_ = file.Close()
+	if scanErr != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, scanErr)
+	}
+	valid := replay.result()
+	report.Valid = len(valid)
+
+	if dryRun {
+		return report, nil
+	}
 
 	// Create backup
 	backupPath := path + ".corrupted." + time.Now().Format("20060102-150405")
 	if err := os.Rename(path, backupPath); err != nil {
-		return fmt.Errorf("failed to backup corrupted file: %w", err)
+		return nil, fmt.Errorf("failed to backup corrupted file: %w", err)
 	}
+	report.BackupPath = backupPath
 
-	// Create new persistence and write valid notifications
-	p, err := NewJSONLPersistence(path)
+	// Write valid notifications in the original format, as a snapshot
+	header.HistuiSchemaVersion = newSchemaHeader(header.Encryption).HistuiSchemaVersion
+	header.CreatedAt = time.Now().Unix()
+	newFile, err := replaceFile(path, *header, c, valid)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := newFile.Close(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// MigrateHistory rewrites the history file at path encrypted with to, or as
+// plaintext if to is nil. from is the key the file is currently encrypted
+// with, if any. Returns the number of notifications migrated. Corrupt
+// records are dropped, so run RecoverFromCorruption first to report them.
+func MigrateHistory(path string, from, to *HistoryKey) (int, error) {
+	p, err := NewEncryptedJSONLPersistence(path, from)
+	if err != nil {
+		return 0, err
+	}
+	unlock, err := lockHistory(path)
+	if err != nil {
+		_ = p.Close()
+		return 0, err
 	}
-	defer func() { _ = p.Close() }()
+	defer unlock()
 
-	return p.AppendBatch(valid)
+	ns, err := p.Load()
+	_ = p.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	var encryption *encryptionHeader
+	var c *recordCipher
+	if to != nil {
+		if encryption, c, err = to.newEncryptionHeader(); err != nil {
+			return 0, fmt.Errorf("failed to set up encryption: %w", err)
+		}
+	}
+
+	file, err := replaceFile(path, newSchemaHeader(encryption), c, ns)
+	if err != nil {
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	return len(ns), nil
 }
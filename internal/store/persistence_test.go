@@ -174,6 +174,36 @@ func TestJSONLPersistence_LoadWithReopenedFile(t *testing.T) {
 	assert.Len(t, notifications, 2)
 }
 
+func TestJSONLPersistence_FollowsReplacedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.jsonl")
+
+	// Two handles on the same file, as histuid and the histui CLI have
+	p1, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	defer p1.Close()
+	require.NoError(t, p1.Append(persistTestNotification("shared1")))
+
+	p2, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	defer p2.Close()
+
+	rewritten := persistTestNotification("shared1")
+	rewritten.HistuiDismissedAt = time.Now().Unix()
+	require.NoError(t, p2.Rewrite([]model.Notification{rewritten}))
+
+	notifications, err := p1.Load()
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.True(t, notifications[0].IsDismissed(), "load should read the replaced file")
+
+	// Appends must land in the current file, not the unlinked one
+	require.NoError(t, p1.Append(persistTestNotification("shared2")))
+	notifications, err = p2.Load()
+	require.NoError(t, err)
+	assert.Len(t, notifications, 2)
+}
+
 func TestJSONLPersistence_FilePermissions(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "test.jsonl")
@@ -189,6 +219,20 @@ func TestJSONLPersistence_FilePermissions(t *testing.T) {
 	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
 }
 
+func TestJSONLPersistence_TightensPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"histui_schema_version":1,"created_at":1703577600}`+"\n"), 0644))
+	require.NoError(t, os.Chmod(path, 0644))
+
+	p, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	p.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
 func TestJSONLPersistence_SkipsMalformedLines(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "test.jsonl")
@@ -273,9 +317,20 @@ corrupt line that will break things
 	err := os.WriteFile(path, []byte(content), 0600)
 	require.NoError(t, err)
 
+	// Dry run reports without touching the file
+	report, err := RecoverFromCorruption(path, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 1, report.Corrupt)
+	assert.Equal(t, []int{3}, report.CorruptLines)
+	assert.Empty(t, report.BackupPath)
+	matches, _ := filepath.Glob(path + ".corrupted.*")
+	assert.Empty(t, matches)
+
 	// Recover
-	err = RecoverFromCorruption(path)
+	report, err = RecoverFromCorruption(path, nil, false)
 	require.NoError(t, err)
+	assert.NotEmpty(t, report.BackupPath)
 
 	// Verify recovered file
 	p, err := NewJSONLPersistence(path)
@@ -287,7 +342,7 @@ corrupt line that will break things
 	assert.Len(t, notifications, 2)
 
 	// Backup should exist
-	matches, _ := filepath.Glob(path + ".corrupted.*")
+	matches, _ = filepath.Glob(path + ".corrupted.*")
 	assert.Len(t, matches, 1)
 }
 
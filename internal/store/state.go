@@ -61,6 +61,7 @@ type SharedState struct {
 	DnDEnabled   bool   `json:"dnd_enabled"`
 	DnDEnabledAt int64  `json:"dnd_enabled_at,omitempty"` // Unix timestamp (legacy, kept for compatibility)
 	DnDEnabledBy string `json:"dnd_enabled_by,omitempty"` // Legacy field, kept for compatibility
+	DnDUntil     int64  `json:"dnd_until,omitempty"`      // When DnD ends by itself (0 = when turned off)
 
 	// Enhanced DnD tracking
 	DnDLastTransition *DnDTransition `json:"dnd_last_transition,omitempty"` // Details of the last DnD state change
@@ -174,6 +175,7 @@ func SaveSharedState(state *SharedState) error {
 //   - ruleName: name of the rule if trigger is DnDTriggerRule, empty otherwise
 func (s *SharedState) SetDnD(enabled bool, trigger DnDTrigger, reason, source, ruleName string) {
 	s.DnDEnabled = enabled
+	s.DnDUntil = 0
 	now := time.Now().Unix()
 
 	// Update compatibility fields
@@ -195,6 +197,19 @@ func (s *SharedState) SetDnD(enabled bool, trigger DnDTrigger, reason, source, r
 	}
 }
 
+// SetDnDUntil enables Do Not Disturb until the given time, with the same
+// transition tracking as SetDnD.
+func (s *SharedState) SetDnDUntil(until time.Time, trigger DnDTrigger, reason, source, ruleName string) {
+	s.SetDnD(true, trigger, reason, source, ruleName)
+	s.DnDUntil = until.Unix()
+}
+
+// IsDnDActive returns true if Do Not Disturb is enabled at now. DnD enabled
+// for a while is inactive once its end time passes, without a state change.
+func (s *SharedState) IsDnDActive(now time.Time) bool {
+	return s.DnDEnabled && (s.DnDUntil == 0 || now.Unix() < s.DnDUntil)
+}
+
 // ToggleDnD toggles the Do Not Disturb state with full transition tracking.
 // Parameters:
 //   - trigger: what type of event triggered this change
@@ -204,10 +219,31 @@ func (s *SharedState) SetDnD(enabled bool, trigger DnDTrigger, reason, source, r
 //
 // Returns the new DnD state (true = enabled).
 func (s *SharedState) ToggleDnD(trigger DnDTrigger, reason, source, ruleName string) bool {
-	s.SetDnD(!s.DnDEnabled, trigger, reason, source, ruleName)
+	s.SetDnD(!s.IsDnDActive(time.Now()), trigger, reason, source, ruleName)
 	return s.DnDEnabled
 }
 
+// SaveDnD records a user's DnD change from source in the state file: on for
+// d when d is positive, on until turned off, or off. Returns the new state.
+func SaveDnD(enabled bool, d time.Duration, source string) (*SharedState, error) {
+	state, err := LoadSharedState()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case enabled && d > 0:
+		state.SetDnDUntil(time.Now().Add(d), DnDTriggerUser, "dnd on", source, "")
+	case enabled:
+		state.SetDnD(true, DnDTriggerUser, "dnd on", source, "")
+	default:
+		state.SetDnD(false, DnDTriggerUser, "dnd off", source, "")
+	}
+	if err := SaveSharedState(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // UpdateLastNotification updates the last notification timestamp.
 func (s *SharedState) UpdateLastNotification() {
 	s.LastNotificationAt = time.Now().Unix()
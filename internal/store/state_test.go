@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedState_DnDUntil(t *testing.T) {
+	now := time.Now()
+	s := DefaultSharedState()
+
+	s.SetDnDUntil(now.Add(time.Hour), DnDTriggerUser, "dnd for 1h", "tui", "")
+	assert.True(t, s.IsDnDActive(now))
+	assert.False(t, s.IsDnDActive(now.Add(2*time.Hour)), "expires without a state change")
+
+	// Toggling an expired DnD turns it on again
+	s.DnDUntil = now.Add(-time.Minute).Unix()
+	assert.True(t, s.ToggleDnD(DnDTriggerUser, "dnd toggle", "cli", ""))
+	assert.Zero(t, s.DnDUntil)
+	assert.True(t, s.IsDnDActive(now.Add(24*time.Hour)))
+}
+
+func TestSaveDnD(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	state, err := SaveDnD(true, time.Hour, "tui")
+	require.NoError(t, err)
+	assert.True(t, state.IsDnDActive(time.Now()))
+	assert.Equal(t, "dnd on", state.DnDLastTransition.Reason)
+
+	_, err = SaveDnD(false, 0, "tui")
+	require.NoError(t, err)
+	loaded, err := LoadSharedState()
+	require.NoError(t, err)
+	assert.False(t, loaded.DnDEnabled)
+	assert.Zero(t, loaded.DnDUntil)
+	assert.Equal(t, "dnd off", loaded.DnDLastTransition.Reason)
+}
+
+func TestSharedState_SaveLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	s := DefaultSharedState()
+	s.SetDnDUntil(time.Unix(1700003600, 0), DnDTriggerUser, "dnd for 1h", "tui", "")
+	require.NoError(t, SaveSharedState(s))
+
+	loaded, err := LoadSharedState()
+	require.NoError(t, err)
+	assert.True(t, loaded.DnDEnabled)
+	assert.Equal(t, int64(1700003600), loaded.DnDUntil)
+	assert.Equal(t, "tui", loaded.DnDLastTransition.Source)
+}
@@ -2,6 +2,7 @@
 package store
 
 import (
+	"reflect"
 	"sort"
 	"sync"
 	"time"
@@ -21,6 +22,10 @@ const (
 	ChangeTypePrune
 	// ChangeTypeDelete indicates a notification was deleted.
 	ChangeTypeDelete
+	// ChangeTypeBatch indicates several notifications were changed by Batch.
+	ChangeTypeBatch
+	// ChangeTypeReload indicates the store was reloaded from persistence.
+	ChangeTypeReload
 )
 
 // ChangeEvent signals store content changes.
@@ -40,6 +45,13 @@ type FilterOptions struct {
 	SortOrder string        // "asc" or "desc" (default: "desc")
 }
 
+// Redactor rewrites sensitive content in notifications before they are
+// stored. It may set ContentHash; otherwise it is computed from the
+// redacted content.
+type Redactor interface {
+	Redact(n *model.Notification)
+}
+
 // Store manages the notification history with thread-safe operations.
 type Store struct {
 	mu            sync.RWMutex
@@ -49,6 +61,7 @@ type Store struct {
 	tombstones    map[string]bool // content_hash -> true (for deleted items)
 
 	persistence Persistence
+	redactor    Redactor
 
 	subscribers []chan ChangeEvent
 	closed      bool
@@ -67,6 +80,14 @@ func NewStore(persistence Persistence) *Store {
 	}
 }
 
+// SetRedactor sets the redactor applied to notifications added with Add
+// and AddBatch. Notifications already in the store are not changed.
+func (s *Store) SetRedactor(r Redactor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redactor = r
+}
+
 // Add adds a single notification to the store.
 func (s *Store) Add(n model.Notification) error {
 	s.mu.Lock()
@@ -76,6 +97,11 @@ func (s *Store) Add(n model.Notification) error {
 		return ErrStoreClosed
 	}
 
+	// Redact secrets before anything is hashed or persisted
+	if s.redactor != nil {
+		s.redactor.Redact(&n)
+	}
+
 	// Ensure content hash is computed for deduplication
 	n.EnsureContentHash()
 
@@ -135,7 +161,10 @@ func (s *Store) AddBatch(ns []model.Notification) error {
 	seenHashes := make(map[string]bool) // Track hashes within this batch too
 
 	for i := range ns {
-		// Ensure content hash is computed
+		// Redact secrets, then ensure content hash is computed
+		if s.redactor != nil {
+			s.redactor.Redact(&ns[i])
+		}
 		ns[i].EnsureContentHash()
 		hash := ns[i].ContentHash
 
@@ -330,20 +359,11 @@ func (s *Store) Delete(id string) error {
 	s.notifications = append(s.notifications[:idx], s.notifications[idx+1:]...)
 
 	// Rebuild indices
-	s.index = make(map[string]int, len(s.notifications))
-	s.hashIndex = make(map[string]int, len(s.notifications))
-	for i, n := range s.notifications {
-		s.index[n.HistuiID] = i
-		if n.ContentHash != "" {
-			s.hashIndex[n.ContentHash] = i
-		}
-	}
+	s.rebuildIndices()
 
-	// Rewrite persistence file if enabled
-	if s.persistence != nil {
-		if err := s.persistence.Rewrite(s.notifications); err != nil {
-			return err
-		}
+	// Log the deletion and drop it from disk
+	if err := s.logOps([]Op{{Type: OpDelete, ID: id}}, true); err != nil {
+		return err
 	}
 
 	s.notifyChange(ChangeEvent{
@@ -354,6 +374,29 @@ func (s *Store) Delete(id string) error {
 	return nil
 }
 
+// logOps logs ops if persistence is enabled. With scrub the log is then
+// compacted, so deleted or replaced content does not remain on disk.
+// Caller must hold the lock.
+func (s *Store) logOps(ops []Op, scrub bool) error {
+	if s.persistence == nil {
+		return nil
+	}
+	if err := s.persistence.AppendOps(ops); err != nil {
+		return err
+	}
+	if scrub {
+		return s.persistence.Compact()
+	}
+	return nil
+}
+
+// replacesContent reports whether updating old to n replaces text, such as
+// a redacted secret or a cleared note, that must not be left on disk.
+func replacesContent(old, n *model.Notification) bool {
+	return old.AppName != n.AppName || old.Summary != n.Summary || old.Body != n.Body ||
+		old.HistuiNote != n.HistuiNote || !reflect.DeepEqual(old.Extensions, n.Extensions)
+}
+
 // Count returns the total number of notifications.
 func (s *Store) Count() int {
 	s.mu.RLock()
@@ -376,13 +419,12 @@ func (s *Store) Update(n model.Notification) error {
 	}
 
 	// Update in slice
+	scrub := replacesContent(&s.notifications[idx], &n)
 	s.notifications[idx] = n
 
-	// Persist by rewriting (could be optimized later)
-	if s.persistence != nil {
-		if err := s.persistence.Rewrite(s.notifications); err != nil {
-			return err
-		}
+	// Persist
+	if err := s.logOps([]Op{{Type: OpUpdate, ID: n.HistuiID, Notification: &n}}, scrub); err != nil {
+		return err
 	}
 
 	return nil
@@ -407,7 +449,8 @@ func (s *Store) Dismiss(id string) error {
 
 	// Persist
 	if s.persistence != nil {
-		if err := s.persistence.Rewrite(s.notifications); err != nil {
+		op := Op{Type: OpDismiss, ID: id, At: s.notifications[idx].HistuiDismissedAt}
+		if err := s.persistence.AppendOps([]Op{op}); err != nil {
 			return err
 		}
 	}
@@ -444,20 +487,11 @@ func (s *Store) DeleteWithTombstone(id string) error {
 	s.notifications = append(s.notifications[:idx], s.notifications[idx+1:]...)
 
 	// Rebuild indices
-	s.index = make(map[string]int, len(s.notifications))
-	s.hashIndex = make(map[string]int, len(s.notifications))
-	for i, n := range s.notifications {
-		s.index[n.HistuiID] = i
-		if n.ContentHash != "" {
-			s.hashIndex[n.ContentHash] = i
-		}
-	}
+	s.rebuildIndices()
 
-	// Rewrite persistence file if enabled
-	if s.persistence != nil {
-		if err := s.persistence.Rewrite(s.notifications); err != nil {
-			return err
-		}
+	// Log the deletion and drop it from disk
+	if err := s.logOps([]Op{{Type: OpDelete, ID: id}}, true); err != nil {
+		return err
 	}
 
 	s.notifyChange(ChangeEvent{
@@ -595,6 +629,130 @@ func (s *Store) Hydrate() error {
 	return nil
 }
 
+// Reload brings the store up to date with what is currently persisted.
+// Unlike Hydrate, which only adds new notifications, it picks up changes
+// other processes made to existing ones (dismissals, snoozes, deletions).
+// Only the entries logged since the last load are applied, unless the log
+// has been compacted or replaced since.
+func (s *Store) Reload() error {
+	if s.persistence == nil {
+		return nil
+	}
+
+	entries, reset, err := s.persistence.LoadTail()
+	if err != nil {
+		return err
+	}
+	if reset {
+		return s.reloadAll()
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	deleted := false
+	for _, e := range entries {
+		if e.Record != nil {
+			n := *e.Record
+			n.EnsureContentHash()
+			if _, exists := s.hashIndex[n.ContentHash]; exists {
+				continue
+			}
+			if _, exists := s.index[n.HistuiID]; exists {
+				continue
+			}
+			idx := len(s.notifications)
+			s.notifications = append(s.notifications, n)
+			s.index[n.HistuiID] = idx
+			s.hashIndex[n.ContentHash] = idx
+			continue
+		}
+
+		idx, exists := s.index[e.Op.ID]
+		if !exists {
+			continue
+		}
+		if e.Op.Type == OpDelete {
+			// Cleared IDs are dropped below, once
+			delete(s.index, e.Op.ID)
+			delete(s.hashIndex, s.notifications[idx].ContentHash)
+			s.notifications[idx].HistuiID = ""
+			deleted = true
+			continue
+		}
+		e.Op.Apply(&s.notifications[idx])
+	}
+
+	if deleted {
+		kept := s.notifications[:0]
+		for _, n := range s.notifications {
+			if n.HistuiID != "" {
+				kept = append(kept, n)
+			}
+		}
+		s.notifications = kept
+		s.rebuildIndices()
+	}
+
+	s.notifyChange(ChangeEvent{
+		Type:   ChangeTypeReload,
+		Count:  len(s.notifications),
+		Source: "persistence",
+	})
+
+	return nil
+}
+
+// reloadAll replaces the store contents with a full load from persistence.
+func (s *Store) reloadAll() error {
+	notifications, err := s.persistence.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	s.notifications = make([]model.Notification, 0, len(notifications))
+	s.index = make(map[string]int, len(notifications))
+	s.hashIndex = make(map[string]int, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
+		n.EnsureContentHash()
+
+		if _, exists := s.hashIndex[n.ContentHash]; exists {
+			continue
+		}
+		if _, exists := s.index[n.HistuiID]; exists {
+			continue
+		}
+
+		idx := len(s.notifications)
+		s.notifications = append(s.notifications, *n)
+		s.index[n.HistuiID] = idx
+		s.hashIndex[n.ContentHash] = idx
+	}
+
+	s.notifyChange(ChangeEvent{
+		Type:   ChangeTypeReload,
+		Count:  len(s.notifications),
+		Source: "persistence",
+	})
+
+	return nil
+}
+
 // Clear removes all notifications from the store.
 func (s *Store) Clear() error {
 	s.mu.Lock()
@@ -623,6 +781,19 @@ func (s *Store) Clear() error {
 	return nil
 }
 
+// rebuildIndices recreates the ID and hash indices from the notification slice.
+// Caller must hold the write lock.
+func (s *Store) rebuildIndices() {
+	s.index = make(map[string]int, len(s.notifications))
+	s.hashIndex = make(map[string]int, len(s.notifications))
+	for i, n := range s.notifications {
+		s.index[n.HistuiID] = i
+		if n.ContentHash != "" {
+			s.hashIndex[n.ContentHash] = i
+		}
+	}
+}
+
 // notifyChange sends a change event to all subscribers (non-blocking).
 func (s *Store) notifyChange(event ChangeEvent) {
 	for _, ch := range s.subscribers {
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -272,6 +273,73 @@ func TestStore_Close(t *testing.T) {
 
 // Helper functions
 
+func TestStore_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	p1, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s1 := NewStore(p1)
+	defer s1.Close()
+	require.NoError(t, s1.Add(testNotification("r1")))
+	require.NoError(t, s1.Add(testNotification("r2")))
+
+	// Another process snoozes one notification and deletes the other
+	p2, err := NewJSONLPersistence(path)
+	require.NoError(t, err)
+	s2 := NewStore(p2)
+	defer s2.Close()
+	require.NoError(t, s2.Hydrate())
+	require.NoError(t, s2.Batch(func(tx *Tx) error {
+		tx.Snooze("r1", time.Now().Add(time.Hour))
+		tx.Delete("r2")
+		return nil
+	}))
+
+	ch := s1.Subscribe()
+	require.NoError(t, s1.Reload())
+
+	assert.Equal(t, 1, s1.Count())
+	assert.True(t, s1.GetByID("r1").IsSnoozed())
+	assert.Nil(t, s1.GetByID("r2"))
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, ChangeTypeReload, event.Type)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+// bodyRedactor replaces every body, standing in for redact.Redactor.
+type bodyRedactor struct{}
+
+func (bodyRedactor) Redact(n *model.Notification) {
+	n.Body = "[redacted]"
+	n.HistuiRedacted = []string{"test"}
+}
+
+func TestStore_Redactor(t *testing.T) {
+	s := NewStore(nil)
+	defer s.Close()
+	s.SetRedactor(bodyRedactor{})
+
+	require.NoError(t, s.Add(testNotificationWithTime("r1", 1700000000)))
+	require.NoError(t, s.AddBatch([]model.Notification{testNotification("r2"), testNotification("r3")}))
+
+	for _, n := range s.All() {
+		assert.Equal(t, "[redacted]", n.Body)
+		assert.Equal(t, []string{"test"}, n.HistuiRedacted)
+		assert.Equal(t, n.ComputeContentHash(), n.ContentHash, "hash is computed after redaction")
+	}
+
+	// Re-importing the same content is still deduplicated
+	n := testNotificationWithTime("r1-again", 1700000000)
+	n.Summary = "Test Summary r1"
+	n.Body = "Different secret"
+	require.NoError(t, s.Add(n))
+	assert.Equal(t, 3, s.Count())
+}
+
 func testNotification(id string) model.Notification {
 	return model.Notification{
 		HistuiID:         id,
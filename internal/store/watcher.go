@@ -8,7 +8,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
-// FileWatcher watches a file for changes and triggers rehydration.
+// FileWatcher watches a file for changes and reloads the store.
 type FileWatcher struct {
 	watcher  *fsnotify.Watcher
 	store    *Store
@@ -73,9 +73,9 @@ func (fw *FileWatcher) watch() {
 
 			// Handle write events
 			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
-				slog.Debug("file changed, rehydrating store", "file", fw.filePath)
-				if err := fw.store.Hydrate(); err != nil {
-					slog.Warn("failed to rehydrate store", "error", err)
+				slog.Debug("file changed, reloading store", "file", fw.filePath)
+				if err := fw.store.Reload(); err != nil {
+					slog.Warn("failed to reload store", "error", err)
 				}
 			}
 
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+const (
+	// calendarDays is the most days the heatmap shows.
+	calendarDays = 28
+
+	// calendarDayColumn is the heatmap column after the hours, which stands
+	// for the whole day.
+	calendarDayColumn = 24
+)
+
+// heatLevels shade heatmap cells from empty to busiest.
+var heatLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// heatmap counts notifications per day and hour. Row 0 is today, row 1
+// yesterday and so on, for days rows.
+func heatmap(notifications []model.Notification, now time.Time, days int) [][24]int {
+	counts := make([][24]int, days)
+	today := dayStart(now)
+	for i := range notifications {
+		ts := notifications[i].TimestampTime()
+		// Rounded, as days are 23 or 25 hours long when clocks change
+		day := int(math.Round(today.Sub(dayStart(ts)).Hours() / 24))
+		if day >= 0 && day < days {
+			counts[day][ts.Hour()]++
+		}
+	}
+	return counts
+}
+
+// cellWindow returns the time window of a heatmap cell.
+func cellWindow(day, hour int, now time.Time) timeWindow {
+	start := dayStart(now).AddDate(0, 0, -day)
+	if hour == calendarDayColumn {
+		return timeWindow{start: start, end: start.AddDate(0, 0, 1)}
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), hour, 0, 0, 0, start.Location())
+	return timeWindow{start: start, end: start.Add(time.Hour)}
+}
+
+// calendarRows returns how many days of the heatmap fit on screen.
+func (m Model) calendarRows() int {
+	// Title, axis, summary, keybind bar and spacing take seven lines
+	return max(min(calendarDays, m.height-7), 1)
+}
+
+// openCalendar shows the heatmap, with the cursor on the current hour
+// unless a window is already picked.
+func (m Model) openCalendar() (tea.Model, tea.Cmd) {
+	m.mode = ModeCalendar
+	if m.window == nil {
+		m.calDay = 0
+		m.calHour = time.Now().Hour()
+	}
+	return m, nil
+}
+
+// handleCalendarKey handles keys on the heatmap.
+func (m Model) handleCalendarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Calendar):
+		m.mode = ModeList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.notifications = m.fetchNotifications()
+		m.list.SetItems(m.buildListItems())
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		m.calDay = max(m.calDay-1, 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		m.calDay = min(m.calDay+1, m.calendarRows()-1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		now := time.Now()
+		w := cellWindow(m.calDay, m.calHour, now)
+		m.mode = ModeList
+		m = m.setWindow(&w)
+		text := fmt.Sprintf("%s: %s (%s shows all)", w.label(now), countNotifications(len(m.list.Items())), shortKey(m.keys.Back))
+		return m, func() tea.Msg {
+			return statusMsg{text: text, isErr: false}
+		}
+	}
+
+	switch msg.String() {
+	case "left", "h":
+		m.calHour = max(m.calHour-1, 0)
+	case "right", "l":
+		m.calHour = min(m.calHour+1, calendarDayColumn)
+	}
+	return m, nil
+}
+
+// renderCalendar renders notification counts per day and hour as a
+// heatmap, today at the top.
+func (m Model) renderCalendar() string {
+	now := time.Now()
+	rows := m.calendarRows()
+	// Counted before any search or window, so the whole history shows
+	notifications := m.visibleNotifications()
+	counts := heatmap(notifications, now, rows)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.colors.Header)
+	labelStyle := fg(m.colors.Label)
+	cellStyle := fg(m.colors.Key)
+	cursorStyle := lipgloss.NewStyle().Reverse(true).Foreground(m.colors.Selected)
+
+	labels := make([]string, rows)
+	labelWidth, busiest := 0, 0
+	for day := range rows {
+		labels[day] = dayLabel(dayStart(now).AddDate(0, 0, -day), now)
+		labelWidth = max(labelWidth, lipgloss.Width(labels[day]))
+		for _, c := range counts[day] {
+			busiest = max(busiest, c)
+		}
+	}
+
+	// Two columns per hour when there is room
+	cellWidth := 1
+	if m.width >= labelWidth+1+24*2+6 {
+		cellWidth = 2
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("Activity by day and hour") + "\n\n")
+
+	axis := []rune(strings.Repeat(" ", 24*cellWidth))
+	for h := 0; h < 24; h += 6 {
+		copy(axis[h*cellWidth:], []rune(strconv.Itoa(h)))
+	}
+	sb.WriteString(labelStyle.Render(strings.Repeat(" ", labelWidth+1)+string(axis)+"  day") + "\n")
+
+	for day := range rows {
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%-*s ", labelWidth, labels[day])))
+		total := 0
+		for hour, c := range counts[day] {
+			total += c
+			cell := strings.Repeat(heatLevels[heatLevel(c, busiest)], cellWidth)
+			switch {
+			case day == m.calDay && hour == m.calHour:
+				sb.WriteString(cursorStyle.Render(cell))
+			case c == 0:
+				sb.WriteString(labelStyle.Render(cell))
+			default:
+				sb.WriteString(cellStyle.Render(cell))
+			}
+		}
+		totalText := fmt.Sprintf("%5d", total)
+		if day == m.calDay && m.calHour == calendarDayColumn {
+			totalText = cursorStyle.Render(totalText)
+		}
+		sb.WriteString(totalText + "\n")
+	}
+
+	w := cellWindow(m.calDay, m.calHour, now)
+	found := core.Filter(notifications, core.FilterOptions{After: w.start, Before: w.end})
+	sb.WriteString("\n" + titleStyle.Render(w.label(now)) + labelStyle.Render(": "+countNotifications(len(found))) + "\n")
+	return sb.String()
+}
+
+// heatLevel returns the heatLevels index for count, scaled to the busiest
+// cell.
+func heatLevel(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	steps := len(heatLevels) - 1
+	return min((count*steps+busiest-1)/busiest, steps)
+}
+
+func (m Model) viewCalendar() string {
+	s := m.renderCalendar() + "\n"
+	if m.statusMsg != "" {
+		statusStyle := fg(m.colors.Status)
+		if m.statusErr {
+			statusStyle = fg(m.colors.Error)
+		}
+		return s + statusStyle.Render(m.statusMsg)
+	}
+	return s + m.buildKeybindBar(m.width, "calendar")
+}
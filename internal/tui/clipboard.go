@@ -4,61 +4,27 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
-	"strings"
-	"time"
 
 	"github.com/jmylchreest/histui/internal/adapter/input"
+	"github.com/jmylchreest/histui/internal/clipboard"
 	"github.com/jmylchreest/histui/internal/config"
 	"github.com/jmylchreest/histui/internal/store"
 )
 
 // copyText copies text to the system clipboard.
 func copyText(text string, cfg *config.Config) error {
-	// Get clipboard command
-	cmd := detectClipboardCommand(cfg)
-	if cmd == "" {
-		return fmt.Errorf("no clipboard command available")
-	}
-
-	// Parse command
-	parts := strings.Fields(cmd)
-	if len(parts) == 0 {
-		return fmt.Errorf("invalid clipboard command")
-	}
-
-	// Execute with text as stdin
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	c := exec.CommandContext(ctx, parts[0], parts[1:]...)
-	c.Stdin = strings.NewReader(text)
-
-	return c.Run()
+	return clipboard.Copy(text, cfg)
 }
 
-// detectClipboardCommand returns the clipboard command to use.
-func detectClipboardCommand(cfg *config.Config) string {
-	// Use configured command if specified
-	if cfg != nil && cfg.Clipboard.Command != "" {
-		return cfg.Clipboard.Command
-	}
-
-	// Auto-detect based on environment
-	// Check for Wayland
-	if _, err := exec.LookPath("wl-copy"); err == nil {
-		return "wl-copy"
-	}
-
-	// Check for X11
-	if _, err := exec.LookPath("xclip"); err == nil {
-		return "xclip -selection clipboard"
-	}
-
-	if _, err := exec.LookPath("xsel"); err == nil {
-		return "xsel --clipboard --input"
+// openURL opens a link with xdg-open without waiting for the application it
+// starts. A variable so tests can record links instead.
+var openURL = func(link string) error {
+	cmd := exec.Command("xdg-open", link)
+	if err := cmd.Start(); err != nil {
+		return err
 	}
-
-	return ""
+	go func() { _ = cmd.Wait() }()
+	return nil
 }
 
 // importFromAdapter imports notifications from an input adapter into the store.
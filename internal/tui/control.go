@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// daemonPollInterval is how often the header and control page re-read the
+// DnD state and ask histuid what it is showing.
+const daemonPollInterval = 2 * time.Second
+
+// dndSource identifies the TUI in DnD transitions.
+const dndSource = "tui"
+
+// daemonControl is the part of histuid's control interface the TUI uses.
+type daemonControl interface {
+	Status() (dbus.Status, error)
+	SetDnD(enabled bool, d time.Duration, source string) error
+	CloseAll() error
+	ShowNotification(histuiID string) error
+}
+
+// connectDaemon connects to a running histuid. A variable so tests can
+// substitute a fake.
+var connectDaemon = func() (daemonControl, error) {
+	client, err := dbus.NewControlClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// daemonStatus is the state shown in the header and on the control page.
+type daemonStatus struct {
+	state   *store.SharedState
+	running bool        // histuid answered
+	popups  dbus.Status // Valid when running
+}
+
+// daemonStatusMsg carries a freshly loaded status. Polled statuses schedule
+// the next poll.
+type daemonStatusMsg struct {
+	status daemonStatus
+	poll   bool
+}
+
+// controlResultMsg reports the outcome of a control action together with
+// the status after it.
+type controlResultMsg struct {
+	result statusMsg
+	status daemonStatus
+}
+
+// loadDaemonStatus reads the shared state and asks histuid, if running,
+// what it is showing.
+func loadDaemonStatus() daemonStatus {
+	state, err := store.LoadSharedState()
+	if err != nil {
+		state = store.DefaultSharedState()
+	}
+	st := daemonStatus{state: state}
+	if client, err := connectDaemon(); err == nil {
+		if popups, err := client.Status(); err == nil {
+			st.running = true
+			st.popups = popups
+		}
+	}
+	return st
+}
+
+// pollDaemon loads the status, after a delay unless it is the first poll.
+func pollDaemon(delay time.Duration) tea.Cmd {
+	if delay == 0 {
+		return func() tea.Msg {
+			return daemonStatusMsg{status: loadDaemonStatus(), poll: true}
+		}
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return daemonStatusMsg{status: loadDaemonStatus(), poll: true}
+	})
+}
+
+// controlCmd runs a control action in the background, then reports its
+// result and the refreshed status.
+func controlCmd(action func() statusMsg) tea.Cmd {
+	return func() tea.Msg {
+		result := action()
+		return controlResultMsg{result: result, status: loadDaemonStatus()}
+	}
+}
+
+// setDnD turns DnD on or off through histuid, which applies it at once, or
+// in the shared state file when histuid is not running. A positive
+// duration turns DnD off again after d.
+func setDnD(enabled bool, d time.Duration) tea.Cmd {
+	return controlCmd(func() statusMsg {
+		var err error
+		if client, cerr := connectDaemon(); cerr == nil {
+			err = client.SetDnD(enabled, d, dndSource)
+		} else {
+			_, err = store.SaveDnD(enabled, d, dndSource)
+		}
+		switch {
+		case err != nil:
+			return statusMsg{text: "Failed to set Do Not Disturb: " + err.Error(), isErr: true}
+		case enabled && d > 0:
+			return statusMsg{text: "Do Not Disturb on until " + time.Now().Add(d).Format("15:04"), isErr: false}
+		case enabled:
+			return statusMsg{text: "Do Not Disturb on", isErr: false}
+		default:
+			return statusMsg{text: "Do Not Disturb off", isErr: false}
+		}
+	})
+}
+
+// withDaemon runs fn against histuid, which must be running.
+func withDaemon(fn func(daemonControl) error, done string) tea.Cmd {
+	return controlCmd(func() statusMsg {
+		client, err := connectDaemon()
+		if err == nil {
+			err = fn(client)
+		}
+		if err != nil {
+			return statusMsg{text: err.Error(), isErr: true}
+		}
+		return statusMsg{text: done, isErr: false}
+	})
+}
+
+// hasStatusHeader reports whether the DnD and histuid header is shown. It
+// appears once the first status has loaded.
+func (m Model) hasStatusHeader() bool {
+	return m.showStatus && m.daemon != nil
+}
+
+// renderStatusHeader renders the one-line DnD and histuid summary.
+func (m Model) renderStatusHeader() string {
+	d := m.daemon
+	dnd := fg(m.colors.Label).Render("DnD off")
+	if d.state.IsDnDActive(time.Now()) {
+		dnd = fg(m.colors.Marked).Render("DnD " + dndText(d.state))
+	}
+
+	info := "histuid not running"
+	if d.running {
+		info = fmt.Sprintf("%d shown, %d queued · theme %s", d.popups.Active, d.popups.Queued, d.popups.Theme)
+	}
+	line := dnd + fg(m.colors.Label).Render(" · "+info)
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
+
+// dndText describes an active DnD: "on" or "on until 15:04".
+func dndText(state *store.SharedState) string {
+	if state.DnDUntil > 0 {
+		return "on until " + time.Unix(state.DnDUntil, 0).Format("15:04")
+	}
+	return "on"
+}
+
+// openControl shows the control page with a fresh status.
+func (m Model) openControl() (tea.Model, tea.Cmd) {
+	m.mode = ModeControl
+	return m, func() tea.Msg {
+		return daemonStatusMsg{status: loadDaemonStatus()}
+	}
+}
+
+// handleControlKey handles keys on the control page. The page's own keys
+// are fixed and listed on it.
+func (m Model) handleControlKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Control):
+		m.mode = ModeList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m.openControl()
+	}
+
+	switch msg.String() {
+	case "d":
+		active := m.daemon != nil && m.daemon.state.IsDnDActive(time.Now())
+		return m, setDnD(!active, 0)
+
+	case "t":
+		return m.openPrompt(promptDnD, nil)
+
+	case "c":
+		return m, withDaemon(func(c daemonControl) error {
+			return c.CloseAll()
+		}, "Closed all popups")
+
+	case "s":
+		item, ok := m.list.SelectedItem().(notificationItem)
+		if !ok {
+			return m, nil
+		}
+		id := item.notification.HistuiID
+		return m, withDaemon(func(c daemonControl) error {
+			return c.ShowNotification(id)
+		}, "Shown again: "+item.notification.Summary)
+	}
+	return m, nil
+}
+
+// renderControl renders the control page.
+func (m Model) renderControl() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.colors.Header)
+	labelStyle := fg(m.colors.Label)
+	keyStyle := fg(m.colors.Key)
+
+	s := titleStyle.Render("Do Not Disturb and popups") + "\n\n"
+	row := func(label, value string) string {
+		return labelStyle.Render(fmt.Sprintf("  %-16s", label)) + value + "\n"
+	}
+
+	d := m.daemon
+	if d == nil {
+		return s + labelStyle.Render("  Loading...") + "\n"
+	}
+
+	dnd := "off"
+	if d.state.IsDnDActive(time.Now()) {
+		dnd = dndText(d.state)
+	}
+	s += row("Do Not Disturb", dnd)
+	if t := d.state.DnDLastTransition; t != nil {
+		change := humanize.Time(time.Unix(t.Timestamp, 0)) + ": " + t.Reason
+		if t.Source != "" {
+			change += " (" + t.Source + ")"
+		}
+		if t.RuleName != "" {
+			change += ", rule " + t.RuleName
+		}
+		s += row("Last change", change)
+	}
+	if d.running {
+		s += row("histuid", "running, theme "+d.popups.Theme)
+		s += row("Popups", fmt.Sprintf("%d shown, %d queued", d.popups.Active, d.popups.Queued))
+	} else {
+		s += row("histuid", "not running (DnD changes are saved for its next start)")
+	}
+	s += "\n"
+
+	line := func(k, desc string) string {
+		return keyStyle.Render(fmt.Sprintf("  %-4s", k)) + desc + "\n"
+	}
+	s += line("d", "Turn Do Not Disturb on or off")
+	s += line("t", "Do Not Disturb for a while (30m, 2h, 15:00)")
+	s += line("c", "Close all popups")
+	if item, ok := m.list.SelectedItem().(notificationItem); ok {
+		s += line("s", "Show \""+truncate(item.notification.Summary, 40)+"\" again")
+	}
+	s += line(shortKey(m.keys.Refresh), "Refresh")
+	return s
+}
+
+func (m Model) viewControl() string {
+	s := m.renderControl() + "\n"
+	if m.statusMsg != "" {
+		statusStyle := fg(m.colors.Status)
+		if m.statusErr {
+			statusStyle = fg(m.colors.Error)
+		}
+		return s + statusStyle.Render(m.statusMsg)
+	}
+	return s + m.buildKeybindBar(m.width, "control")
+}
+
+// truncate shortens s to n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n-1])) + "…"
+}
+
+// statusHeaderLine returns the status header followed by a newline, or
+// nothing when it is hidden.
+func (m Model) statusHeaderLine() string {
+	if !m.hasStatusHeader() {
+		return ""
+	}
+	return m.renderStatusHeader() + "\n"
+}
+
+// setDaemonStatus stores a loaded status, resizing the list when the header
+// first appears.
+func (m Model) setDaemonStatus(st daemonStatus) Model {
+	had := m.hasStatusHeader()
+	m.daemon = &st
+	if !had && m.hasStatusHeader() {
+		m = m.resize()
+	}
+	return m
+}
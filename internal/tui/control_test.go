@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/dbus"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// fakeDaemon records the control calls the TUI makes.
+type fakeDaemon struct {
+	status dbus.Status
+	dnd    *bool
+	dndFor time.Duration
+	closed int
+	shown  []string
+}
+
+func (f *fakeDaemon) Status() (dbus.Status, error) { return f.status, nil }
+
+func (f *fakeDaemon) SetDnD(enabled bool, d time.Duration, source string) error {
+	f.dnd = &enabled
+	f.dndFor = d
+	f.status.DnD = enabled
+	return nil
+}
+
+func (f *fakeDaemon) CloseAll() error {
+	f.closed++
+	f.status.Active = 0
+	return nil
+}
+
+func (f *fakeDaemon) ShowNotification(histuiID string) error {
+	f.shown = append(f.shown, histuiID)
+	return nil
+}
+
+// useDaemon replaces connectDaemon for the test; a nil daemon is not
+// running. The shared state file goes to a temporary directory.
+func useDaemon(t *testing.T, d *fakeDaemon) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	orig := connectDaemon
+	connectDaemon = func() (daemonControl, error) {
+		if d == nil {
+			return nil, errors.New("histuid is not running")
+		}
+		return d, nil
+	}
+	t.Cleanup(func() { connectDaemon = orig })
+}
+
+func TestControl_StatusHeader(t *testing.T) {
+	useDaemon(t, &fakeDaemon{status: dbus.Status{Active: 2, Queued: 1, Theme: "dark"}})
+	m, _ := newSelectionTestModel(t, 3)
+	height := m.list.Height()
+	assert.NotContains(t, m.View(), "DnD", "no header until the status loads")
+
+	m = update(t, m, pollDaemon(0)())
+	assert.Equal(t, height-1, m.list.Height(), "the header takes a line")
+	assert.Contains(t, m.View(), "DnD off · 2 shown, 1 queued · theme dark")
+	assert.Equal(t, 1, m.listTop())
+}
+
+func TestControl_Actions(t *testing.T) {
+	daemon := &fakeDaemon{status: dbus.Status{Active: 1, Theme: "default"}}
+	useDaemon(t, daemon)
+	m, _ := newSelectionTestModel(t, 3)
+
+	m, msg := press(t, m, runes("H"))
+	require.Equal(t, ModeControl, m.mode)
+	m = update(t, m, msg)
+	assert.Contains(t, m.View(), "running, theme default")
+	assert.Contains(t, m.View(), `Show "Summary 0" again`)
+
+	m, msg = press(t, m, runes("d"))
+	require.NotNil(t, daemon.dnd)
+	assert.True(t, *daemon.dnd)
+	assert.Zero(t, daemon.dndFor)
+	m = update(t, m, msg)
+	assert.Equal(t, "Do Not Disturb on", m.statusMsg)
+
+	m, msg = press(t, m, runes("c"))
+	assert.Equal(t, 1, daemon.closed)
+	m = update(t, m, msg)
+	assert.Equal(t, "Closed all popups", m.statusMsg)
+	assert.Contains(t, m.renderControl(), "0 shown")
+
+	m, msg = press(t, m, runes("s"))
+	assert.Equal(t, []string{"sel0"}, daemon.shown)
+	m = update(t, m, msg)
+	assert.Equal(t, "Shown again: Summary 0", m.statusMsg)
+
+	m, _ = press(t, m, runes("t"))
+	require.Equal(t, ModePrompt, m.mode)
+	assert.Contains(t, m.View(), "Do Not Disturb for: ")
+	m.promptInput.SetValue("30m")
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ModeControl, m.mode)
+	assert.InDelta(t, 30*time.Minute, daemon.dndFor, float64(time.Minute))
+
+	m, _ = press(t, m, runes("t"))
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ModeControl, m.mode, "cancelling returns to the control page")
+
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ModeList, m.mode)
+}
+
+func TestControl_WithoutDaemon(t *testing.T) {
+	useDaemon(t, nil)
+	m, _ := newSelectionTestModel(t, 1)
+
+	m, msg := press(t, m, runes("H"))
+	m = update(t, m, msg)
+	assert.Contains(t, m.View(), "not running")
+
+	// DnD is saved for histuid's next start
+	m, msg = press(t, m, runes("d"))
+	m = update(t, m, msg)
+	assert.Equal(t, "Do Not Disturb on", m.statusMsg)
+	state, err := store.LoadSharedState()
+	require.NoError(t, err)
+	assert.True(t, state.DnDEnabled)
+	require.NotNil(t, state.DnDLastTransition)
+	assert.Equal(t, "tui", state.DnDLastTransition.Source)
+	assert.Contains(t, m.renderControl(), "(tui)")
+
+	// Popups need histuid
+	_, msg = press(t, m, runes("c"))
+	assert.Equal(t, controlResultMsg{
+		result: statusMsg{text: "histuid is not running", isErr: true},
+		status: msg.(controlResultMsg).status,
+	}, msg)
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/jmylchreest/histui/internal/core"
 )
 
 func TestIsFilterExpression(t *testing.T) {
@@ -25,6 +27,11 @@ func TestIsFilterExpression(t *testing.T) {
 		{"seen", "seen=false", true},
 		{"timestamp", "timestamp<1h", true},
 		{"category", "category=email", true},
+		{"tag", "tag=work", true},
+		{"starred", "starred=true", true},
+		{"source", "source=dunst", true},
+		{"progress", "progress>=50", true},
+		{"extension", "ext.sound_name=bell", true},
 		{"multiple", "app=slack,urgency=critical", true},
 
 		// Not filter expressions (plain text search)
@@ -49,3 +56,12 @@ func TestIsFilterExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestViewHelpFilters_ListsFields(t *testing.T) {
+	m, _ := newSelectionTestModel(t, 1)
+	view := m.viewHelpFilters()
+	for _, f := range core.FilterFields() {
+		assert.Contains(t, view, f.Name)
+	}
+	assert.Contains(t, view, core.ExtensionPrefix+"<key>")
+}
@@ -1,7 +1,13 @@
 package tui
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/jmylchreest/histui/internal/config"
 )
 
 // KeyMap defines the key bindings for the TUI.
@@ -21,11 +27,35 @@ type KeyMap struct {
 	CopySummary     key.Binding
 	CopyAllJSON     key.Binding
 	CopyAllYAML     key.Binding
+	CopyCode        key.Binding
+	OpenLink        key.Binding
 	Dismiss         key.Binding
 	HardDelete      key.Binding
+	MarkSeen        key.Binding
+	Export          key.Binding
+	Snooze          key.Binding
+	Star            key.Binding
+	Tag             key.Binding
+	Note            key.Binding
 	Search          key.Binding
 	Refresh         key.Binding
 	ToggleDismissed key.Binding
+	Stats           key.Binding
+	Control         key.Binding
+	Timeline        key.Binding
+	Calendar        key.Binding
+	NextView        key.Binding
+	PrevView        key.Binding
+
+	// Preview
+	TogglePreview key.Binding
+	GrowList      key.Binding
+	ShrinkList    key.Binding
+
+	// Selection
+	Mark      key.Binding
+	MarkRange key.Binding
+	MarkAll   key.Binding
 
 	// Global
 	Quit key.Binding
@@ -41,90 +71,150 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown},
-		{k.Enter, k.Back, k.Copy, k.CopySummary},
-		{k.Search, k.Refresh, k.Dismiss, k.HardDelete},
-		{k.ToggleDismissed, k.Help, k.Quit},
+		{k.Enter, k.Back, k.Copy, k.CopySummary, k.CopyCode, k.OpenLink},
+		{k.Search, k.Refresh, k.Dismiss, k.HardDelete, k.MarkSeen, k.Snooze},
+		{k.Star, k.Tag, k.Note, k.Export},
+		{k.Mark, k.MarkRange, k.MarkAll},
+		{k.ToggleDismissed, k.Stats, k.Control, k.NextView, k.PrevView},
+		{k.Timeline, k.Calendar},
+		{k.TogglePreview, k.GrowList, k.ShrinkList},
+		{k.Help, k.Quit},
 	}
 }
 
+// keyHelp describes each action in the help, by its [tui.keys] name.
+var keyHelp = map[string]string{
+	"up":               "up",
+	"down":             "down",
+	"page_up":          "page up",
+	"page_down":        "page down",
+	"home":             "go to top",
+	"end":              "go to bottom",
+	"enter":            "view details",
+	"back":             "back",
+	"copy":             "copy body",
+	"copy_summary":     "copy summary",
+	"copy_json":        "copy marked or all as JSON",
+	"copy_yaml":        "copy marked or all as YAML",
+	"copy_code":        "copy code",
+	"open_link":        "open link",
+	"export":           "export marked or all",
+	"dismiss":          "dismiss",
+	"delete":           "delete permanently",
+	"mark_seen":        "mark seen",
+	"snooze":           "snooze",
+	"star":             "star",
+	"tag":              "tag",
+	"note":             "note",
+	"search":           "search",
+	"refresh":          "refresh",
+	"toggle_dismissed": "toggle dismissed",
+	"stats":            "stats",
+	"control":          "histuid controls",
+	"timeline":         "group by day",
+	"calendar":         "activity heatmap",
+	"next_view":        "next view",
+	"prev_view":        "previous view",
+	"preview":          "toggle preview",
+	"grow_list":        "widen list",
+	"shrink_list":      "narrow list",
+	"mark":             "mark",
+	"mark_range":       "mark range",
+	"mark_all":         "mark all listed",
+	"help":             "help",
+	"quit":             "quit",
+}
+
 // DefaultKeyMap returns the default key bindings.
 func DefaultKeyMap() KeyMap {
+	bindings, _ := config.TUIKeysConfig{Preset: config.DefaultKeyPreset}.Bindings()
+	return NewKeyMap(bindings)
+}
+
+// NewKeyMap creates key bindings from keys by action, as resolved from the
+// [tui.keys] config.
+func NewKeyMap(bindings map[string][]string) KeyMap {
+	b := func(action string) key.Binding {
+		keys := bindings[action]
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = config.KeyName(k)
+		}
+		return key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(strings.Join(names, "/"), keyHelp[action]),
+		)
+	}
 	return KeyMap{
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "down"),
-		),
-		PageUp: key.NewBinding(
-			key.WithKeys("pgup", "ctrl+u"),
-			key.WithHelp("pgup", "page up"),
-		),
-		PageDown: key.NewBinding(
-			key.WithKeys("pgdown", "ctrl+d"),
-			key.WithHelp("pgdn", "page down"),
-		),
-		Home: key.NewBinding(
-			key.WithKeys("home", "g"),
-			key.WithHelp("home/g", "go to top"),
-		),
-		End: key.NewBinding(
-			key.WithKeys("end", "G"),
-			key.WithHelp("end/G", "go to bottom"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "view details"),
-		),
-		Back: key.NewBinding(
-			key.WithKeys("esc", "backspace"),
-			key.WithHelp("esc", "back"),
-		),
-		Copy: key.NewBinding(
-			key.WithKeys("c"),
-			key.WithHelp("c", "copy body"),
-		),
-		CopySummary: key.NewBinding(
-			key.WithKeys("s"),
-			key.WithHelp("s", "copy summary"),
-		),
-		CopyAllJSON: key.NewBinding(
-			key.WithKeys("C"),
-			key.WithHelp("C", "copy all as JSON"),
-		),
-		CopyAllYAML: key.NewBinding(
-			key.WithKeys("alt+c"),
-			key.WithHelp("alt+c", "copy all as YAML"),
-		),
-		Dismiss: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("d", "dismiss"),
-		),
-		HardDelete: key.NewBinding(
-			key.WithKeys("D"),
-			key.WithHelp("D", "delete permanently"),
-		),
-		Search: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
-		),
-		Refresh: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "refresh"),
-		),
-		ToggleDismissed: key.NewBinding(
-			key.WithKeys("a"),
-			key.WithHelp("a", "toggle dismissed"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
+		Up:              b("up"),
+		Down:            b("down"),
+		PageUp:          b("page_up"),
+		PageDown:        b("page_down"),
+		Home:            b("home"),
+		End:             b("end"),
+		Enter:           b("enter"),
+		Back:            b("back"),
+		Copy:            b("copy"),
+		CopySummary:     b("copy_summary"),
+		CopyAllJSON:     b("copy_json"),
+		CopyAllYAML:     b("copy_yaml"),
+		CopyCode:        b("copy_code"),
+		OpenLink:        b("open_link"),
+		Dismiss:         b("dismiss"),
+		HardDelete:      b("delete"),
+		MarkSeen:        b("mark_seen"),
+		Export:          b("export"),
+		Snooze:          b("snooze"),
+		Star:            b("star"),
+		Tag:             b("tag"),
+		Note:            b("note"),
+		Search:          b("search"),
+		Refresh:         b("refresh"),
+		ToggleDismissed: b("toggle_dismissed"),
+		Stats:           b("stats"),
+		Control:         b("control"),
+		Timeline:        b("timeline"),
+		Calendar:        b("calendar"),
+		NextView:        b("next_view"),
+		PrevView:        b("prev_view"),
+		TogglePreview:   b("preview"),
+		GrowList:        b("grow_list"),
+		ShrinkList:      b("shrink_list"),
+		Mark:            b("mark"),
+		MarkRange:       b("mark_range"),
+		MarkAll:         b("mark_all"),
+		Quit:            b("quit"),
+		Help:            b("help"),
+	}
+}
+
+// shortKey returns the first key of a binding, for the keybind bar.
+func shortKey(b key.Binding) string {
+	if keys := b.Keys(); len(keys) > 0 {
+		return config.KeyName(keys[0])
 	}
+	return ""
+}
+
+// listKeyMap returns the list's key bindings with navigation taken from
+// keys. Filtering and help are bound to the TUI's search and help keys,
+// which are handled before the list sees them, so no other key starts the
+// list's own filter.
+func listKeyMap(keys KeyMap) list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.CursorUp = keys.Up
+	km.CursorDown = keys.Down
+	km.PrevPage = withKeys(keys.PageUp, "left")
+	km.NextPage = withKeys(keys.PageDown, "right")
+	km.GoToStart = keys.Home
+	km.GoToEnd = keys.End
+	km.Filter = keys.Search
+	km.ShowFullHelp = keys.Help
+	return km
+}
+
+// withKeys returns b with extra keys added.
+func withKeys(b key.Binding, extra ...string) key.Binding {
+	b.SetKeys(append(slices.Clone(b.Keys()), extra...)...)
+	return b
 }
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/config"
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func TestConfiguredKeys(t *testing.T) {
+	s := store.NewStore(nil)
+	t.Cleanup(func() { _ = s.Close() })
+	require.NoError(t, s.AddBatch([]model.Notification{
+		{HistuiID: "k0", Summary: "first", Timestamp: 2},
+		{HistuiID: "k1", Summary: "second", Timestamp: 1},
+	}))
+
+	cfg := config.DefaultConfig()
+	cfg.TUI.Keys.Preset = "emacs"
+	cfg.TUI.Keys.Dismiss = []string{"x"}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, s)
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = update(t, m, loadNotificationsMsg{})
+
+	// Navigation reaches the list
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlN})
+	assert.Equal(t, 1, m.list.Index())
+
+	// The old key does nothing; the new one dismisses
+	m = update(t, m, runes("d"))
+	assert.False(t, s.GetByID("k1").IsDismissed())
+	m = update(t, m, runes("x"))
+	assert.True(t, s.GetByID("k1").IsDismissed())
+
+	// The bar and help show the configured keys
+	assert.Contains(t, stripANSI(m.buildKeybindBar(200, "list")), "x dismiss")
+	assert.Contains(t, stripANSI(m.viewHelpKeybindings()), "ctrl+p/up, ctrl+n/down")
+}
+
+func TestNewPalette(t *testing.T) {
+	p := newPalette(map[string]string{"header": "#ff0000"})
+	assert.Equal(t, lipgloss.Color("#ff0000"), p.Header)
+	assert.Equal(t, defaultPalette().Title, p.Title)
+}
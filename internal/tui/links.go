@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// focused returns the notification the link and code keys act on: the one
+// open in the detail view, or the highlighted one in the list.
+func (m Model) focused() (model.Notification, bool) {
+	if m.mode == ModeDetail && m.selected != nil {
+		return *m.selected, true
+	}
+	item, ok := m.list.SelectedItem().(notificationItem)
+	return item.notification, ok
+}
+
+// notificationLinks returns the URLs in n followed by its email addresses as
+// mailto: links.
+func notificationLinks(n model.Notification) []string {
+	links := n.URLs()
+	for _, addr := range n.Emails() {
+		links = append(links, "mailto:"+addr)
+	}
+	return links
+}
+
+// openLinks opens the focused notification's link, or a picker if it has
+// more than one.
+func (m Model) openLinks() (tea.Model, tea.Cmd) {
+	n, ok := m.focused()
+	if !ok {
+		return m, nil
+	}
+	links := notificationLinks(n)
+	switch len(links) {
+	case 0:
+		return m, func() tea.Msg {
+			return statusMsg{text: "No links in this notification", isErr: true}
+		}
+	case 1:
+		return m, openLinkCmd(links[0])
+	}
+
+	m.links = links
+	m.linkIndex = 0
+	m.linksFrom = m.mode
+	m.mode = ModeLinks
+	return m, nil
+}
+
+// copyCode copies the first one-time code in the focused notification.
+func (m Model) copyCode() (tea.Model, tea.Cmd) {
+	n, ok := m.focused()
+	if !ok {
+		return m, nil
+	}
+	codes := n.Codes()
+	if len(codes) == 0 {
+		return m, func() tea.Msg {
+			return statusMsg{text: "No code in this notification", isErr: true}
+		}
+	}
+	return m, m.copyToClipboard(codes[0])
+}
+
+// openLinkCmd opens a link with the desktop's default handler.
+func openLinkCmd(link string) tea.Cmd {
+	return func() tea.Msg {
+		if err := openURL(link); err != nil {
+			return statusMsg{text: "Failed to open link: " + err.Error(), isErr: true}
+		}
+		return statusMsg{text: "Opened " + link, isErr: false}
+	}
+}
+
+// closeLinks leaves the picker for the mode it was opened from.
+func (m Model) closeLinks() Model {
+	m.mode = m.linksFrom
+	m.links = nil
+	return m
+}
+
+// handleLinksKey handles keys in the link picker. Digits open a link
+// directly.
+func (m Model) handleLinksKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		return m.closeLinks(), nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.linkIndex > 0 {
+			m.linkIndex--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.linkIndex < len(m.links)-1 {
+			m.linkIndex++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		link := m.links[m.linkIndex]
+		return m.closeLinks(), openLinkCmd(link)
+	}
+
+	if s := msg.String(); len(s) == 1 && s[0] >= '1' && s[0] <= '9' {
+		if i := int(s[0] - '1'); i < len(m.links) {
+			link := m.links[i]
+			return m.closeLinks(), openLinkCmd(link)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewLinks() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.colors.Header)
+
+	s := titleStyle.Render("Open link") + "\n\n"
+	for i, link := range m.links {
+		num := "   "
+		if i < 9 {
+			num = fmt.Sprintf("%d. ", i+1)
+		}
+		line := fg(m.colors.Key).Render(num) + link
+		if i == m.linkIndex {
+			line = fg(m.colors.Selected).Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		s += line + "\n"
+	}
+	return s + "\n" + m.buildKeybindBar(m.width, "links")
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// recordLinks replaces openURL for the test, returning the links opened.
+func recordLinks(t *testing.T) *[]string {
+	t.Helper()
+	var opened []string
+	orig := openURL
+	openURL = func(link string) error {
+		opened = append(opened, link)
+		return nil
+	}
+	t.Cleanup(func() { openURL = orig })
+	return &opened
+}
+
+// press sends a key and runs the command it returns, if any.
+func press(t *testing.T, m Model, msg tea.KeyMsg) (Model, tea.Msg) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	if cmd == nil {
+		return next.(Model), nil
+	}
+	return next.(Model), cmd()
+}
+
+func newLinksTestModel(t *testing.T, body string) Model {
+	t.Helper()
+	s := store.NewStore(nil)
+	t.Cleanup(func() { _ = s.Close() })
+	require.NoError(t, s.Add(model.Notification{
+		HistuiID: "l0", AppName: "test", Summary: "Links", Body: body, Timestamp: time.Now().Unix(),
+	}))
+	m := New(nil, s)
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	return update(t, m, loadNotificationsMsg{})
+}
+
+func TestLinks_OpenSingle(t *testing.T) {
+	opened := recordLinks(t)
+	m := newLinksTestModel(t, `Read <a href="https://example.com/post">the post</a>`)
+
+	m, msg := press(t, m, runes("o"))
+	assert.Equal(t, ModeList, m.mode)
+	assert.Equal(t, []string{"https://example.com/post"}, *opened)
+	assert.Equal(t, statusMsg{text: "Opened https://example.com/post"}, msg)
+}
+
+func TestLinks_Picker(t *testing.T) {
+	opened := recordLinks(t)
+	m := newLinksTestModel(t, "See https://a.example and https://b.example or ask help@c.example")
+
+	// From the detail view, which lists the links
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, m.viewport.View(), "2. https://b.example")
+
+	m, _ = press(t, m, runes("o"))
+	require.Equal(t, ModeLinks, m.mode)
+	assert.Contains(t, m.View(), "3. mailto:help@c.example")
+
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ModeDetail, m.mode, "back to where the picker opened")
+
+	m, _ = press(t, m, runes("o"))
+	m, _ = press(t, m, runes("3"))
+	m, _ = press(t, m, runes("o"))
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ModeDetail, m.mode)
+	assert.Equal(t, []string{"https://b.example", "mailto:help@c.example"}, *opened)
+}
+
+func TestLinks_CopyCodeWithoutCode(t *testing.T) {
+	opened := recordLinks(t)
+	m := newLinksTestModel(t, "Nothing to see")
+
+	_, msg := press(t, m, runes("y"))
+	assert.Equal(t, statusMsg{text: "No code in this notification", isErr: true}, msg)
+	_, msg = press(t, m, runes("o"))
+	assert.Equal(t, statusMsg{text: "No links in this notification", isErr: true}, msg)
+	assert.Empty(t, *opened)
+}
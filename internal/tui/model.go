@@ -22,6 +22,8 @@ import (
 	"github.com/jmylchreest/histui/internal/adapter/input"
 	"github.com/jmylchreest/histui/internal/config"
 	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/icons"
+	"github.com/jmylchreest/histui/internal/markup"
 	"github.com/jmylchreest/histui/internal/model"
 	"github.com/jmylchreest/histui/internal/store"
 )
@@ -34,6 +36,22 @@ const (
 	ModeDetail
 	ModeSearch
 	ModeHelp
+	ModeStats
+	ModePrompt
+	ModeLinks
+	ModeControl
+	ModeCalendar
+)
+
+// promptKind identifies what the single-line prompt in ModePrompt edits.
+type promptKind int
+
+const (
+	promptSnooze promptKind = iota
+	promptTag
+	promptNote
+	promptExport
+	promptDnD
 )
 
 // Model is the main TUI model.
@@ -49,20 +67,50 @@ type Model struct {
 	list        list.Model
 	viewport    viewport.Model
 	searchInput textinput.Model
+	promptInput textinput.Model
 	help        help.Model
 
 	// State
 	notifications []model.Notification
 	selected      *model.Notification
 	searchQuery   string
+	promptKind    promptKind
+	promptTargets []model.Notification // Notifications being edited in ModePrompt
+	sel           *selection           // Notifications marked for bulk actions
 	showDismissed bool
 	width         int
 	height        int
 	ready         bool
 	helpPage      int // 0 = keybindings, 1 = filter reference
 
-	// Key bindings
-	keys KeyMap
+	// Link picker, for notifications with more than one link
+	links     []string
+	linkIndex int
+	linksFrom Mode // Mode to return to
+
+	// Saved views from config, shown as tabs ("" = all notifications)
+	views     []string
+	viewIndex int
+
+	// Detail preview beside or below the list
+	split      bool
+	splitRatio int // Percentage of the screen given to the list
+	preview    viewport.Model
+	previewID  string // histui_id shown in the preview
+
+	// Timeline grouping and the time window picked on the heatmap
+	timeline bool
+	window   *timeWindow
+	calDay   int // Heatmap cursor: days before today
+	calHour  int // Heatmap cursor: hour, or calendarDayColumn for the day
+
+	// Do Not Disturb and histuid status for the header and control page
+	showStatus bool
+	daemon     *daemonStatus // nil until first loaded
+
+	// Key bindings and colors from config
+	keys   KeyMap
+	colors palette
 
 	// Status message
 	statusMsg string
@@ -76,6 +124,7 @@ type Model struct {
 type notificationItem struct {
 	notification model.Notification
 	index        int
+	query        string // Text search to highlight
 }
 
 func (i notificationItem) Title() string {
@@ -83,25 +132,42 @@ func (i notificationItem) Title() string {
 }
 
 func (i notificationItem) Description() string {
-	return fmt.Sprintf("[%s] %s - %s",
-		i.notification.AppName,
-		i.notification.RelativeTime(),
-		i.notification.BodyTruncated(50))
+	n := i.notification
+	n.Body = markup.Strip(n.Body)
+	desc := fmt.Sprintf("[%s] %s - %s",
+		n.AppName,
+		n.RelativeTime(),
+		n.BodyTruncated(50))
+	for _, tag := range i.notification.HistuiTags {
+		desc += " #" + tag
+	}
+	return desc
 }
 
 func (i notificationItem) FilterValue() string {
-	return i.notification.Summary + " " + i.notification.Body + " " + i.notification.AppName
+	return i.notification.Summary + " " + i.notification.Body + " " + i.notification.AppName +
+		" " + strings.Join(i.notification.HistuiTags, " ") + " " + i.notification.HistuiNote
 }
 
 // notificationDelegate is a custom list delegate for styling notifications.
 type notificationDelegate struct {
 	list.DefaultDelegate
+	sel    *selection
+	colors palette
 }
 
 // newNotificationDelegate creates a new notification delegate.
-func newNotificationDelegate() notificationDelegate {
+func newNotificationDelegate(sel *selection, colors palette) notificationDelegate {
 	d := list.NewDefaultDelegate()
-	return notificationDelegate{DefaultDelegate: d}
+	d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(colors.Title)
+	d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(colors.Description)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
+		Foreground(colors.Selected).
+		BorderForeground(colors.Selected)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.
+		Foreground(colors.SelectedDescription).
+		BorderForeground(colors.Selected)
+	return notificationDelegate{DefaultDelegate: d, sel: sel, colors: colors}
 }
 
 // Render renders a list item with custom styling for dismissed notifications.
@@ -116,6 +182,7 @@ func (d notificationDelegate) Render(w io.Writer, m list.Model, index int, item
 	// Check if this item is selected
 	isSelected := index == m.Index()
 	isDismissed := ni.notification.IsDismissed()
+	isMarked := d.sel != nil && d.sel.contains(ni.notification.HistuiID, index, m.Index())
 
 	// Get item width from the list
 	itemWidth := m.Width() - d.Styles.NormalTitle.GetHorizontalPadding()
@@ -127,14 +194,14 @@ func (d notificationDelegate) Render(w io.Writer, m list.Model, index int, item
 		// Dismissed: dimmed/gray color
 		if isSelected {
 			titleStyle = d.Styles.SelectedTitle.
-				Foreground(lipgloss.Color("8"))
+				Foreground(d.colors.Dismissed)
 			descStyle = d.Styles.SelectedDesc.
-				Foreground(lipgloss.Color("8"))
+				Foreground(d.colors.Dismissed)
 		} else {
 			titleStyle = d.Styles.NormalTitle.
-				Foreground(lipgloss.Color("8"))
+				Foreground(d.colors.Dismissed)
 			descStyle = d.Styles.NormalDesc.
-				Foreground(lipgloss.Color("8"))
+				Foreground(d.colors.Dismissed)
 		}
 	} else {
 		// Normal: use default delegate styles
@@ -149,9 +216,18 @@ func (d notificationDelegate) Render(w io.Writer, m list.Model, index int, item
 
 	// Build title with optional prefix
 	title := ni.Title()
-	if isDismissed {
+	if ni.notification.IsSnoozed() {
+		title = "[z] " + title
+	} else if isDismissed {
 		title = "[d] " + title
 	}
+	if ni.notification.IsStarred() {
+		title = "★ " + title
+	}
+	if isMarked {
+		title = "● " + title
+		titleStyle = titleStyle.Bold(true).Foreground(d.colors.Marked)
+	}
 
 	// Truncate if needed
 	if itemWidth > 0 && len(title) > itemWidth {
@@ -164,17 +240,31 @@ func (d notificationDelegate) Render(w io.Writer, m list.Model, index int, item
 	}
 
 	// Render using the same structure as DefaultDelegate
-	_, _ = fmt.Fprint(w, titleStyle.Render(title))
+	_, _ = fmt.Fprint(w, highlight(title, ni.query, titleStyle, d.colors.Key))
 	_, _ = fmt.Fprint(w, "\n")
-	_, _ = fmt.Fprint(w, descStyle.Render(desc))
+	_, _ = fmt.Fprint(w, highlight(desc, ni.query, descStyle, d.colors.Key))
 }
 
 // New creates a new TUI model.
 func New(cfg *config.Config, s *store.Store) Model {
 	// Initialize components with custom delegate for styling
-	delegate := newNotificationDelegate()
+	keys := DefaultKeyMap()
+	colors := defaultPalette()
+	if cfg != nil {
+		// Validated when the config was loaded
+		if bindings, err := cfg.TUI.Keys.Bindings(); err == nil {
+			keys = NewKeyMap(bindings)
+		}
+		if c, err := cfg.TUI.Colors.Colors(); err == nil {
+			colors = newPalette(c)
+		}
+	}
+
+	sel := newSelection()
+	delegate := newNotificationDelegate(sel, colors)
 	l := list.New(nil, delegate, 0, 0)
-	l.Title = "Notification History"
+	l.KeyMap = listKeyMap(keys)
+	l.Title = listTitle
 	l.SetShowStatusBar(true)
 	l.SetShowHelp(false)
 	l.SetFilteringEnabled(true)
@@ -184,9 +274,10 @@ func New(cfg *config.Config, s *store.Store) Model {
 	searchInput.Placeholder = "Search or filter (e.g., app=discord)..."
 	searchInput.CharLimit = 100
 
-	h := help.New()
+	promptInput := textinput.New()
+	promptInput.CharLimit = 200
 
-	keys := DefaultKeyMap()
+	h := help.New()
 
 	m := Model{
 		cfg:         cfg,
@@ -194,8 +285,23 @@ func New(cfg *config.Config, s *store.Store) Model {
 		mode:        ModeList,
 		list:        l,
 		searchInput: searchInput,
+		promptInput: promptInput,
 		help:        h,
 		keys:        keys,
+		colors:      colors,
+		views:       []string{""},
+		sel:         sel,
+		splitRatio:  config.DefaultSplitRatio,
+		preview:     viewport.New(0, 0),
+		showStatus:  cfg == nil || cfg.TUI.Status,
+	}
+	if cfg != nil {
+		m.views = append(m.views, cfg.ViewNames()...)
+		m.split = cfg.TUI.Split
+		m.timeline = cfg.TUI.Timeline
+		if cfg.TUI.SplitRatio != 0 {
+			m.splitRatio = cfg.TUI.SplitRatio
+		}
 	}
 
 	// Subscribe to store changes if available
@@ -208,10 +314,11 @@ func New(cfg *config.Config, s *store.Store) Model {
 
 // Init initializes the TUI.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.loadNotifications,
-		m.watchForChanges,
-	)
+	cmds := []tea.Cmd{m.loadNotifications, m.watchForChanges}
+	if m.showStatus {
+		cmds = append(cmds, pollDaemon(0))
+	}
+	return tea.Batch(cmds...)
 }
 
 // loadNotifications fetches notifications from the store.
@@ -235,21 +342,34 @@ type refreshMsg struct{}
 
 // Update handles messages and updates the model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		// Follow the highlighted notification in the preview
+		return nm.syncPreview(), cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
 
-		// Update component sizes
-		m.list.SetSize(msg.Width, msg.Height-2)
+		m = m.resize()
 		m.viewport = viewport.New(msg.Width, msg.Height-4)
 		m.viewport.YPosition = 2
+		m.viewport.KeyMap.Up = m.keys.Up
+		m.viewport.KeyMap.Down = m.keys.Down
 
 		return m, nil
 
@@ -275,6 +395,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusErr = false
 		return m, nil
 
+	case daemonStatusMsg:
+		m = m.setDaemonStatus(msg.status)
+		if msg.poll {
+			return m, pollDaemon(daemonPollInterval)
+		}
+		return m, nil
+
+	case controlResultMsg:
+		m = m.setDaemonStatus(msg.status)
+		return m.update(msg.result)
+
 	case copyResultMsg:
 		if msg.err != nil {
 			return m, func() tea.Msg {
@@ -292,7 +423,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.list, cmd = m.list.Update(msg)
 		cmds = append(cmds, cmd)
-	case ModeDetail:
+	case ModeDetail, ModeStats:
 		var cmd tea.Cmd
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
@@ -300,6 +431,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.searchInput, cmd = m.searchInput.Update(msg)
 		cmds = append(cmds, cmd)
+	case ModePrompt:
+		var cmd tea.Cmd
+		m.promptInput, cmd = m.promptInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -318,6 +453,11 @@ type copyResultMsg struct {
 
 // handleKey handles key presses.
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The prompt takes all typed keys
+	if m.mode == ModePrompt {
+		return m.handlePromptKey(msg)
+	}
+
 	// Global keys
 	switch {
 	case key.Matches(msg, m.keys.Quit):
@@ -339,6 +479,16 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDetailKey(msg)
 	case ModeSearch:
 		return m.handleSearchKey(msg)
+	case ModePrompt:
+		return m.handlePromptKey(msg)
+	case ModeStats:
+		return m.handleStatsKey(msg)
+	case ModeLinks:
+		return m.handleLinksKey(msg)
+	case ModeControl:
+		return m.handleControlKey(msg)
+	case ModeCalendar:
+		return m.handleCalendarKey(msg)
 	case ModeHelp:
 		if key.Matches(msg, m.keys.Back) {
 			m.mode = ModeList
@@ -365,17 +515,11 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Enter):
-		if item, ok := m.list.SelectedItem().(notificationItem); ok {
-			m.selected = &item.notification
-			m.mode = ModeDetail
-			m.viewport.SetContent(m.renderDetail(item.notification))
-			m.viewport.GotoTop()
-		}
-		return m, nil
+		return m.openDetail()
 
 	case key.Matches(msg, m.keys.Copy):
 		if item, ok := m.list.SelectedItem().(notificationItem); ok {
-			return m, m.copyToClipboard(item.notification.Body)
+			return m, m.copyToClipboard(markup.Strip(item.notification.Body))
 		}
 		return m, nil
 
@@ -385,16 +529,14 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.CopyCode):
+		return m.copyCode()
+
+	case key.Matches(msg, m.keys.OpenLink):
+		return m.openLinks()
+
 	case key.Matches(msg, m.keys.CopyAllJSON):
-		// Get currently visible notifications
-		items := m.list.Items()
-		notifications := make([]model.Notification, 0, len(items))
-		for _, item := range items {
-			if ni, ok := item.(notificationItem); ok {
-				notifications = append(notifications, ni.notification)
-			}
-		}
-		data, err := json.MarshalIndent(notifications, "", "  ")
+		data, err := json.MarshalIndent(m.exportTargets(), "", "  ")
 		if err != nil {
 			return m, func() tea.Msg {
 				return statusMsg{text: "Failed to marshal JSON: " + err.Error(), isErr: true}
@@ -403,15 +545,7 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, m.copyToClipboard(string(data))
 
 	case key.Matches(msg, m.keys.CopyAllYAML):
-		// Get currently visible notifications
-		items := m.list.Items()
-		notifications := make([]model.Notification, 0, len(items))
-		for _, item := range items {
-			if ni, ok := item.(notificationItem); ok {
-				notifications = append(notifications, ni.notification)
-			}
-		}
-		data, err := yaml.Marshal(notifications)
+		data, err := yaml.Marshal(m.exportTargets())
 		if err != nil {
 			return m, func() tea.Msg {
 				return statusMsg{text: "Failed to marshal YAML: " + err.Error(), isErr: true}
@@ -419,42 +553,122 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, m.copyToClipboard(string(data))
 
+	case key.Matches(msg, m.keys.Export):
+		if targets := m.exportTargets(); len(targets) > 0 {
+			return m.openPrompt(promptExport, targets)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Mark):
+		return m.toggleMark()
+
+	case key.Matches(msg, m.keys.MarkRange):
+		return m.toggleRange()
+
+	case key.Matches(msg, m.keys.MarkAll):
+		return m.markAll()
+
+	case key.Matches(msg, m.keys.Back):
+		// Clear the selection first, then a search kept from search mode,
+		// then the heatmap's time window
+		if len(m.sel.marked) > 0 || m.sel.anchor >= 0 {
+			m.sel.reset()
+			return m, nil
+		}
+		if m.searchQuery != "" {
+			m.searchQuery = ""
+			m.searchInput.SetValue("")
+			m.list.SetItems(m.buildListItems())
+		} else if m.window != nil {
+			m = m.setWindow(nil)
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Dismiss):
-		if item, ok := m.list.SelectedItem().(notificationItem); ok {
-			if m.store != nil {
-				n := item.notification
-				if n.IsDismissed() {
-					// Undismiss
-					n.Undismiss()
-					_ = m.store.Update(n)
-					m.notifications = m.fetchNotifications()
-					m.list.SetItems(m.buildListItems())
-					return m, func() tea.Msg {
-						return statusMsg{text: "Notification restored", isErr: false}
-					}
-				}
-				// Dismiss
-				_ = m.store.Dismiss(item.notification.HistuiID)
-				m.notifications = m.fetchNotifications()
-				m.list.SetItems(m.buildListItems())
-				return m, func() tea.Msg {
-					return statusMsg{text: "Notification dismissed", isErr: false}
-				}
+		targets := m.targets()
+		// Restore only if every target is already dismissed
+		restore := len(targets) > 0
+		for _, n := range targets {
+			if !n.IsDismissed() {
+				restore = false
+				break
 			}
 		}
-		return m, nil
+		if restore {
+			return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+				tx.Undismiss(n.HistuiID)
+			}, countStatus(len(targets), "restored"))
+		}
+		return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+			tx.Dismiss(n.HistuiID)
+		}, countStatus(len(targets), "dismissed"))
 
 	case key.Matches(msg, m.keys.HardDelete):
-		if item, ok := m.list.SelectedItem().(notificationItem); ok {
-			if m.store != nil {
-				_ = m.store.DeleteWithTombstone(item.notification.HistuiID)
-				m.notifications = m.fetchNotifications()
-				m.list.SetItems(m.buildListItems())
+		targets := m.targets()
+		return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+			tx.DeleteWithTombstone(n.HistuiID)
+		}, countStatus(len(targets), "deleted permanently"))
+
+	case key.Matches(msg, m.keys.MarkSeen):
+		targets := m.targets()
+		return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+			tx.MarkSeen(n.HistuiID)
+		}, countStatus(len(targets), "marked seen"))
+
+	case key.Matches(msg, m.keys.Snooze):
+		targets := m.targets()
+		if len(targets) == 0 || m.store == nil {
+			return m, nil
+		}
+		// Snoozed items are only listed when showing all; z restores them
+		pending := true
+		for _, n := range targets {
+			if !n.HasPendingSnooze() {
+				pending = false
+				break
 			}
 		}
-		return m, func() tea.Msg {
-			return statusMsg{text: "Notification deleted permanently", isErr: false}
+		if pending {
+			status := "Snooze cancelled"
+			if len(targets) > 1 {
+				status = fmt.Sprintf("%d snoozes cancelled", len(targets))
+			}
+			return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+				tx.Unsnooze(n.HistuiID)
+			}, status)
+		}
+		return m.openPrompt(promptSnooze, targets)
+
+	case key.Matches(msg, m.keys.Star):
+		targets := m.targets()
+		// Unstar only if every target is already starred
+		starred := len(targets) > 0
+		for _, n := range targets {
+			if !n.IsStarred() {
+				starred = false
+				break
+			}
+		}
+		status := "Starred"
+		if starred {
+			status = "Unstarred"
 		}
+		return m.applyToTargets(targets, func(tx *store.Tx, n model.Notification) {
+			tx.SetStarred(n.HistuiID, !starred)
+		}, status)
+
+	case key.Matches(msg, m.keys.Tag):
+		if targets := m.targets(); len(targets) > 0 && m.store != nil {
+			return m.openPrompt(promptTag, targets)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Note):
+		// Notes are personal to one notification, so they ignore the selection
+		if item, ok := m.list.SelectedItem().(notificationItem); ok && m.store != nil {
+			return m.openPrompt(promptNote, []model.Notification{item.notification})
+		}
+		return m, nil
 
 	case key.Matches(msg, m.keys.ToggleDismissed):
 		m.showDismissed = !m.showDismissed
@@ -479,6 +693,36 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.Refresh):
 		return m, m.loadNotifications
+
+	case key.Matches(msg, m.keys.NextView):
+		return m.switchView(1)
+
+	case key.Matches(msg, m.keys.PrevView):
+		return m.switchView(-1)
+
+	case key.Matches(msg, m.keys.TogglePreview):
+		return m.togglePreview()
+
+	case key.Matches(msg, m.keys.GrowList):
+		return m.resizeSplit(splitStep)
+
+	case key.Matches(msg, m.keys.ShrinkList):
+		return m.resizeSplit(-splitStep)
+
+	case key.Matches(msg, m.keys.Stats):
+		m.mode = ModeStats
+		m.viewport.SetContent(m.renderStats())
+		m.viewport.GotoTop()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Control):
+		return m.openControl()
+
+	case key.Matches(msg, m.keys.Timeline):
+		return m.toggleTimeline()
+
+	case key.Matches(msg, m.keys.Calendar):
+		return m.openCalendar()
 	}
 
 	// Pass to list
@@ -487,6 +731,17 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openDetail shows the highlighted notification in the detail view.
+func (m Model) openDetail() (tea.Model, tea.Cmd) {
+	if item, ok := m.list.SelectedItem().(notificationItem); ok {
+		m.selected = &item.notification
+		m.mode = ModeDetail
+		m.viewport.SetContent(m.renderDetail(item.notification))
+		m.viewport.GotoTop()
+	}
+	return m, nil
+}
+
 // handleDetailKey handles keys in detail mode.
 func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
@@ -497,7 +752,7 @@ func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.Copy):
 		if m.selected != nil {
-			return m, m.copyToClipboard(m.selected.Body)
+			return m, m.copyToClipboard(markup.Strip(m.selected.Body))
 		}
 		return m, nil
 
@@ -507,6 +762,12 @@ func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.CopyCode):
+		return m.copyCode()
+
+	case key.Matches(msg, m.keys.OpenLink):
+		return m.openLinks()
+
 	case key.Matches(msg, m.keys.Search):
 		// Go to search mode, reset search and show full list
 		m.selected = nil
@@ -538,14 +799,16 @@ func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyEnter:
 		// Enter opens the selected notification (like in list mode)
-		if item, ok := m.list.SelectedItem().(notificationItem); ok {
-			m.selected = &item.notification
-			m.mode = ModeDetail
+		if _, ok := m.list.SelectedItem().(notificationItem); ok {
 			m.searchInput.Blur()
-			m.viewport.SetContent(m.renderDetail(item.notification))
-			m.viewport.GotoTop()
 		}
-		return m, nil
+		return m.openDetail()
+
+	case tea.KeyCtrlA:
+		// Keep the search and mark everything it matches
+		m.mode = ModeList
+		m.searchInput.Blur()
+		return m.markAll()
 
 	case tea.KeyUp, tea.KeyDown:
 		// Allow navigating the list while searching
@@ -565,6 +828,175 @@ func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openPrompt switches to ModePrompt to edit or export the given notifications.
+func (m Model) openPrompt(kind promptKind, targets []model.Notification) (tea.Model, tea.Cmd) {
+	m.promptKind = kind
+	m.promptTargets = targets
+	m.promptInput.SetValue("")
+	switch kind {
+	case promptSnooze:
+		m.promptInput.Placeholder = "30m, 2h, 15:00, 2006-01-02 15:04"
+	case promptTag:
+		m.promptInput.Placeholder = "work followup -oldtag"
+	case promptNote:
+		m.promptInput.Placeholder = "Note (empty to clear)"
+		m.promptInput.SetValue(targets[0].HistuiNote)
+		m.promptInput.CursorEnd()
+	case promptExport:
+		m.promptInput.Placeholder = "File (.json, .yaml)"
+		m.promptInput.SetValue(defaultExportPath(time.Now()))
+		m.promptInput.CursorEnd()
+	case promptDnD:
+		m.promptInput.Placeholder = "30m, 2h, 15:00 (empty: until turned off)"
+	}
+	m.promptInput.Focus()
+	m.mode = ModePrompt
+	return m, textinput.Blink
+}
+
+// handlePromptKey handles keys in the snooze, tag, note and export prompts.
+func (m Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.mode = ModeList
+		if m.promptKind == promptDnD {
+			m.mode = ModeControl
+		}
+		m.promptInput.Blur()
+		m.promptTargets = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		targets := m.promptTargets
+		value := m.promptInput.Value()
+
+		var apply func(tx *store.Tx) error
+		var status string
+		switch m.promptKind {
+		case promptSnooze:
+			until, err := core.ParseWhen(value, time.Now())
+			if err != nil {
+				return m, func() tea.Msg {
+					return statusMsg{text: err.Error(), isErr: true}
+				}
+			}
+			apply = func(tx *store.Tx) error {
+				for _, n := range targets {
+					tx.Snooze(n.HistuiID, until)
+				}
+				return nil
+			}
+			status = "Snoozed until " + until.Format("Mon 15:04")
+			if len(targets) > 1 {
+				status = fmt.Sprintf("%d notifications snoozed until %s", len(targets), until.Format("Mon 15:04"))
+			}
+
+		case promptTag:
+			// Space-separated tags; a leading "-" removes the tag
+			var add, remove []string
+			for _, field := range strings.Fields(value) {
+				target := &add
+				if strings.HasPrefix(field, "-") {
+					target = &remove
+					field = field[1:]
+				}
+				tag, err := model.NormalizeTag(field)
+				if err != nil {
+					return m, func() tea.Msg {
+						return statusMsg{text: err.Error(), isErr: true}
+					}
+				}
+				*target = append(*target, tag)
+			}
+			apply = func(tx *store.Tx) error {
+				for _, n := range targets {
+					for _, tag := range add {
+						tx.Tag(n.HistuiID, tag)
+					}
+					for _, tag := range remove {
+						tx.Untag(n.HistuiID, tag)
+					}
+				}
+				return nil
+			}
+			status = "Tags updated"
+			if len(targets) > 1 {
+				status = fmt.Sprintf("Tags updated on %d notifications", len(targets))
+			}
+
+		case promptNote:
+			note := strings.TrimSpace(value)
+			apply = func(tx *store.Tx) error {
+				tx.SetNote(targets[0].HistuiID, note)
+				return nil
+			}
+			status = "Note saved"
+			if note == "" {
+				status = "Note cleared"
+			}
+
+		case promptExport:
+			path := strings.TrimSpace(value)
+			if path == "" {
+				path = defaultExportPath(time.Now())
+			}
+			if err := exportNotifications(targets, path); err != nil {
+				return m, func() tea.Msg {
+					return statusMsg{text: "Export failed: " + err.Error(), isErr: true}
+				}
+			}
+			m.mode = ModeList
+			m.promptInput.Blur()
+			m.promptTargets = nil
+			m.sel.reset()
+			return m, func() tea.Msg {
+				return statusMsg{text: fmt.Sprintf("Exported %d notifications to %s", len(targets), path), isErr: false}
+			}
+
+		case promptDnD:
+			var d time.Duration
+			if value = strings.TrimSpace(value); value != "" {
+				now := time.Now()
+				until, err := core.ParseWhen(value, now)
+				if err != nil {
+					return m, func() tea.Msg {
+						return statusMsg{text: err.Error(), isErr: true}
+					}
+				}
+				d = until.Sub(now)
+			}
+			m.mode = ModeControl
+			m.promptInput.Blur()
+			return m, setDnD(true, d)
+		}
+
+		m.mode = ModeList
+		m.promptInput.Blur()
+		m.promptTargets = nil
+		return m.applyBatch(apply, status)
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// applyBatch writes changes to the store, refreshes the list and reports status.
+// The selection is cleared once the changes are written.
+func (m Model) applyBatch(fn func(tx *store.Tx) error, status string) (tea.Model, tea.Cmd) {
+	if err := m.store.Batch(fn); err != nil {
+		return m, func() tea.Msg {
+			return statusMsg{text: "Update failed: " + err.Error(), isErr: true}
+		}
+	}
+	m.sel.reset()
+	m.notifications = m.fetchNotifications()
+	m.list.SetItems(m.buildListItems())
+	return m, func() tea.Msg {
+		return statusMsg{text: status, isErr: false}
+	}
+}
+
 // fetchNotifications gets notifications from the store or directly from dunst.
 func (m Model) fetchNotifications() []model.Notification {
 	if m.store != nil {
@@ -573,21 +1005,35 @@ func (m Model) fetchNotifications() []model.Notification {
 	return nil
 }
 
-// buildListItems creates list items from current notifications.
-func (m Model) buildListItems() []list.Item {
+// visibleNotifications returns the notifications the active view shows,
+// without dismissed and snoozed ones unless showDismissed is true.
+func (m Model) visibleNotifications() []model.Notification {
 	notifications := m.notifications
 
-	// Filter out dismissed unless showDismissed is true
+	// Filter out dismissed and snoozed unless showDismissed is true
 	if !m.showDismissed {
 		var visible []model.Notification
 		for _, n := range notifications {
-			if !n.IsDismissed() {
+			if !n.IsDismissed() && !n.IsSnoozed() {
 				visible = append(visible, n)
 			}
 		}
 		notifications = visible
 	}
 
+	// Apply the active saved view
+	return m.applyActiveView(notifications)
+}
+
+// buildListItems creates list items from current notifications.
+func (m Model) buildListItems() []list.Item {
+	notifications := m.visibleNotifications()
+
+	// Limit to the window picked on the heatmap
+	if m.window != nil {
+		notifications = core.Filter(notifications, core.FilterOptions{After: m.window.start, Before: m.window.end})
+	}
+
 	// Apply search filter if active
 	if m.searchQuery != "" {
 		query := m.searchQuery
@@ -598,23 +1044,17 @@ func (m Model) buildListItems() []list.Item {
 			if expr, err := core.ParseFilter(query); err == nil {
 				notifications = core.FilterWithExpr(notifications, expr)
 			}
-		} else {
-			// Regular text search
-			var filtered []model.Notification
-			for _, n := range notifications {
-				if containsIgnoreCase(n.Summary, query) ||
-					containsIgnoreCase(n.Body, query) ||
-					containsIgnoreCase(n.AppName, query) {
-					filtered = append(filtered, n)
-				}
-			}
-			notifications = filtered
+		} else if strings.TrimSpace(query) != "" {
+			// Fuzzy text search, best matches first
+			notifications = core.Search(notifications, query)
+			core.Sort(notifications, core.SortOptions{Field: core.SortByRelevance, Order: core.SortDesc, Query: query})
 		}
 	}
 
+	query := m.textQuery()
 	items := make([]list.Item, len(notifications))
 	for i, n := range notifications {
-		items[i] = notificationItem{notification: n, index: i}
+		items[i] = notificationItem{notification: n, index: i, query: query}
 	}
 	return items
 }
@@ -632,9 +1072,11 @@ func isFilterExpression(query string) bool {
 			if len(parts) == 2 && len(strings.TrimSpace(parts[0])) > 0 {
 				field := strings.ToLower(strings.TrimSpace(parts[0]))
 				// Check if the field looks like a valid filter field
-				validFields := []string{"app", "summary", "body", "urgency", "category", "dismissed", "seen", "timestamp"}
-				for _, vf := range validFields {
-					if field == vf || strings.HasPrefix(field, vf+",") || strings.Contains(field, ","+vf) {
+				if _, err := core.LookupFilterField(field); err == nil {
+					return true
+				}
+				for _, f := range core.FilterFields() {
+					if strings.HasPrefix(field, f.Name+",") || strings.Contains(field, ","+f.Name) {
 						return true
 					}
 				}
@@ -655,34 +1097,60 @@ func (m Model) renderDetail(n model.Notification) string {
 	// Header
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("12"))
+		Foreground(m.colors.Header)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+		Foreground(m.colors.Label)
 
 	s += headerStyle.Render(n.Summary) + "\n\n"
 
 	// Metadata
-	s += labelStyle.Render("App: ") + n.AppName + "\n"
+	s += labelStyle.Render("App: ") + n.AppName
+	if name := icons.Default().NotificationAppName(&n); name != n.AppName {
+		s += " (" + name + ")"
+	}
+	s += "\n"
 	s += labelStyle.Render("Time: ") + n.RelativeTime() + "\n"
 	s += labelStyle.Render("Urgency: ") + n.UrgencyName + "\n"
 	if n.Category != "" {
 		s += labelStyle.Render("Category: ") + n.Category + "\n"
 	}
+	if n.IsStarred() {
+		s += labelStyle.Render("Starred: ") + "yes\n"
+	}
+	if len(n.HistuiTags) > 0 {
+		s += labelStyle.Render("Tags: ") + strings.Join(n.HistuiTags, ", ") + "\n"
+	}
+	if n.HistuiNote != "" {
+		s += labelStyle.Render("Note: ") + n.HistuiNote + "\n"
+	}
+	if len(n.HistuiRedacted) > 0 {
+		s += labelStyle.Render("Redacted: ") + strings.Join(n.HistuiRedacted, ", ")
+		if n.HistuiRedactAt > 0 {
+			s += " (kept until " + time.Unix(n.HistuiRedactAt, 0).Format("15:04") + ")"
+		}
+		s += "\n"
+	}
 
 	// Body
 	s += "\n" + labelStyle.Render("Body:") + "\n"
-	s += n.Body + "\n"
+	s += markup.ANSI(n.Body) + "\n"
+
+	// Links and codes found in the text, including dunst's urls field
+	if links := notificationLinks(n); len(links) > 0 {
+		s += "\n" + labelStyle.Render("Links:") + "\n"
+		for i, link := range links {
+			s += fmt.Sprintf("  %d. %s\n", i+1, link)
+		}
+	}
+	if codes := n.Codes(); len(codes) > 0 {
+		s += "\n" + labelStyle.Render("Codes: ") + strings.Join(codes, ", ") + "\n"
+	}
 
 	// Extensions
-	if n.Extensions != nil {
+	if n.Extensions != nil && n.Extensions.Progress > 0 {
 		s += "\n" + labelStyle.Render("Extensions:") + "\n"
-		if n.Extensions.URLs != "" {
-			s += "  URLs: " + n.Extensions.URLs + "\n"
-		}
-		if n.Extensions.Progress > 0 {
-			s += fmt.Sprintf("  Progress: %d%%\n", n.Extensions.Progress)
-		}
+		s += fmt.Sprintf("  Progress: %d%%\n", n.Extensions.Progress)
 	}
 
 	return s
@@ -711,6 +1179,16 @@ func (m Model) View() string {
 		return m.viewSearch()
 	case ModeHelp:
 		return m.viewHelp()
+	case ModeStats:
+		return m.viewStats()
+	case ModePrompt:
+		return m.viewPrompt()
+	case ModeLinks:
+		return m.viewLinks()
+	case ModeControl:
+		return m.viewControl()
+	case ModeCalendar:
+		return m.viewCalendar()
 	default:
 		return ""
 	}
@@ -718,14 +1196,20 @@ func (m Model) View() string {
 
 func (m Model) viewList() string {
 	var s string
-	s += m.list.View()
+	if m.hasStatusHeader() {
+		s += m.renderStatusHeader() + "\n"
+	}
+	if m.hasViewTabs() {
+		s += m.renderViewTabs() + "\n"
+	}
+	s += m.listView()
 
 	// Status bar
 	if m.statusMsg != "" {
 		statusStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7"))
+			Foreground(m.colors.Status)
 		if m.statusErr {
-			statusStyle = statusStyle.Foreground(lipgloss.Color("9"))
+			statusStyle = statusStyle.Foreground(m.colors.Error)
 		}
 		s += "\n" + statusStyle.Render(m.statusMsg)
 	} else {
@@ -751,9 +1235,36 @@ func (m Model) viewSearch() string {
 
 	// Show search bar at top, then the filtered list, then keybinds
 	searchBar := "Search: " + m.searchInput.View() + " " +
-		lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(countStr)
+		fg(m.colors.Label).Render(countStr)
 
-	return searchBar + "\n" + m.list.View() + "\n" + m.buildKeybindBar(m.width, "search")
+	return searchBar + "\n" + m.statusHeaderLine() + m.listView() + "\n" + m.buildKeybindBar(m.width, "search")
+}
+
+func (m Model) viewPrompt() string {
+	var label string
+	switch m.promptKind {
+	case promptSnooze:
+		label = "Snooze until: "
+	case promptTag:
+		label = "Tags: "
+	case promptNote:
+		label = "Note: "
+	case promptExport:
+		label = fmt.Sprintf("Export %d to: ", len(m.promptTargets))
+	case promptDnD:
+		label = "Do Not Disturb for: "
+	}
+	if len(m.promptTargets) > 1 && m.promptKind != promptExport {
+		label = fmt.Sprintf("(%d selected) ", len(m.promptTargets)) + label
+	}
+	prompt := label + m.promptInput.View()
+	if m.statusErr && m.statusMsg != "" {
+		prompt += " " + fg(m.colors.Error).Render(m.statusMsg)
+	}
+	if m.promptKind == promptDnD {
+		return prompt + "\n\n" + m.renderControl()
+	}
+	return prompt + "\n" + m.statusHeaderLine() + m.listView() + "\n" + m.buildKeybindBar(m.width, "prompt")
 }
 
 func (m Model) viewHelp() string {
@@ -766,44 +1277,77 @@ func (m Model) viewHelp() string {
 func (m Model) viewHelpKeybindings() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("12"))
+		Foreground(m.colors.Header)
 
 	sectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+		Foreground(m.colors.Label)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("10"))
+		Foreground(m.colors.Key)
 
 	dimStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+		Foreground(m.colors.Label)
 
 	s := titleStyle.Render("Keyboard Shortcuts") + dimStyle.Render(" (1/2)") + "\n\n"
 
+	k := m.keys
+	line := func(keys, desc string) string {
+		return keyStyle.Render(fmt.Sprintf("  %-12s", keys)) + " " + desc + "\n"
+	}
+	pair := func(a, b key.Binding) string {
+		return a.Help().Key + ", " + b.Help().Key
+	}
+
 	s += sectionStyle.Render("Navigation") + "\n"
-	s += keyStyle.Render("  j/k, ↑/↓") + "     Move up/down\n"
-	s += keyStyle.Render("  g/G") + "          Go to top/bottom\n"
-	s += keyStyle.Render("  pgup/pgdn") + "    Page up/down\n"
+	s += line(pair(k.Up, k.Down), "Move up/down")
+	s += line(pair(k.Home, k.End), "Go to top/bottom")
+	s += line(pair(k.PageUp, k.PageDown), "Page up/down")
 	s += "\n"
 
 	s += sectionStyle.Render("Actions") + "\n"
-	s += keyStyle.Render("  enter") + "        View details\n"
-	s += keyStyle.Render("  c") + "            Copy body\n"
-	s += keyStyle.Render("  s") + "            Copy summary\n"
-	s += keyStyle.Render("  C") + "            Copy all as JSON\n"
-	s += keyStyle.Render("  alt+c") + "        Copy all as YAML\n"
-	s += keyStyle.Render("  d") + "            Dismiss/undismiss\n"
-	s += keyStyle.Render("  D") + "            Delete permanently\n"
-	s += keyStyle.Render("  a") + "            Toggle dismissed\n"
-	s += keyStyle.Render("  /") + "            Search/filter\n"
-	s += keyStyle.Render("  r") + "            Refresh\n"
+	s += line(k.Enter.Help().Key, "View details")
+	s += line(k.Copy.Help().Key, "Copy body")
+	s += line(k.CopySummary.Help().Key, "Copy summary")
+	s += line(k.CopyCode.Help().Key, "Copy one-time code")
+	s += line(k.OpenLink.Help().Key, "Open link or email address")
+	s += line(k.CopyAllJSON.Help().Key, "Copy marked (or all) as JSON")
+	s += line(k.CopyAllYAML.Help().Key, "Copy marked (or all) as YAML")
+	s += line(k.Export.Help().Key, "Export marked (or all) to a file")
+	s += line(k.Dismiss.Help().Key, "Dismiss/undismiss")
+	s += line(k.HardDelete.Help().Key, "Delete permanently")
+	s += line(k.MarkSeen.Help().Key, "Mark seen")
+	s += line(k.Snooze.Help().Key, "Snooze/cancel snooze")
+	s += line(k.Star.Help().Key, "Star/unstar")
+	s += line(k.Tag.Help().Key, "Add tags (-tag removes)")
+	s += line(k.Note.Help().Key, "Edit note")
+	s += line(k.ToggleDismissed.Help().Key, "Toggle dismissed/snoozed")
+	s += line(k.Search.Help().Key, "Search/filter")
+	s += line(k.Refresh.Help().Key, "Refresh")
+	s += line(k.Stats.Help().Key, "Statistics")
+	s += line(k.Control.Help().Key, "Do Not Disturb and popup controls")
+	s += line(k.Timeline.Help().Key, "Group the list by day")
+	s += line(k.Calendar.Help().Key, "Activity heatmap; enter shows a day or hour")
+	s += line(pair(k.NextView, k.PrevView), "Next/previous view")
+	s += line(k.TogglePreview.Help().Key, "Show/hide the detail preview")
+	s += line(pair(k.ShrinkList, k.GrowList), "Resize the preview")
+	s += "\n"
+
+	bulk := strings.Join([]string{shortKey(k.Dismiss), shortKey(k.HardDelete), shortKey(k.MarkSeen),
+		shortKey(k.Snooze), shortKey(k.Star), shortKey(k.Tag)}, ", ")
+	s += sectionStyle.Render("Selection") + dimStyle.Render(" ("+bulk+" apply to all marked)") + "\n"
+	s += line(k.Mark.Help().Key, "Mark/unmark")
+	s += line(k.MarkRange.Help().Key, "Start/end a marked range")
+	s += line(k.MarkAll.Help().Key, "Mark all listed")
+	s += line("ctrl+a", "Mark all search matches")
+	s += line(shortKey(k.Back), "Clear marks")
 	s += "\n"
 
 	s += sectionStyle.Render("General") + "\n"
-	s += keyStyle.Render("  ?") + "            This help\n"
-	s += keyStyle.Render("  esc") + "          Back\n"
-	s += keyStyle.Render("  q") + "            Quit\n"
+	s += line(k.Help.Help().Key, "This help")
+	s += line(k.Back.Help().Key, "Back")
+	s += line(k.Quit.Help().Key, "Quit")
 
-	s += "\n" + dimStyle.Render("←/→ or h/l: switch pages  ?/esc: close")
+	s += "\n" + dimStyle.Render("←/→ or h/l: switch pages  "+shortKey(m.keys.Help)+"/"+shortKey(m.keys.Back)+": close")
 
 	return s
 }
@@ -811,31 +1355,32 @@ func (m Model) viewHelpKeybindings() string {
 func (m Model) viewHelpFilters() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("12"))
+		Foreground(m.colors.Header)
 
 	sectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+		Foreground(m.colors.Label)
 
 	fieldStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("10"))
+		Foreground(m.colors.Key)
 
 	opStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("11"))
+		Foreground(m.colors.Marked)
 
 	dimStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+		Foreground(m.colors.Label)
 
 	s := titleStyle.Render("Filter Reference") + dimStyle.Render(" (2/2)") + "\n\n"
 
 	s += sectionStyle.Render("Fields") + "\n"
-	s += fieldStyle.Render("  app") + "        Application name\n"
-	s += fieldStyle.Render("  summary") + "    Notification title\n"
-	s += fieldStyle.Render("  body") + "       Notification body\n"
-	s += fieldStyle.Render("  urgency") + "    low, normal, critical\n"
-	s += fieldStyle.Render("  category") + "   Notification category\n"
-	s += fieldStyle.Render("  dismissed") + "  true/false\n"
-	s += fieldStyle.Render("  seen") + "       true/false\n"
-	s += fieldStyle.Render("  timestamp") + "  Duration (1h, 7d, 2w)\n"
+	fields := core.FilterFields()
+	width := len(core.ExtensionPrefix + "<key>")
+	for _, f := range fields {
+		width = max(width, len(f.Name))
+	}
+	for _, f := range fields {
+		s += fieldStyle.Render(fmt.Sprintf("  %-*s", width, f.Name)) + "  " + f.Description + dimStyle.Render(" · "+f.Type.Values()) + "\n"
+	}
+	s += fieldStyle.Render(fmt.Sprintf("  %-*s", width, core.ExtensionPrefix+"<key>")) + "  Extension field by JSON key, e.g. ext.sound_name\n"
 	s += "\n"
 
 	s += sectionStyle.Render("Operators") + "\n"
@@ -849,52 +1394,19 @@ func (m Model) viewHelpFilters() string {
 	s += "  app=discord\n"
 	s += "  body~meeting\n"
 	s += "  urgency=critical\n"
-	s += "  timestamp<1h          " + dimStyle.Render("(last hour)") + "\n"
+	s += "  timestamp>1h          " + dimStyle.Render("(last hour)") + "\n"
+	s += "  timestamp=yesterday   " + dimStyle.Render("(all of yesterday)") + "\n"
+	s += "  timestamp=2026-10-01..2026-10-07\n"
 	s += "  app=slack,seen=false  " + dimStyle.Render("(multiple)") + "\n"
+	s += "  tag=work,starred=true\n"
+	s += "  progress>=50          " + dimStyle.Render("(half done)") + "\n"
+	s += "  ext.sound_name=bell\n"
 
-	s += "\n" + dimStyle.Render("←/→ or h/l: switch pages  ?/esc: close")
+	s += "\n" + dimStyle.Render("←/→ or h/l: switch pages  "+shortKey(m.keys.Help)+"/"+shortKey(m.keys.Back)+": close")
 
 	return s
 }
 
-// containsIgnoreCase checks if s contains substr (case-insensitive).
-func containsIgnoreCase(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			len(substr) == 0 ||
-			findIgnoreCase(s, substr))
-}
-
-func findIgnoreCase(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if equalFoldAt(s, i, substr) {
-			return true
-		}
-	}
-	return false
-}
-
-func equalFoldAt(s string, start int, substr string) bool {
-	for j := 0; j < len(substr); j++ {
-		c1 := s[start+j]
-		c2 := substr[j]
-		if c1 == c2 {
-			continue
-		}
-		// Simple ASCII case folding
-		if c1 >= 'A' && c1 <= 'Z' {
-			c1 += 32
-		}
-		if c2 >= 'A' && c2 <= 'Z' {
-			c2 += 32
-		}
-		if c1 != c2 {
-			return false
-		}
-	}
-	return true
-}
-
 // keybind represents a single keybind with priority for the status bar.
 type keybind struct {
 	key      string
@@ -903,48 +1415,119 @@ type keybind struct {
 }
 
 // buildKeybindBar builds a keybind bar that fits within the given width.
-// mode determines which keybinds are shown: "list", "detail", "search"
+// mode determines which keybinds are shown: "list", "detail", "search", "links", "control", "calendar"
 func (m Model) buildKeybindBar(width int, mode string) string {
-	style := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	style := fg(m.colors.Label)
+	keyStyle := fg(m.colors.Key)
 
+	k := m.keys
 	var binds []keybind
+	var prefix string
 
 	switch mode {
 	case "list":
 		// Priority order for list mode (most important first)
 		binds = []keybind{
-			{"q", "quit", 1},
-			{"enter", "view", 2},
-			{"?", "help", 3},
-			{"/", "search", 4},
-			{"d", "dismiss", 5},
-			{"a", "all", 6},
-			{"c", "copy", 7},
-			{"s", "summary", 8},
-			{"D", "delete", 9},
-			{"r", "refresh", 10},
+			{shortKey(k.Quit), "quit", 1},
+			{shortKey(k.Enter), "view", 2},
+			{shortKey(k.Help), "help", 3},
+			{shortKey(k.Search), "search", 4},
+			{shortKey(k.Dismiss), "dismiss", 5},
+			{shortKey(k.ToggleDismissed), "all", 6},
+			{shortKey(k.Copy), "copy", 7},
+			{shortKey(k.CopySummary), "summary", 8},
+			{shortKey(k.HardDelete), "delete", 9},
+			{shortKey(k.Snooze), "snooze", 10},
+			{shortKey(k.Star), "star", 11},
+			{shortKey(k.Tag), "tag", 12},
+			{shortKey(k.Note), "note", 13},
+			{shortKey(k.Mark), "mark", 14},
+			{shortKey(k.Refresh), "refresh", 15},
+			{shortKey(k.Stats), "stats", 16},
+			{shortKey(k.TogglePreview), "preview", 17},
+			{shortKey(k.OpenLink), "open link", 18},
+			{shortKey(k.CopyCode), "copy code", 19},
+			{shortKey(k.Control), "histuid", 20},
+			{shortKey(k.Timeline), "timeline", 21},
+			{shortKey(k.Calendar), "heatmap", 22},
+		}
+		if marked := len(m.markedNotifications()); marked > 0 || m.sel.anchor >= 0 {
+			// Lead with the selection and what applies to it
+			label := fmt.Sprintf("%d selected", marked)
+			if m.sel.anchor >= 0 {
+				label += " (" + shortKey(k.MarkRange) + " to end range)"
+			}
+			prefix = fg(m.colors.Marked).Render(label)
+			binds = []keybind{
+				{shortKey(k.Back), "clear", 1},
+				{shortKey(k.Dismiss), "dismiss", 2},
+				{shortKey(k.HardDelete), "delete", 3},
+				{shortKey(k.MarkSeen), "seen", 4},
+				{shortKey(k.Tag), "tag", 5},
+				{shortKey(k.CopyAllJSON), "copy JSON", 6},
+				{shortKey(k.Export), "export", 7},
+				{shortKey(k.Snooze), "snooze", 8},
+				{shortKey(k.Star), "star", 9},
+			}
 		}
 	case "detail":
 		binds = []keybind{
-			{"q", "quit", 1},
-			{"esc", "back", 2},
-			{"/", "search", 3},
-			{"c", "copy body", 4},
-			{"s", "copy summary", 5},
-			{"j/k", "scroll", 6},
+			{shortKey(k.Quit), "quit", 1},
+			{shortKey(k.Back), "back", 2},
+			{shortKey(k.Search), "search", 3},
+			{shortKey(k.Copy), "copy body", 4},
+			{shortKey(k.CopySummary), "copy summary", 5},
+			{shortKey(k.OpenLink), "open link", 6},
+			{shortKey(k.CopyCode), "copy code", 7},
+			{shortKey(k.Down) + "/" + shortKey(k.Up), "scroll", 8},
+		}
+	case "stats":
+		binds = []keybind{
+			{shortKey(k.Quit), "quit", 1},
+			{shortKey(k.Back), "back", 2},
+			{shortKey(k.Refresh), "refresh", 3},
+			{shortKey(k.Down) + "/" + shortKey(k.Up), "scroll", 4},
 		}
 	case "search":
 		binds = []keybind{
 			{"enter", "view", 1},
 			{"esc", "close", 2},
 			{"↑/↓", "navigate", 3},
+			{"ctrl+a", "mark all", 4},
+		}
+	case "calendar":
+		binds = []keybind{
+			{shortKey(k.Enter), "show in list", 1},
+			{shortKey(k.Back), "back", 2},
+			{"←/→", "hour", 3},
+			{shortKey(k.Down) + "/" + shortKey(k.Up), "day", 4},
+			{shortKey(k.Refresh), "refresh", 5},
+		}
+	case "control":
+		binds = []keybind{
+			{"d", "toggle DnD", 1},
+			{"t", "DnD for…", 2},
+			{"c", "close popups", 3},
+			{"s", "show again", 4},
+			{shortKey(k.Back), "back", 5},
+		}
+	case "links":
+		binds = []keybind{
+			{shortKey(k.Enter), "open", 1},
+			{"1-9", "open by number", 2},
+			{shortKey(k.Back), "cancel", 3},
+			{shortKey(k.Down) + "/" + shortKey(k.Up), "navigate", 4},
+		}
+	case "prompt":
+		binds = []keybind{
+			{"enter", "save", 1},
+			{"esc", "cancel", 2},
 		}
 	}
 
 	// Build the bar, adding keybinds until we run out of space
 	const separator = "  "
-	result := ""
+	result := prefix
 	for _, b := range binds {
 		item := keyStyle.Render(b.key) + " " + b.desc
 		plainItem := b.key + " " + b.desc
@@ -1028,7 +1611,11 @@ func Run(opts RunOptions) error {
 	}
 
 	m := New(opts.Config, s)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	programOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if opts.Config == nil || opts.Config.TUI.Mouse {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, programOpts...)
 
 	_, err := p.Run()
 
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+// textQuery returns the search query if it is plain text, to highlight its
+// matches. Filter expressions have none.
+func (m Model) textQuery() string {
+	if isFilterExpression(m.searchQuery) {
+		return ""
+	}
+	return strings.TrimSpace(m.searchQuery)
+}
+
+// highlight renders text in style with the letters query matches in color
+// and underlined. The style's border and padding wrap the whole text.
+func highlight(text, query string, style lipgloss.Style, color lipgloss.TerminalColor) string {
+	var positions []int
+	if query != "" {
+		positions = core.MatchPositions(text, query)
+	}
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+
+	base := style.Inline(true)
+	match := base.Foreground(color).Underline(true)
+	var sb strings.Builder
+	var run []rune
+	matched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if matched {
+			sb.WriteString(match.Render(string(run)))
+		} else {
+			sb.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	next := 0
+	for i, r := range []rune(text) {
+		isMatch := next < len(positions) && positions[next] == i
+		if isMatch {
+			next++
+		}
+		if isMatch != matched {
+			flush()
+			matched = isMatch
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return style.Render(sb.String())
+}
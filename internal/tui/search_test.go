@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func TestSearch_RanksFuzzyMatches(t *testing.T) {
+	s := store.NewStore(nil)
+	t.Cleanup(func() { _ = s.Close() })
+	now := time.Now().Unix()
+	for _, n := range []model.Notification{
+		{HistuiID: "body", AppName: "ci", Summary: "Pipeline", Body: "deploy finished", Timestamp: now},
+		{HistuiID: "typo", AppName: "ci", Summary: "Depoly failed", Timestamp: now - 1},
+		{HistuiID: "other", AppName: "mail", Summary: "Lunch?", Timestamp: now - 2},
+		{HistuiID: "summary", AppName: "ci", Summary: "Deploy done", Timestamp: now - 3},
+	} {
+		require.NoError(t, s.Add(n))
+	}
+	m := New(nil, s)
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = update(t, m, loadNotificationsMsg{})
+
+	m = update(t, m, runes("/"))
+	for _, r := range "deploy" {
+		m = update(t, m, runes(string(r)))
+	}
+	assert.Equal(t, []string{"summary", "body", "typo"}, ids(m.listedNotifications()))
+	assert.Equal(t, "deploy", m.textQuery())
+
+	// Filter expressions keep the list's order and highlight nothing
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = update(t, m, runes("/"))
+	for _, r := range "app=ci" {
+		m = update(t, m, runes(string(r)))
+	}
+	assert.Equal(t, []string{"body", "typo", "summary"}, ids(m.listedNotifications()))
+	assert.Empty(t, m.textQuery())
+}
+
+func TestHighlight(t *testing.T) {
+	style := lipgloss.NewStyle().PaddingLeft(2)
+	out := highlight("Team meeting", "meetign", style, lipgloss.Color("10"))
+	// Tests have no terminal, so no colors either
+	assert.Equal(t, "  Team meeting", out)
+	assert.Equal(t, style.Render("Lunch"), highlight("Lunch", "meeting", style, lipgloss.Color("10")))
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+// selection tracks the notifications marked for bulk actions. It is shared
+// by pointer between the model and the list delegate, which draws the marks.
+type selection struct {
+	marked map[string]bool // histui_id -> marked
+	anchor int             // List index where a V range starts; -1 if none
+}
+
+func newSelection() *selection {
+	return &selection{marked: make(map[string]bool), anchor: -1}
+}
+
+// contains reports whether the item at index is marked, directly or by the
+// open range ending at the cursor.
+func (s *selection) contains(id string, index, cursor int) bool {
+	if s.marked[id] {
+		return true
+	}
+	if s.anchor < 0 {
+		return false
+	}
+	return index >= min(s.anchor, cursor) && index <= max(s.anchor, cursor)
+}
+
+// reset unmarks everything and closes any open range.
+func (s *selection) reset() {
+	clear(s.marked)
+	s.anchor = -1
+}
+
+// listedNotifications returns the notifications currently in the list.
+func (m Model) listedNotifications() []model.Notification {
+	items := m.list.Items()
+	notifications := make([]model.Notification, 0, len(items))
+	for _, item := range items {
+		if ni, ok := item.(notificationItem); ok {
+			notifications = append(notifications, ni.notification)
+		}
+	}
+	return notifications
+}
+
+// markedNotifications returns the listed notifications that are marked,
+// in list order.
+func (m Model) markedNotifications() []model.Notification {
+	var marked []model.Notification
+	for i, n := range m.listedNotifications() {
+		if m.sel.contains(n.HistuiID, i, m.list.Index()) {
+			marked = append(marked, n)
+		}
+	}
+	return marked
+}
+
+// targets returns the notifications an action applies to: the marked ones,
+// or the one under the cursor if none are marked.
+func (m Model) targets() []model.Notification {
+	if marked := m.markedNotifications(); len(marked) > 0 {
+		return marked
+	}
+	if item, ok := m.list.SelectedItem().(notificationItem); ok {
+		return []model.Notification{item.notification}
+	}
+	return nil
+}
+
+// exportTargets returns the notifications copied or exported: the marked
+// ones, or everything listed if none are marked.
+func (m Model) exportTargets() []model.Notification {
+	if marked := m.markedNotifications(); len(marked) > 0 {
+		return marked
+	}
+	return m.listedNotifications()
+}
+
+// toggleMark marks or unmarks the item under the cursor and moves down.
+func (m Model) toggleMark() (tea.Model, tea.Cmd) {
+	if item, ok := m.list.SelectedItem().(notificationItem); ok {
+		id := item.notification.HistuiID
+		if m.sel.marked[id] {
+			delete(m.sel.marked, id)
+		} else {
+			m.sel.marked[id] = true
+		}
+		m.list.CursorDown()
+	}
+	return m, nil
+}
+
+// toggleRange starts a range at the cursor, or marks everything between
+// the start and the cursor if one is open.
+func (m Model) toggleRange() (tea.Model, tea.Cmd) {
+	if m.sel.anchor < 0 {
+		if len(m.list.Items()) > 0 {
+			m.sel.anchor = m.list.Index()
+		}
+		return m, nil
+	}
+
+	for _, n := range m.markedNotifications() {
+		m.sel.marked[n.HistuiID] = true
+	}
+	m.sel.anchor = -1
+	return m, nil
+}
+
+// markAll marks every listed notification, or unmarks them all if they
+// already are.
+func (m Model) markAll() (tea.Model, tea.Cmd) {
+	listed := m.listedNotifications()
+	if len(listed) > 0 && len(m.markedNotifications()) == len(listed) {
+		m.sel.reset()
+		return m, nil
+	}
+	m.sel.anchor = -1
+	for _, n := range listed {
+		m.sel.marked[n.HistuiID] = true
+	}
+	return m, func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("%d selected", len(listed)), isErr: false}
+	}
+}
+
+// applyToTargets applies fn to each target in a single batch.
+func (m Model) applyToTargets(targets []model.Notification, fn func(tx *store.Tx, n model.Notification), status string) (tea.Model, tea.Cmd) {
+	if len(targets) == 0 || m.store == nil {
+		return m, nil
+	}
+	return m.applyBatch(func(tx *store.Tx) error {
+		for _, n := range targets {
+			fn(tx, n)
+		}
+		return nil
+	}, status)
+}
+
+// countStatus describes an action on count notifications,
+// e.g. "Notification dismissed" or "3 notifications dismissed".
+func countStatus(count int, action string) string {
+	if count == 1 {
+		return "Notification " + action
+	}
+	return fmt.Sprintf("%d notifications %s", count, action)
+}
+
+// marshalNotifications encodes notifications as YAML for .yaml and .yml
+// paths and as indented JSON otherwise.
+func marshalNotifications(notifications []model.Notification, path string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(notifications)
+	default:
+		return json.MarshalIndent(notifications, "", "  ")
+	}
+}
+
+// defaultExportPath names an export file in the working directory.
+func defaultExportPath(now time.Time) string {
+	return "histui-export-" + now.Format("20060102-150405") + ".json"
+}
+
+// exportNotifications writes notifications to path. The file is only
+// readable by the user, like the history it comes from.
+func exportNotifications(notifications []model.Notification, path string) error {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, err := marshalNotifications(notifications, path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func newSelectionTestModel(t *testing.T, count int) (Model, *store.Store) {
+	t.Helper()
+	s := store.NewStore(nil)
+	t.Cleanup(func() { _ = s.Close() })
+	for i := range count {
+		require.NoError(t, s.Add(model.Notification{
+			HistuiID:  fmt.Sprintf("sel%d", i),
+			AppName:   "test",
+			Summary:   fmt.Sprintf("Summary %d", i),
+			Timestamp: time.Now().Unix() - int64(i),
+		}))
+	}
+
+	m := New(nil, s)
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 60})
+	m = update(t, m, loadNotificationsMsg{})
+	return m, s
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func ids(ns []model.Notification) []string {
+	out := make([]string, len(ns))
+	for i, n := range ns {
+		out[i] = n.HistuiID
+	}
+	return out
+}
+
+func TestSelection_MarkAndBulkDismiss(t *testing.T) {
+	m, s := newSelectionTestModel(t, 6)
+	listed := ids(m.listedNotifications())
+	require.Len(t, listed, 6)
+
+	// Without marks, actions target the cursor item
+	assert.Equal(t, listed[:1], ids(m.targets()))
+
+	// space marks and moves down
+	m = update(t, m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, listed[:1], ids(m.markedNotifications()))
+	assert.Equal(t, 1, m.list.Index())
+
+	// V opens a range that follows the cursor until V closes it
+	m = update(t, m, runes("V"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, listed[:4], ids(m.markedNotifications()))
+	assert.Contains(t, m.buildKeybindBar(200, "list"), "4 selected")
+	m = update(t, m, runes("V"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, listed[:4], ids(m.targets()))
+
+	events := s.Subscribe()
+	m = update(t, m, runes("d"))
+	for _, id := range listed[:4] {
+		assert.True(t, s.GetByID(id).IsDismissed(), id)
+	}
+	assert.False(t, s.GetByID(listed[4]).IsDismissed())
+	assert.Empty(t, m.markedNotifications(), "marks are cleared after the action")
+
+	// One store write for the whole selection
+	assert.Equal(t, store.ChangeTypeBatch, (<-events).Type)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected extra event: %+v", event)
+	default:
+	}
+}
+
+func TestSelection_MarkAll(t *testing.T) {
+	m, s := newSelectionTestModel(t, 3)
+
+	m = update(t, m, runes("*"))
+	assert.Len(t, m.markedNotifications(), 3)
+
+	// esc clears the marks
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Empty(t, m.markedNotifications())
+
+	// ctrl+a in search marks the matches and keeps the search
+	m = update(t, m, runes("/"))
+	for _, r := range "Summary 1" {
+		m = update(t, m, runes(string(r)))
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlA})
+	assert.Equal(t, ModeList, m.mode)
+	assert.Equal(t, []string{"sel1"}, ids(m.markedNotifications()))
+
+	m = update(t, m, runes("m"))
+	assert.True(t, s.GetByID("sel1").IsSeen())
+	assert.False(t, s.GetByID("sel0").IsSeen())
+
+	// esc then clears the search
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, m.listedNotifications(), 3)
+}
+
+func TestSelection_TagPrompt(t *testing.T) {
+	m, s := newSelectionTestModel(t, 3)
+
+	m = update(t, m, runes("*"))
+	m = update(t, m, runes("t"))
+	require.Equal(t, ModePrompt, m.mode)
+	for _, r := range "work" {
+		m = update(t, m, runes(string(r)))
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	for _, id := range []string{"sel0", "sel1", "sel2"} {
+		assert.Equal(t, []string{"work"}, s.GetByID(id).HistuiTags, id)
+	}
+}
+
+func TestExportNotifications(t *testing.T) {
+	dir := t.TempDir()
+	ns := []model.Notification{{HistuiID: "x1", Summary: "one"}, {HistuiID: "x2", Summary: "two"}}
+
+	jsonPath := filepath.Join(dir, "out.json")
+	require.NoError(t, exportNotifications(ns, jsonPath))
+	data, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var fromJSON []model.Notification
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Equal(t, []string{"x1", "x2"}, ids(fromJSON))
+
+	yamlPath := filepath.Join(dir, "out.yaml")
+	require.NoError(t, exportNotifications(ns, yamlPath))
+	data, err = os.ReadFile(yamlPath)
+	require.NoError(t, err)
+	var fromYAML []map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &fromYAML))
+	assert.Len(t, fromYAML, 2)
+
+	info, err := os.Stat(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
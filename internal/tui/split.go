@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/config"
+)
+
+// Split layout thresholds. Below both, the preview is hidden and enter
+// opens the detail view as usual.
+const (
+	splitMinWidth  = 100 // Preview beside the list from this width
+	splitMinHeight = 30  // Otherwise preview below the list from this height
+	splitStep      = 5   // Percentage points per resize
+)
+
+// List item geometry, matching list.DefaultDelegate and the list's own
+// title and status bars (each followed by a blank line).
+const (
+	listHeaderLines = 4
+	listItemLines   = 3 // Two lines per item plus one of spacing
+)
+
+// splitLayout is where the preview is drawn.
+type splitLayout int
+
+const (
+	splitOff        splitLayout = iota // No preview
+	splitSideBySide                    // List left, preview right
+	splitStacked                       // List top, preview bottom
+)
+
+// splitLayout returns the layout for the terminal size, falling back to no
+// preview on small terminals.
+func (m Model) splitLayout() splitLayout {
+	switch {
+	case !m.split:
+		return splitOff
+	case m.width >= splitMinWidth:
+		return splitSideBySide
+	case m.height >= splitMinHeight:
+		return splitStacked
+	default:
+		return splitOff
+	}
+}
+
+// resize sizes the list and preview for the terminal and layout.
+func (m Model) resize() Model {
+	listWidth := m.width
+	listHeight := m.height - 2
+	if m.hasViewTabs() {
+		listHeight--
+	}
+	if m.hasStatusHeader() {
+		listHeight--
+	}
+
+	switch m.splitLayout() {
+	case splitSideBySide:
+		listWidth = m.width * m.splitRatio / 100
+		// Border and padding take two columns
+		m.preview.Width = max(m.width-listWidth-2, 0)
+		m.preview.Height = max(listHeight, 0)
+	case splitStacked:
+		previewHeight := listHeight * (100 - m.splitRatio) / 100
+		listHeight -= previewHeight
+		// The border takes a line
+		m.preview.Width = m.width
+		m.preview.Height = max(previewHeight-1, 0)
+	}
+
+	m.list.SetSize(listWidth, max(listHeight, 0))
+	m.previewID = ""
+	return m.syncPreview()
+}
+
+// syncPreview shows the highlighted notification in the preview, scrolled
+// to the top when the highlight moves.
+func (m Model) syncPreview() Model {
+	if m.splitLayout() == splitOff {
+		return m
+	}
+
+	item, ok := m.list.SelectedItem().(notificationItem)
+	if !ok {
+		m.previewID = ""
+		m.preview.SetContent(fg(m.colors.Label).Render("No notification selected"))
+		return m
+	}
+
+	// Re-render every time so edits such as tags show up at once
+	content := lipgloss.NewStyle().Width(m.preview.Width).Render(m.renderDetail(item.notification))
+	m.preview.SetContent(content)
+	if id := item.notification.HistuiID; id != m.previewID {
+		m.previewID = id
+		m.preview.GotoTop()
+	}
+	return m
+}
+
+// togglePreview shows or hides the preview.
+func (m Model) togglePreview() (tea.Model, tea.Cmd) {
+	m.split = !m.split
+	m = m.resize()
+	if m.split && m.splitLayout() == splitOff {
+		return m, func() tea.Msg {
+			return statusMsg{text: "Terminal too small for the preview", isErr: true}
+		}
+	}
+	return m, nil
+}
+
+// resizeSplit gives the list delta more percentage points of the screen.
+func (m Model) resizeSplit(delta int) (tea.Model, tea.Cmd) {
+	if m.splitLayout() == splitOff {
+		return m, nil
+	}
+	m.splitRatio = min(max(m.splitRatio+delta, config.MinSplitRatio), config.MaxSplitRatio)
+	m = m.resize()
+	return m, func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("List %d%%", m.splitRatio), isErr: false}
+	}
+}
+
+// listView renders the list, with the preview beside or below it if shown.
+func (m Model) listView() string {
+	layout := m.splitLayout()
+	content := m.list.View()
+	if m.timeline {
+		content = m.renderTimeline()
+	}
+	if layout == splitOff {
+		return content
+	}
+
+	border := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(m.colors.Label)
+	// Pad the list so the preview does not shift with its content
+	list := lipgloss.NewStyle().Width(m.list.Width()).Render(content)
+
+	if layout == splitSideBySide {
+		preview := border.BorderLeft(true).PaddingLeft(1).Render(m.preview.View())
+		return lipgloss.JoinHorizontal(lipgloss.Top, list, preview)
+	}
+	preview := border.BorderTop(true).Render(m.preview.View())
+	return lipgloss.JoinVertical(lipgloss.Left, list, preview)
+}
+
+// listTop returns the screen line where the list starts in the current mode.
+func (m Model) listTop() int {
+	top := 0
+	if m.hasStatusHeader() {
+		top++
+	}
+	switch m.mode {
+	case ModeSearch, ModePrompt:
+		// Search bar or prompt
+		return top + 1
+	case ModeList:
+		if m.hasViewTabs() {
+			return top + 1
+		}
+		return top
+	}
+	return 0
+}
+
+// inPreview reports whether a screen position is over the preview.
+func (m Model) inPreview(x, y int) bool {
+	switch m.splitLayout() {
+	case splitSideBySide:
+		return x >= m.list.Width()
+	case splitStacked:
+		return y >= m.listTop()+m.list.Height()
+	}
+	return false
+}
+
+// itemAt returns the list index of the item drawn at screen line y.
+func (m Model) itemAt(y int) (int, bool) {
+	if m.timeline {
+		return m.timelineItemAt(y)
+	}
+	offset := y - m.listTop() - listHeaderLines
+	if offset < 0 || offset%listItemLines == listItemLines-1 {
+		return 0, false
+	}
+	row := offset / listItemLines
+	if row >= m.list.Paginator.PerPage {
+		return 0, false
+	}
+	index := m.list.Paginator.Page*m.list.Paginator.PerPage + row
+	if index >= len(m.list.Items()) {
+		return 0, false
+	}
+	return index, true
+}
+
+// handleMouse selects list items on click and scrolls with the wheel.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.mode {
+	case ModeDetail, ModeStats:
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	case ModeList, ModeSearch:
+	default:
+		return m, nil
+	}
+
+	if m.inPreview(msg.X, msg.Y) {
+		m.preview, cmd = m.preview.Update(msg)
+		return m, cmd
+	}
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.list.CursorUp()
+	case tea.MouseButtonWheelDown:
+		m.list.CursorDown()
+	case tea.MouseButtonLeft:
+		index, ok := m.itemAt(msg.Y)
+		if !ok {
+			return m, nil
+		}
+		// Without a preview, clicking the highlighted item opens it
+		if index == m.list.Index() && m.mode == ModeList && m.splitLayout() == splitOff {
+			return m.openDetail()
+		}
+		m.list.Select(index)
+	}
+	return m, nil
+}
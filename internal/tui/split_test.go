@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit_PreviewFollowsCursor(t *testing.T) {
+	m, _ := newSelectionTestModel(t, 3)
+	assert.Equal(t, splitOff, m.splitLayout())
+
+	m = update(t, m, runes("p"))
+	require.Equal(t, splitSideBySide, m.splitLayout())
+	assert.Equal(t, 60, m.list.Width())
+	assert.Contains(t, m.preview.View(), "Summary 0")
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Contains(t, m.preview.View(), "Summary 1")
+	assert.Contains(t, m.View(), "│ Summary 1", "preview is drawn beside the list")
+
+	// Resizing is clamped
+	m = update(t, m, runes(">"))
+	assert.Equal(t, 66, m.list.Width())
+	for range 10 {
+		m = update(t, m, runes("<"))
+	}
+	assert.Equal(t, 24, m.list.Width())
+
+	m = update(t, m, runes("p"))
+	assert.Equal(t, splitOff, m.splitLayout())
+	assert.Equal(t, 120, m.list.Width())
+}
+
+func TestSplit_NarrowTerminals(t *testing.T) {
+	m, _ := newSelectionTestModel(t, 3)
+	m = update(t, m, runes("p"))
+
+	// Narrow but tall: the preview goes below the list
+	m = update(t, m, tea.WindowSizeMsg{Width: 80, Height: 40})
+	require.Equal(t, splitStacked, m.splitLayout())
+	assert.Equal(t, 80, m.list.Width())
+	assert.Equal(t, 19, m.list.Height())
+	assert.Equal(t, 18, m.preview.Height)
+
+	// Small: back to the list, and enter opens the detail view
+	m = update(t, m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	assert.Equal(t, splitOff, m.splitLayout())
+	assert.False(t, strings.Contains(m.View(), "───"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ModeDetail, m.mode)
+}
+
+func TestSplit_Mouse(t *testing.T) {
+	m, _ := newSelectionTestModel(t, 5)
+	press := func(button tea.MouseButton, x, y int) tea.MouseMsg {
+		return tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress}
+	}
+
+	// Items start below the title and status bars, three lines apart
+	m = update(t, m, press(tea.MouseButtonLeft, 5, listHeaderLines+2*listItemLines))
+	assert.Equal(t, 2, m.list.Index())
+	m = update(t, m, press(tea.MouseButtonLeft, 5, listHeaderLines+2*listItemLines+2))
+	assert.Equal(t, 2, m.list.Index(), "spacing lines are ignored")
+
+	m = update(t, m, press(tea.MouseButtonWheelDown, 5, 10))
+	assert.Equal(t, 3, m.list.Index())
+	m = update(t, m, press(tea.MouseButtonWheelUp, 5, 10))
+	assert.Equal(t, 2, m.list.Index())
+
+	// Clicking the highlighted item opens it
+	m = update(t, m, press(tea.MouseButtonLeft, 5, listHeaderLines+2*listItemLines))
+	assert.Equal(t, ModeDetail, m.mode)
+	assert.Equal(t, "sel2", m.selected.HistuiID)
+
+	// With the preview shown, the wheel over it scrolls the preview
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = update(t, m, runes("p"))
+	m = update(t, m, press(tea.MouseButtonWheelDown, 100, 10))
+	assert.Equal(t, 2, m.list.Index())
+}
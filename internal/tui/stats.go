@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/core"
+)
+
+// statsTopN limits the app and category sections on the stats page.
+const statsTopN = 8
+
+// handleStatsKey handles keys on the stats page.
+func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Stats):
+		m.mode = ModeList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.notifications = m.fetchNotifications()
+		m.list.SetItems(m.buildListItems())
+		m.viewport.SetContent(m.renderStats())
+		return m, nil
+	}
+
+	// Pass to viewport for scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// renderStats renders aggregate statistics for the loaded notifications.
+func (m Model) renderStats() string {
+	st := core.ComputeStats(m.notifications)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.colors.Header)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(m.colors.Label)
+
+	barStyle := lipgloss.NewStyle().
+		Foreground(m.colors.Key)
+
+	barWidth := max(m.width/3, 10)
+
+	var sb strings.Builder
+
+	sb.WriteString(headerStyle.Render("Overview") + "\n")
+	sb.WriteString(labelStyle.Render("Total: ") + fmt.Sprintf("%d", st.Total) + "\n")
+	sb.WriteString(labelStyle.Render("Dismissed: ") + fmt.Sprintf("%d", st.Dismissed) + "  ")
+	sb.WriteString(labelStyle.Render("Seen: ") + fmt.Sprintf("%d", st.Seen) + "  ")
+	sb.WriteString(labelStyle.Render("Ignored: ") + fmt.Sprintf("%d", st.Ignored) + "\n")
+	sb.WriteString(labelStyle.Render("Dismiss ratio: ") + fmt.Sprintf("%.0f%%", st.DismissRatio*100) + "\n")
+	median := "-"
+	if st.MedianTimeToDismiss > 0 {
+		median = st.MedianTimeToDismiss.Round(time.Second).String()
+	}
+	sb.WriteString(labelStyle.Render("Median time to dismiss: ") + median + "\n")
+
+	writeSection := func(title string, buckets []core.StatsBucket, limit int) {
+		sb.WriteString("\n" + headerStyle.Render(title) + "\n")
+		if len(buckets) == 0 {
+			sb.WriteString(labelStyle.Render("  (none)") + "\n")
+			return
+		}
+		if limit > 0 && len(buckets) > limit {
+			buckets = buckets[:limit]
+		}
+		nameWidth, maxCount := 0, 0
+		for _, b := range buckets {
+			nameWidth = max(nameWidth, len(b.Name))
+			maxCount = max(maxCount, b.Count)
+		}
+		for _, b := range buckets {
+			fmt.Fprintf(&sb, "  %-*s %s %d\n", nameWidth, b.Name,
+				barStyle.Render(core.Bar(b.Count, maxCount, barWidth)), b.Count)
+		}
+	}
+
+	writeSection("By app", st.ByApp, statsTopN)
+	writeSection("By urgency", st.ByUrgency, 0)
+	writeSection("By category", st.ByCategory, statsTopN)
+
+	sb.WriteString("\n" + headerStyle.Render("By hour") + "\n")
+	sb.WriteString("  " + barStyle.Render(core.Sparkline(st.ByHour[:])) + "\n")
+	sb.WriteString(labelStyle.Render("  0     6     12    18   23") + "\n")
+
+	sb.WriteString("\n" + headerStyle.Render("By weekday") + "\n")
+	maxDay := 0
+	for _, c := range st.ByWeekday {
+		maxDay = max(maxDay, c)
+	}
+	for day, count := range st.ByWeekday {
+		fmt.Fprintf(&sb, "  %s %s %d\n", core.WeekdayNames[day],
+			barStyle.Render(core.Bar(count, maxDay, barWidth)), count)
+	}
+
+	return sb.String()
+}
+
+func (m Model) viewStats() string {
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1)
+
+	header := headerStyle.Render("Notification Statistics")
+
+	return header + "\n" + m.viewport.View() + "\n" + m.buildKeybindBar(m.width, "stats")
+}
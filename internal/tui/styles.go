@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette holds the TUI colors by role, as resolved from the [tui.colors]
+// config.
+type palette struct {
+	Title               lipgloss.TerminalColor
+	Description         lipgloss.TerminalColor
+	Selected            lipgloss.TerminalColor
+	SelectedDescription lipgloss.TerminalColor
+	Dismissed           lipgloss.TerminalColor
+	Marked              lipgloss.TerminalColor
+	Header              lipgloss.TerminalColor
+	Label               lipgloss.TerminalColor
+	Key                 lipgloss.TerminalColor
+	Status              lipgloss.TerminalColor
+	Error               lipgloss.TerminalColor
+}
+
+// defaultPalette returns the colors of the auto preset: the list delegate's
+// own, which adapt to the terminal background, and ANSI colors elsewhere.
+func defaultPalette() palette {
+	d := list.NewDefaultDelegate()
+	return palette{
+		Title:               d.Styles.NormalTitle.GetForeground(),
+		Description:         d.Styles.NormalDesc.GetForeground(),
+		Selected:            d.Styles.SelectedTitle.GetForeground(),
+		SelectedDescription: d.Styles.SelectedDesc.GetForeground(),
+		Dismissed:           lipgloss.Color("8"),
+		Marked:              lipgloss.Color("11"),
+		Header:              lipgloss.Color("12"),
+		Label:               lipgloss.Color("8"),
+		Key:                 lipgloss.Color("10"),
+		Status:              lipgloss.Color("7"),
+		Error:               lipgloss.Color("9"),
+	}
+}
+
+// newPalette returns the default palette with colors replaced by role
+// (the [tui.colors] names).
+func newPalette(colors map[string]string) palette {
+	p := defaultPalette()
+	roles := map[string]*lipgloss.TerminalColor{
+		"title":                &p.Title,
+		"description":          &p.Description,
+		"selected":             &p.Selected,
+		"selected_description": &p.SelectedDescription,
+		"dismissed":            &p.Dismissed,
+		"marked":               &p.Marked,
+		"header":               &p.Header,
+		"label":                &p.Label,
+		"key":                  &p.Key,
+		"status":               &p.Status,
+		"error":                &p.Error,
+	}
+	for role, color := range colors {
+		if c, ok := roles[role]; ok {
+			*c = lipgloss.Color(color)
+		}
+	}
+	return p
+}
+
+// fg returns a style with the given foreground color.
+func fg(c lipgloss.TerminalColor) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(c)
+}
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// listTitle is the list's title when no time window is picked.
+const listTitle = "Notification History"
+
+// timelineHeaderLines is the title and count above the timeline.
+const timelineHeaderLines = 2
+
+// timeWindow limits the list to notifications from start up to end.
+type timeWindow struct {
+	start, end time.Time
+}
+
+// label describes the window: "Today" for a whole day, "Yesterday
+// 09:00–10:00" for an hour.
+func (w timeWindow) label(now time.Time) string {
+	day := dayLabel(w.start, now)
+	if w.end.Equal(w.start.AddDate(0, 0, 1)) {
+		return day
+	}
+	return fmt.Sprintf("%s %s–%s", day, w.start.Format("15:04"), w.end.Format("15:04"))
+}
+
+// dayStart returns midnight at the start of t's day.
+func dayStart(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// dayLabel names t's day relative to now: "Today", "Yesterday" or
+// "Mon 12 Oct", with the year for other years.
+func dayLabel(t, now time.Time) string {
+	day, today := dayStart(t), dayStart(now)
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case day.Year() != today.Year():
+		return day.Format("Mon 2 Jan 2006")
+	}
+	return day.Format("Mon 2 Jan")
+}
+
+// setWindow limits the list to w, or shows everything again if w is nil.
+func (m Model) setWindow(w *timeWindow) Model {
+	m.window = w
+	m.list.Title = listTitle
+	if w != nil {
+		m.list.Title += " · " + w.label(time.Now())
+	}
+	m.list.SetItems(m.buildListItems())
+	m.list.ResetSelected()
+	return m
+}
+
+// toggleTimeline switches the list between plain and grouped by day.
+func (m Model) toggleTimeline() (tea.Model, tea.Cmd) {
+	m.timeline = !m.timeline
+	text := "Timeline off"
+	if m.timeline {
+		text = "Timeline: grouped by day"
+	}
+	return m, func() tea.Msg {
+		return statusMsg{text: text, isErr: false}
+	}
+}
+
+// timelineLine is a line of the timeline: a day header, or the list item
+// at index.
+type timelineLine struct {
+	text  string
+	index int // -1 for day headers
+}
+
+// timelineLines lays out the listed notifications under a header for each
+// day.
+func (m Model) timelineLines(now time.Time) []timelineLine {
+	notifications := m.listedNotifications()
+	width := m.list.Width()
+
+	perDay := make(map[time.Time]int)
+	for i := range notifications {
+		perDay[dayStart(notifications[i].TimestampTime())]++
+	}
+
+	var lines []timelineLine
+	var lastDay time.Time
+	for i := range notifications {
+		ts := notifications[i].TimestampTime()
+		if day := dayStart(ts); !day.Equal(lastDay) {
+			lastDay = day
+			lines = append(lines, timelineLine{text: m.renderDayHeader(dayLabel(ts, now), perDay[day], width), index: -1})
+		}
+		lines = append(lines, timelineLine{text: m.renderTimelineItem(notifications[i], i, width), index: i})
+	}
+	return lines
+}
+
+// renderDayHeader renders a day separator: "Today · 12 ─────".
+func (m Model) renderDayHeader(label string, count int, width int) string {
+	text := fmt.Sprintf("%s · %d ", label, count)
+	rule := strings.Repeat("─", max(width-lipgloss.Width(text), 0))
+	return lipgloss.NewStyle().Bold(true).Foreground(m.colors.Header).Render(label) +
+		fg(m.colors.Label).Render(strings.TrimPrefix(text, label)+rule)
+}
+
+// renderTimelineItem renders a notification as one line: time, app and
+// summary, with the list's markers.
+func (m Model) renderTimelineItem(n model.Notification, index, width int) string {
+	isSelected := index == m.list.Index()
+	isMarked := m.sel.contains(n.HistuiID, index, m.list.Index())
+
+	style := fg(m.colors.Title)
+	switch {
+	case isMarked:
+		style = style.Bold(true).Foreground(m.colors.Marked)
+	case n.IsDismissed():
+		style = fg(m.colors.Dismissed)
+	}
+	if isSelected {
+		style = style.Foreground(m.colors.Selected)
+	}
+
+	prefix := "  "
+	if isSelected {
+		prefix = "> "
+	}
+	if isMarked {
+		prefix += "● "
+	}
+	if n.IsStarred() {
+		prefix += "★ "
+	}
+	switch {
+	case n.IsSnoozed():
+		prefix += "[z] "
+	case n.IsDismissed():
+		prefix += "[d] "
+	}
+
+	clock := n.TimestampTime().Format("15:04") + " "
+	text := fmt.Sprintf("%-12s %s", truncate(n.AppName, 12), n.Summary)
+	text = truncate(text, max(width-lipgloss.Width(prefix+clock), 1))
+	return style.Render(prefix) + fg(m.colors.Label).Render(clock) + highlight(text, m.textQuery(), style, m.colors.Key)
+}
+
+// countNotifications returns "1 notification" or "n notifications".
+func countNotifications(n int) string {
+	if n == 1 {
+		return "1 notification"
+	}
+	return fmt.Sprintf("%d notifications", n)
+}
+
+// timelineOffset returns the first line to draw so the cursor is visible,
+// with a third of the space above it where possible.
+func timelineOffset(lines []timelineLine, cursor, avail int) int {
+	at := 0
+	for j, l := range lines {
+		if l.index == cursor {
+			at = j
+			break
+		}
+	}
+	return max(min(at-avail/3, len(lines)-avail), 0)
+}
+
+// renderTimeline renders the listed notifications grouped by day, in the
+// space the list would take.
+func (m Model) renderTimeline() string {
+	height := m.list.Height()
+	out := []string{
+		m.list.Styles.Title.Render(m.list.Title),
+		fg(m.colors.Label).Render(" " + countNotifications(len(m.list.Items()))),
+	}
+
+	avail := height - timelineHeaderLines
+	lines := m.timelineLines(time.Now())
+	start := timelineOffset(lines, m.list.Index(), avail)
+	for j := start; j < len(lines) && j < start+avail; j++ {
+		out = append(out, lines[j].text)
+	}
+	for len(out) < height {
+		out = append(out, "")
+	}
+	return strings.Join(out[:max(height, 0)], "\n")
+}
+
+// timelineItemAt returns the list index of the item drawn at screen line y
+// in the timeline.
+func (m Model) timelineItemAt(y int) (int, bool) {
+	row := y - m.listTop() - timelineHeaderLines
+	avail := m.list.Height() - timelineHeaderLines
+	if row < 0 || row >= avail {
+		return 0, false
+	}
+	lines := m.timelineLines(time.Now())
+	j := timelineOffset(lines, m.list.Index(), avail) + row
+	if j >= len(lines) || lines[j].index < 0 {
+		return 0, false
+	}
+	return lines[j].index, true
+}
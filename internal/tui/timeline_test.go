@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/histui/internal/model"
+	"github.com/jmylchreest/histui/internal/store"
+)
+
+func TestDayLabel(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.Local)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-10 * time.Hour), "Today"},
+		{time.Date(2026, 10, 13, 23, 59, 0, 0, time.Local), "Yesterday"},
+		{time.Date(2026, 10, 12, 8, 0, 0, 0, time.Local), "Mon 12 Oct"},
+		{time.Date(2025, 12, 31, 8, 0, 0, 0, time.Local), "Wed 31 Dec 2025"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, dayLabel(tt.t, now), tt.t)
+	}
+
+	hour := cellWindow(1, 9, now)
+	assert.Equal(t, "Yesterday 09:00–10:00", hour.label(now))
+	assert.Equal(t, "Today", cellWindow(0, calendarDayColumn, now).label(now))
+}
+
+func TestHeatmap(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.Local)
+	at := func(day, hour int) model.Notification {
+		ts := time.Date(2026, 10, 14-day, hour, 15, 0, 0, time.Local)
+		return model.Notification{Timestamp: ts.Unix()}
+	}
+	counts := heatmap([]model.Notification{
+		at(0, 9), at(0, 9), at(2, 23), at(7, 0),
+		{Timestamp: now.Add(time.Hour).Unix()}, // Later today
+	}, now, 3)
+
+	require.Len(t, counts, 3)
+	assert.Equal(t, 2, counts[0][9])
+	assert.Equal(t, 1, counts[0][11])
+	assert.Equal(t, 1, counts[2][23])
+
+	assert.Equal(t, 0, heatLevel(0, 10))
+	assert.Equal(t, 1, heatLevel(1, 10))
+	assert.Equal(t, 4, heatLevel(10, 10))
+}
+
+// newTimelineTestModel returns a model with notifications at the given
+// offsets from 10:00 yesterday, newest first.
+func newTimelineTestModel(t *testing.T, offsets ...time.Duration) Model {
+	t.Helper()
+	s := store.NewStore(nil)
+	t.Cleanup(func() { _ = s.Close() })
+	base := dayStart(time.Now()).AddDate(0, 0, -1).Add(10 * time.Hour)
+	for i, off := range offsets {
+		require.NoError(t, s.Add(model.Notification{
+			HistuiID:  fmt.Sprintf("tl%d", i),
+			AppName:   "test",
+			Summary:   fmt.Sprintf("Summary %d", i),
+			Timestamp: base.Add(off).Unix(),
+		}))
+	}
+	m := New(nil, s)
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	return update(t, m, loadNotificationsMsg{})
+}
+
+func TestTimeline_GroupsByDay(t *testing.T) {
+	m := newTimelineTestModel(t, 20*time.Hour, 0, 30*time.Minute, -24*time.Hour)
+	assert.False(t, m.timeline)
+
+	m = update(t, m, runes("T"))
+	require.True(t, m.timeline)
+	view := m.View()
+	assert.Contains(t, view, "Today · 1 ─")
+	assert.Contains(t, view, "Yesterday · 2 ─")
+	assert.Contains(t, view, dayLabel(time.Now().AddDate(0, 0, -2), time.Now())+" · 1 ─")
+	assert.Contains(t, view, "> 06:00 test         Summary 0")
+
+	// Items are the list's, so moving and opening work as before
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Contains(t, m.View(), "> 10:30 test         Summary 2")
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, ModeDetail, m.mode)
+	assert.Equal(t, "tl2", m.selected.HistuiID)
+
+	// Clicking skips day headers: line 4 is yesterday's header
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	_, ok := m.itemAt(4)
+	assert.False(t, ok)
+	index, ok := m.itemAt(6)
+	require.True(t, ok)
+	assert.Equal(t, 2, index)
+}
+
+func TestCalendar_PicksWindow(t *testing.T) {
+	m := newTimelineTestModel(t, 0, 30*time.Minute, 5*time.Hour, 20*time.Hour)
+
+	m = update(t, m, runes("M"))
+	require.Equal(t, ModeCalendar, m.mode)
+	assert.Equal(t, time.Now().Hour(), m.calHour)
+	assert.Contains(t, m.View(), "Activity by day and hour")
+
+	// Yesterday 10:00-11:00
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	for m.calHour > 10 {
+		m = update(t, m, runes("h"))
+	}
+	for m.calHour < 10 {
+		m = update(t, m, runes("l"))
+	}
+	assert.Contains(t, m.View(), "Yesterday 10:00–11:00: 2 notifications")
+
+	m, msg := press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, ModeList, m.mode)
+	assert.Equal(t, []string{"tl1", "tl0"}, ids(m.listedNotifications()))
+	assert.Contains(t, m.list.Title, "Yesterday 10:00–11:00")
+	assert.Equal(t, statusMsg{text: "Yesterday 10:00–11:00: 2 notifications (esc shows all)"}, msg)
+
+	// The whole day
+	m = update(t, m, runes("M"))
+	assert.Equal(t, 1, m.calDay, "the cursor stays on the picked window")
+	for m.calHour < calendarDayColumn {
+		m = update(t, m, runes("l"))
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, m.listedNotifications(), 3)
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, m.window)
+	assert.Equal(t, listTitle, m.list.Title)
+	assert.Len(t, m.listedNotifications(), 4)
+}
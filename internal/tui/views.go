@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jmylchreest/histui/internal/core"
+	"github.com/jmylchreest/histui/internal/model"
+)
+
+// hasViewTabs reports whether any saved views are configured.
+func (m Model) hasViewTabs() bool {
+	return len(m.views) > 1
+}
+
+// activeViewName returns the name of the active view ("" = all).
+func (m Model) activeViewName() string {
+	if m.viewIndex < 0 || m.viewIndex >= len(m.views) {
+		return ""
+	}
+	return m.views[m.viewIndex]
+}
+
+// switchView moves to the next (delta=1) or previous (delta=-1) view tab.
+func (m Model) switchView(delta int) (tea.Model, tea.Cmd) {
+	if !m.hasViewTabs() {
+		return m, nil
+	}
+
+	m.viewIndex = (m.viewIndex + delta + len(m.views)) % len(m.views)
+	m.list.SetItems(m.buildListItems())
+	m.list.ResetSelected()
+
+	name := m.activeViewName()
+	if name == "" {
+		name = "all"
+	}
+	return m, func() tea.Msg {
+		return statusMsg{text: "View: " + name, isErr: false}
+	}
+}
+
+// applyActiveView filters, sorts and limits notifications using the active view.
+func (m Model) applyActiveView(notifications []model.Notification) []model.Notification {
+	name := m.activeViewName()
+	if name == "" || m.cfg == nil {
+		return notifications
+	}
+
+	view, err := m.cfg.View(name)
+	if err != nil {
+		return notifications
+	}
+
+	expr, err := view.FilterExpr()
+	if err != nil {
+		return notifications
+	}
+
+	// Copy before sorting so the cached slice keeps its order
+	result := append([]model.Notification(nil), core.FilterWithExpr(notifications, expr)...)
+	core.Sort(result, view.SortOptions())
+	if view.Limit > 0 && len(result) > view.Limit {
+		result = result[:view.Limit]
+	}
+	return result
+}
+
+// renderViewTabs renders the saved view tab bar.
+func (m Model) renderViewTabs() string {
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.colors.Header).
+		Padding(0, 1)
+
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(m.colors.Label).
+		Padding(0, 1)
+
+	tabs := make([]string, 0, len(m.views))
+	for i, name := range m.views {
+		label := name
+		if label == "" {
+			label = "All"
+		}
+		if i == m.viewIndex {
+			tabs = append(tabs, activeStyle.Render(fmt.Sprintf("[%s]", label)))
+		} else {
+			tabs = append(tabs, inactiveStyle.Render(label))
+		}
+	}
+	return strings.Join(tabs, "")
+}